@@ -0,0 +1,202 @@
+// address/address_test.go
+package address_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"usermanagement/address"
+	"usermanagement/model"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		country, zip string
+		wantCountry  string
+		wantZip      string
+	}{
+		{"usa", "90210", "United States", "90210"},
+		{"US", "90210-1234", "United States", "90210-1234"},
+		{"uk", "sw1a1aa", "United Kingdom", "SW1A 1AA"},
+		{"Great  Britain", "ec1a 1bb", "United Kingdom", "EC1A 1BB"},
+		{"CA", "k1a0b1", "Canada", "K1A 0B1"},
+		{"deutschland", "10115", "Germany", "10115"},
+		{"Holland", "1012js", "Netherlands", "1012 JS"},
+		{"Nippon", "1000001", "Japan", "100-0001"},
+		{"brasil", "01310 100", "Brazil", "01310-100"},
+		{"IE", "d02x285", "Ireland", "D02 X285"},
+		{"India", "110001", "India", "110001"},
+		{"za", "8001", "South Africa", "8001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantCountry+"/"+tt.zip, func(t *testing.T) {
+			got, err := address.Normalize(model.Address{Street: "1 high st", City: "  new  york ", ZipCode: tt.zip, Country: tt.country})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := model.Address{Street: "1 High Street", City: "New York", ZipCode: tt.wantZip, Country: tt.wantCountry}
+			if got != want {
+				t.Errorf("Normalize = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	tests := []struct {
+		country, zip string
+		wantErr      error
+	}{
+		{"Germany", "1011", address.ErrInvalidPostalCode},
+		{"US", "", address.ErrInvalidPostalCode},
+		{"GB", "12345", address.ErrInvalidPostalCode},
+		{"CA", "D1A 0B1", address.ErrInvalidPostalCode}, // D is not used in Canadian codes.
+		{"Atlantis", "12345", address.ErrUnknownCountry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+"/"+tt.zip, func(t *testing.T) {
+			got, err := address.Normalize(model.Address{Street: "main st", City: "x", ZipCode: tt.zip, Country: tt.country})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize = %v, want %v", err, tt.wantErr)
+			}
+			// The rest of the address is still cleaned up.
+			if got.Street != "Main Street" {
+				t.Errorf("Street = %q, want it normalized despite the error", got.Street)
+			}
+		})
+	}
+
+	_, err := address.Normalize(model.Address{ZipCode: "1011", Country: "de"})
+	var pe *address.PostalCodeError
+	if !errors.As(err, &pe) || pe.Country != "Germany" || pe.Example != "10115" {
+		t.Errorf("Normalize = %#v, want a *PostalCodeError for Germany", err)
+	}
+}
+
+func TestNormalizeStreet(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"123  main st.  apt 4b", "123 Main Street Apartment 4B"},
+		{"st mary's rd", "St Mary's Road"},
+		{"1st ave nw", "1st Ave NW"},
+		{"42 main st, #5", "42 Main Street, #5"},
+		{"10 elm st #5", "10 Elm Street #5"},
+		{"McDonald blvd", "McDonald Boulevard"},
+		{"PO BOX 12", "Po Box 12"},
+		{"5 park ln ste 200", "5 Park Lane Suite 200"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := address.NormalizeStreet(tt.in); got != tt.want {
+			t.Errorf("NormalizeStreet(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want model.Address
+	}{
+		{"one line", "123 main st, Springfield 90210, USA",
+			model.Address{Street: "123 Main Street", City: "Springfield", ZipCode: "90210", Country: "United States"}},
+		{"several lines", "Hauptstr. 5\n10115 berlin\nGermany\n",
+			model.Address{Street: "Hauptstr. 5", City: "Berlin", ZipCode: "10115", Country: "Germany"}},
+		{"code on its own", "10 downing st, London, SW1A 2AA, UK",
+			model.Address{Street: "10 Downing Street", City: "London", ZipCode: "SW1A 2AA", Country: "United Kingdom"}},
+		{"two-word code before the city", "Damrak 1, 1012 lg Amsterdam, Netherlands",
+			model.Address{Street: "Damrak 1", City: "Amsterdam", ZipCode: "1012 LG", Country: "Netherlands"}},
+		{"code before the city", "1 rue de rivoli, 75001 paris, france",
+			model.Address{Street: "1 Rue De Rivoli", City: "Paris", ZipCode: "75001", Country: "France"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := address.Parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := address.Parse("Main St, USA"); !errors.Is(err, address.ErrIncomplete) {
+		t.Errorf("Parse with two parts = %v, want ErrIncomplete", err)
+	}
+	got, err := address.Parse("1 road, atlantis city, Atlantis")
+	if !errors.Is(err, address.ErrUnknownCountry) || got.City != "Atlantis City" {
+		t.Errorf("Parse with an unknown country = %+v, %v", got, err)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		in   model.Address
+		want string
+	}{
+		{"US", model.Address{Street: "123 main st", City: "springfield", ZipCode: "90210", Country: "us"},
+			"123 Main Street\nSPRINGFIELD 90210\nUNITED STATES"},
+		{"DE", model.Address{Street: "Hauptstr. 5", City: "berlin", ZipCode: "10115", Country: "DE"},
+			"Hauptstr. 5\n10115 Berlin\nGERMANY"},
+		{"GB", model.Address{Street: "10 downing st", City: "london", ZipCode: "sw1a2aa", Country: "UK"},
+			"10 Downing Street\nLONDON\nSW1A 2AA\nUNITED KINGDOM"},
+		{"JP", model.Address{Street: "1-1 chiyoda", City: "tokyo", ZipCode: "1000001", Country: "Japan"},
+			"〒100-0001\nTokyo\n1-1 Chiyoda\nJAPAN"},
+		{"empty line dropped", model.Address{City: "rio", ZipCode: "01310-100", Country: "BR"},
+			"Rio\n01310-100\nBRAZIL"},
+		{"placeholder in a field", model.Address{Street: "{city} st", City: "{zip} town", ZipCode: "10115", Country: "DE"},
+			"{city} Street\n10115 {zip} Town\nGERMANY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := address.Label(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Label =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := address.Label(model.Address{City: "x", ZipCode: "1", Country: "DE"}); !errors.Is(err, address.ErrInvalidPostalCode) {
+		t.Errorf("Label with a bad postal code = %v, want ErrInvalidPostalCode", err)
+	}
+}
+
+func TestLookupCountrySuggestions(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Germny", []string{"Germany"}},
+		{"frnace", []string{"France"}},
+		{"unit", []string{"United Kingdom", "United States"}},
+		{"Brasill", []string{"Brazil"}},
+		{"qq", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		_, err := address.LookupCountry(tt.in)
+		var ue *address.UnknownCountryError
+		if !errors.As(err, &ue) || !errors.Is(err, address.ErrUnknownCountry) {
+			t.Fatalf("LookupCountry(%q) = %v, want an *UnknownCountryError", tt.in, err)
+		}
+		if !slices.Equal(ue.Suggestions, tt.want) {
+			t.Errorf("LookupCountry(%q) suggests %q, want %q", tt.in, ue.Suggestions, tt.want)
+		}
+	}
+
+	if c, err := address.LookupCountry("  united   STATES of america "); err != nil || c.Code != "US" {
+		t.Errorf("LookupCountry(alias) = %+v, %v", c, err)
+	}
+	if got := address.Countries(); len(got) == 0 || !slices.IsSortedFunc(got, func(a, b address.Country) int {
+		return strings.Compare(a.Name, b.Name)
+	}) {
+		t.Errorf("Countries not sorted by name: %v", got)
+	}
+}
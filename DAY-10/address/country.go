@@ -0,0 +1,30 @@
+// address/country.go
+package address // Parse, normalize, validate and format postal addresses
+
+import "usermanagement/address/country"
+
+// The country data lives in the country subpackage so that model can
+// validate postal codes against it without importing address.
+
+var (
+	ErrUnknownCountry    = country.ErrUnknownCountry
+	ErrInvalidPostalCode = country.ErrInvalidPostalCode
+)
+
+type (
+	Country             = country.Country
+	UnknownCountryError = country.UnknownCountryError
+	PostalCodeError     = country.PostalCodeError
+)
+
+// Countries returns every known country, sorted by name.
+func Countries() []Country {
+	return country.All()
+}
+
+// LookupCountry finds a country by ISO code, name or common alias, ignoring
+// case and extra spaces: "us", "USA" and "United States" are all the United
+// States. An unknown country gives an *UnknownCountryError.
+func LookupCountry(s string) (Country, error) {
+	return country.Lookup(s)
+}
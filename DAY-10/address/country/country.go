@@ -0,0 +1,204 @@
+// address/country/country.go
+package country // The per-country address data shared by address and model validation
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnknownCountry    = errors.New("country: unknown country")
+	ErrInvalidPostalCode = errors.New("country: invalid postal code")
+)
+
+// Country describes how addresses are written in one country. The data
+// comes from countries.json, embedded in the binary.
+type Country struct {
+	Code    string   `json:"code"` // ISO 3166-1 alpha-2, e.g. "US".
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+
+	// Postal is the pattern a normalized postal code must match and
+	// Example a code that does.
+	Postal  string `json:"postal"`
+	Example string `json:"example"`
+
+	// PostalSep is put between the two parts of a postal code, PostalSplit
+	// characters from the end: " " and 3 turn "SW1A1AA" into "SW1A 1AA".
+	PostalSep   string `json:"postal_sep"`
+	PostalSplit int    `json:"postal_split"`
+
+	// Label lists the lines of a mailing label. {street}, {city}, {zip}
+	// and {country} are replaced by the fields of the address; an
+	// upper-case name such as {CITY} upper-cases the field.
+	Label []string `json:"label"`
+
+	postal *regexp.Regexp
+}
+
+//go:embed countries.json
+var countriesJSON []byte
+
+var (
+	countries []*Country
+	byKey     = make(map[string]*Country) // Upper-cased code, name or alias.
+)
+
+func init() {
+	if err := json.Unmarshal(countriesJSON, &countries); err != nil {
+		panic("country: bad countries.json: " + err.Error())
+	}
+	for _, c := range countries {
+		c.postal = regexp.MustCompile(c.Postal)
+		for _, k := range append([]string{c.Code, c.Name}, c.Aliases...) {
+			byKey[key(k)] = c
+		}
+	}
+}
+
+func key(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), " "))
+}
+
+// All returns every known country, sorted by name.
+func All() []Country {
+	out := make([]Country, len(countries))
+	for i, c := range countries {
+		out[i] = *c
+	}
+	slices.SortFunc(out, func(a, b Country) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+// UnknownCountryError is returned for a country that is not in the
+// dataset. Suggestions holds the names of close matches, best first, for
+// "did you mean" messages. It wraps ErrUnknownCountry.
+type UnknownCountryError struct {
+	Input       string
+	Suggestions []string
+}
+
+func (e *UnknownCountryError) Error() string {
+	msg := fmt.Sprintf("country: unknown country %q", e.Input)
+	if len(e.Suggestions) > 0 {
+		msg += "; did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	return msg
+}
+
+func (e *UnknownCountryError) Unwrap() error {
+	return ErrUnknownCountry
+}
+
+// Lookup finds a country by ISO code, name or common alias, ignoring case
+// and extra spaces: "us", "USA" and "United States" are all the United
+// States. An unknown country gives an *UnknownCountryError.
+func Lookup(s string) (Country, error) {
+	if c, ok := byKey[key(s)]; ok {
+		return *c, nil
+	}
+	return Country{}, &UnknownCountryError{Input: s, Suggestions: suggest(key(s))}
+}
+
+// suggest returns up to three country names close to k: those starting
+// with it, then those within a few typos of a name, code or alias.
+func suggest(k string) []string {
+	if k == "" {
+		return nil
+	}
+	type match struct {
+		name string
+		dist int
+	}
+	best := make(map[string]int)
+	for name, c := range byKey {
+		d := distance(k, name)
+		if len(k) >= 3 && strings.HasPrefix(name, k) {
+			d = 0
+		}
+		if d > max(1, len([]rune(k))/3) {
+			continue
+		}
+		if old, ok := best[c.Name]; !ok || d < old {
+			best[c.Name] = d
+		}
+	}
+	var matches []match
+	for name, d := range best {
+		matches = append(matches, match{name, d})
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	var out []string
+	for _, m := range matches[:min(3, len(matches))] {
+		out = append(out, m.name)
+	}
+	return out
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// PostalCodeError is returned for a postal code that doesn't fit its
+// country. It wraps ErrInvalidPostalCode.
+type PostalCodeError struct {
+	Code    string
+	Country string
+	Example string
+}
+
+func (e *PostalCodeError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("country: missing postal code for %s (example: %s)", e.Country, e.Example)
+	}
+	return fmt.Sprintf("country: %q is not a valid postal code for %s (example: %s)", e.Code, e.Country, e.Example)
+}
+
+func (e *PostalCodeError) Unwrap() error {
+	return ErrInvalidPostalCode
+}
+
+// NormalizePostalCode upper-cases code, fixes its spacing for the country
+// and checks it against the country's pattern: "sw1a1aa" becomes
+// "SW1A 1AA" for the United Kingdom.
+func (c Country) NormalizePostalCode(code string) (string, error) {
+	norm := strings.ToUpper(strings.Join(strings.Fields(code), " "))
+	if c.PostalSep != "" {
+		compact := strings.NewReplacer(" ", "", "-", "").Replace(norm)
+		if n := len(compact); n > c.PostalSplit {
+			norm = compact[:n-c.PostalSplit] + c.PostalSep + compact[n-c.PostalSplit:]
+		}
+	}
+	if !c.postal.MatchString(norm) {
+		return "", &PostalCodeError{Code: code, Country: c.Name, Example: c.Example}
+	}
+	return norm, nil
+}
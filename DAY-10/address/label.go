@@ -0,0 +1,41 @@
+// address/label.go
+package address
+
+import (
+	"strings"
+
+	"usermanagement/model"
+)
+
+// Label formats an address for an envelope, in the layout of its country:
+//
+//	123 Main Street           Hauptstraße 5
+//	SPRINGFIELD 90210         10115 Berlin
+//	UNITED STATES             GERMANY
+//
+// The address is normalized first; if that fails, so does Label. Lines
+// left empty by missing fields are dropped.
+func Label(a model.Address) (string, error) {
+	a, err := Normalize(a)
+	if err != nil {
+		return "", err
+	}
+	c, _ := LookupCountry(a.Country)
+	// One Replacer fills every placeholder in a single pass, so a field that
+	// itself contains "{city}" is printed as it is.
+	fill := strings.NewReplacer(
+		"{street}", a.Street, "{STREET}", strings.ToUpper(a.Street),
+		"{city}", a.City, "{CITY}", strings.ToUpper(a.City),
+		"{zip}", a.ZipCode, "{ZIP}", strings.ToUpper(a.ZipCode),
+		"{country}", c.Name, "{COUNTRY}", strings.ToUpper(c.Name),
+	)
+
+	var lines []string
+	for _, tmpl := range c.Label {
+		line := fill.Replace(tmpl)
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,103 @@
+// address/normalize.go
+package address
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"usermanagement/model"
+)
+
+// streetTypes are the abbreviations expanded in the last word of a street,
+// or in the word before a unit such as "Apt 4". Elsewhere "St" is more
+// likely to be "Saint", as in "St Mary's Road", so it is left alone.
+var streetTypes = map[string]string{
+	"ST": "Street", "STR": "Street", "AVE": "Avenue", "AV": "Avenue",
+	"RD": "Road", "BLVD": "Boulevard", "DR": "Drive", "LN": "Lane",
+	"CT": "Court", "PL": "Place", "SQ": "Square", "TER": "Terrace",
+	"CRES": "Crescent", "HWY": "Highway", "PKWY": "Parkway", "CIR": "Circle",
+}
+
+// units are abbreviations expanded wherever they appear.
+var units = map[string]string{
+	"APT": "Apartment", "STE": "Suite", "FL": "Floor", "BLDG": "Building",
+}
+
+// directions stay upper-case.
+var directions = map[string]bool{"N": true, "S": true, "E": true, "W": true, "NE": true, "NW": true, "SE": true, "SW": true}
+
+var ordinal = regexp.MustCompile(`(?i)^\d+(st|nd|rd|th)$`)
+
+// Normalize cleans up an address: extra spaces are removed, the street and
+// city are title-cased, street abbreviations such as "St" and "Ave" are
+// spelled out, the country becomes its standard name and the postal code is
+// formatted and checked for that country.
+//
+// Normalize returns the cleaned address even when it also returns an
+// error, so callers can show what was understood. The error is an
+// *UnknownCountryError, in which case the postal code can't be checked, or
+// a *PostalCodeError.
+func Normalize(a model.Address) (model.Address, error) {
+	a.Street = NormalizeStreet(a.Street)
+	a.City = titleCase(strings.Fields(a.City))
+	a.ZipCode = strings.ToUpper(strings.Join(strings.Fields(a.ZipCode), " "))
+
+	c, err := LookupCountry(a.Country)
+	if err != nil {
+		a.Country = strings.Join(strings.Fields(a.Country), " ")
+		return a, err
+	}
+	a.Country = c.Name
+	zip, err := c.NormalizePostalCode(a.ZipCode)
+	if err != nil {
+		return a, err
+	}
+	a.ZipCode = zip
+	return a, nil
+}
+
+// NormalizeStreet tidies a street line:
+//
+//	"123  main st.  apt 4b" → "123 Main Street Apartment 4B"
+func NormalizeStreet(s string) string {
+	words := strings.Fields(strings.ReplaceAll(s, ",", " , "))
+	for i, w := range words {
+		bare := strings.ToUpper(strings.TrimSuffix(w, "."))
+		if full, ok := units[bare]; ok {
+			words[i] = full
+			continue
+		}
+		full, ok := streetTypes[bare]
+		if !ok || i == 0 {
+			continue
+		}
+		last := i == len(words)-1 || words[i+1] == ","
+		beforeUnit := i+1 < len(words) && (units[strings.ToUpper(strings.TrimSuffix(words[i+1], "."))] != "" ||
+			strings.HasPrefix(words[i+1], "#"))
+		if last || beforeUnit {
+			words[i] = full
+		}
+	}
+	return strings.ReplaceAll(titleCase(words), " ,", ",")
+}
+
+// titleCase capitalizes each word and joins them with single spaces.
+// Words in mixed case such as "McDonald" are kept as they are, ordinals
+// become "1st", and compass directions and other words with digits, like
+// "NW" and "4b", are upper-cased.
+func titleCase(words []string) string {
+	for i, w := range words {
+		switch {
+		case ordinal.MatchString(w):
+			words[i] = strings.ToLower(w)
+		case strings.ContainsFunc(w, unicode.IsDigit), directions[strings.ToUpper(w)]:
+			words[i] = strings.ToUpper(w)
+		case w == strings.ToLower(w) || w == strings.ToUpper(w):
+			r := []rune(strings.ToLower(w))
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+	}
+	return strings.Join(words, " ")
+}
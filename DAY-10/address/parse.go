@@ -0,0 +1,71 @@
+// address/parse.go
+package address
+
+import (
+	"errors"
+	"strings"
+
+	"usermanagement/model"
+)
+
+var ErrIncomplete = errors.New("address: need at least a street, a city and a country")
+
+// Parse reads an address written on one line with commas, or on several
+// lines, street first and country last:
+//
+//	123 main st, Springfield 90210, USA
+//	Hauptstr. 5
+//	10115 Berlin
+//	Germany
+//
+// The postal code is found by the country's pattern, before or after the
+// city. The result is normalized; see Normalize for the errors.
+func Parse(s string) (model.Address, error) {
+	var parts []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) < 3 {
+		return model.Address{}, ErrIncomplete
+	}
+	a := model.Address{Street: parts[0], Country: parts[len(parts)-1]}
+	rest := parts[1 : len(parts)-1]
+
+	c, err := LookupCountry(a.Country)
+	if err != nil {
+		a.City = strings.Join(rest, " ")
+		return Normalize(a)
+	}
+	var city []string
+	for _, p := range rest {
+		if a.ZipCode == "" {
+			if zip, remainder, ok := cutPostalCode(c, p); ok {
+				a.ZipCode = zip
+				p = remainder
+			}
+		}
+		if p != "" {
+			city = append(city, p)
+		}
+	}
+	a.City = strings.Join(city, " ")
+	return Normalize(a)
+}
+
+// cutPostalCode looks for a postal code of country c at the start or end
+// of part, trying the longest run of words first so "SW1A 1AA" is found
+// whole.
+func cutPostalCode(c Country, part string) (zip, rest string, ok bool) {
+	words := strings.Fields(part)
+	for n := min(len(words), 3); n > 0; n-- {
+		if zip, err := c.NormalizePostalCode(strings.Join(words[len(words)-n:], " ")); err == nil {
+			return zip, strings.Join(words[:len(words)-n], " "), true
+		}
+		if zip, err := c.NormalizePostalCode(strings.Join(words[:n], " ")); err == nil {
+			return zip, strings.Join(words[n:], " "), true
+		}
+	}
+	return "", part, false
+}
@@ -0,0 +1,60 @@
+// api/errors.go
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"usermanagement/product"
+	"usermanagement/user"
+	"usermanagement/validate"
+)
+
+// Error is the structured error every endpoint answers with:
+//
+//	{"error": {"code": "validation_failed", "message": "...",
+//	           "fields": [{"path": "email", "message": "..."}]}}
+//
+// Code is a stable, machine-readable string; Message is for people.
+type Error struct {
+	Status  int          `json:"-"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError points at one invalid field of a request body.
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// writeError turns any error into an Error response. Errors the API does not
+// know about become a 500 without leaking their text to the client.
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *Error
+	switch {
+	case errors.As(err, &apiErr):
+	case errors.Is(err, user.ErrNotFound), errors.Is(err, product.ErrNotFound):
+		apiErr = &Error{Status: http.StatusNotFound, Code: "not_found", Message: err.Error()}
+	case errors.Is(err, user.ErrEmailTaken):
+		apiErr = &Error{Status: http.StatusConflict, Code: "email_taken", Message: err.Error()}
+	case errors.Is(err, user.ErrInvalid), errors.Is(err, product.ErrInvalid):
+		apiErr = &Error{Status: http.StatusUnprocessableEntity, Code: "validation_failed", Message: "the request body is not valid"}
+		var fields validate.Errors
+		if errors.As(err, &fields) {
+			for _, fe := range fields {
+				apiErr.Fields = append(apiErr.Fields, FieldError{Path: fe.Path, Message: fe.Message})
+			}
+		}
+	default:
+		log.Printf("api: internal error: %v", err)
+		apiErr = &Error{Status: http.StatusInternalServerError, Code: "internal", Message: "internal server error"}
+	}
+	writeJSON(w, apiErr.Status, map[string]*Error{"error": apiErr})
+}
@@ -0,0 +1,111 @@
+// api/products.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"usermanagement/model"
+)
+
+func (s *Server) listProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := s.products.List(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	page, err := paginate(r, products)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) createProduct(w http.ResponseWriter, r *http.Request) {
+	var body model.Product
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+	p, err := s.products.Create(r.Context(), body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/products/"+strconv.Itoa(p.ID))
+	writeResource(w, r, http.StatusCreated, p)
+}
+
+func (s *Server) getProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	p, err := s.products.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResource(w, r, http.StatusOK, p)
+}
+
+func (s *Server) replaceProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var body model.Product
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.products.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkIfMatch(r, current); err != nil {
+		writeError(w, err)
+		return
+	}
+	body.ID = id // The URL decides which product is replaced.
+	p, err := s.products.Replace(r.Context(), body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResource(w, r, http.StatusOK, p)
+}
+
+func (s *Server) deleteProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.products.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkIfMatch(r, current); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.products.Delete(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
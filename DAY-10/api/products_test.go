@@ -0,0 +1,85 @@
+// api/products_test.go
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"usermanagement/api"
+	"usermanagement/model"
+)
+
+func TestProductCRUD(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := do(t, srv, "POST", "/products", model.Product{Name: "Laptop", Price: 1200, Stock: 5})
+	created := decodeBody[model.Product](t, resp, http.StatusCreated)
+	if created != (model.Product{ID: 1, Name: "Laptop", Price: 1200, Stock: 5}) {
+		t.Errorf("created = %+v", created)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/products/1" {
+		t.Errorf("Location = %q, want /products/1", loc)
+	}
+	tag := resp.Header.Get("ETag")
+
+	// The URL decides which product is replaced, whatever ID the body has.
+	resp = do(t, srv, "PUT", "/products/1", model.Product{ID: 99, Name: "Laptop Pro", Price: 1500, Stock: 2}, "If-Match", tag)
+	replaced := decodeBody[model.Product](t, resp, http.StatusOK)
+	if replaced != (model.Product{ID: 1, Name: "Laptop Pro", Price: 1500, Stock: 2}) {
+		t.Errorf("replaced = %+v", replaced)
+	}
+	if got := decodeBody[model.Product](t, do(t, srv, "GET", "/products/1", nil), http.StatusOK); got != replaced {
+		t.Errorf("GET = %+v, want %+v", got, replaced)
+	}
+
+	wantError(t, do(t, srv, "DELETE", "/products/1", nil, "If-Match", tag), http.StatusPreconditionFailed, "precondition_failed")
+	decodeBody[any](t, do(t, srv, "DELETE", "/products/1", nil, "If-Match", resp.Header.Get("ETag")), http.StatusNoContent)
+	wantError(t, do(t, srv, "GET", "/products/1", nil), http.StatusNotFound, "not_found")
+	wantError(t, do(t, srv, "DELETE", "/products/1", nil, "If-Match", "*"), http.StatusNotFound, "not_found")
+}
+
+func TestProductValidation(t *testing.T) {
+	srv := newTestServer(t)
+	apiErr := wantError(t, do(t, srv, "POST", "/products", model.Product{Price: -1, Stock: -2}),
+		http.StatusUnprocessableEntity, "validation_failed")
+	if len(apiErr.Fields) != 3 {
+		t.Errorf("fields = %+v, want name, price and stock", apiErr.Fields)
+	}
+}
+
+func TestProductListPages(t *testing.T) {
+	srv := newTestServer(t)
+	for i := 1; i <= 25; i++ {
+		decodeBody[model.Product](t, do(t, srv, "POST", "/products", model.Product{Name: fmt.Sprint("Item ", i), Price: 1}), http.StatusCreated)
+	}
+
+	tests := []struct {
+		query            string
+		page, perPage    int
+		firstID, entries int
+	}{
+		{"", 1, 20, 1, 20},
+		{"?page=2", 2, 20, 21, 5},
+		{"?per_page=10&page=3", 3, 10, 21, 5},
+		{"?per_page=100", 1, 100, 1, 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			page := decodeBody[api.Page[model.Product]](t, do(t, srv, "GET", "/products"+tt.query, nil), http.StatusOK)
+			if page.Page != tt.page || page.PerPage != tt.perPage || page.Total != 25 || len(page.Items) != tt.entries {
+				t.Fatalf("got page %d/%d with %d of %d items, want page %d/%d with %d of 25",
+					page.Page, page.PerPage, len(page.Items), page.Total, tt.page, tt.perPage, tt.entries)
+			}
+			if page.Items[0].ID != tt.firstID {
+				t.Errorf("first ID = %d, want %d", page.Items[0].ID, tt.firstID)
+			}
+		})
+	}
+
+	// An empty page is still an array, not null.
+	resp := do(t, srv, "GET", "/products?page=9", nil)
+	if raw := decodeBody[map[string]any](t, resp, http.StatusOK); raw["items"] == nil {
+		t.Errorf("items = %v, want []", raw["items"])
+	}
+}
@@ -0,0 +1,172 @@
+// api/server.go
+package api // JSON over HTTP for users and products
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"usermanagement/product"
+	"usermanagement/user"
+)
+
+// Server serves the /users and /products endpoints:
+//
+//	GET    /users              list users (?page, ?per_page, ?active, ?city)
+//	POST   /users              create a user
+//	GET    /users/{id}         fetch a user
+//	PUT    /users/{id}         replace a user (needs If-Match)
+//	DELETE /users/{id}         soft-delete a user (needs If-Match)
+//
+// and the same five for /products, where listing only pages.
+//
+// Every single-resource response carries an ETag. Writes must send it back
+// in If-Match; if the resource changed in the meantime the server answers
+// 412 Precondition Failed instead of overwriting someone else's change.
+type Server struct {
+	users    *user.Service
+	products *product.Service
+	mux      *http.ServeMux
+
+	// writeMu makes "compare the ETag, then write" a single step. It
+	// serializes writes, which is fine for one in-memory process.
+	writeMu sync.Mutex
+}
+
+// NewServer returns a Server backed by the given services.
+func NewServer(users *user.Service, products *product.Service) *Server {
+	s := &Server{users: users, products: products, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /users", s.listUsers)
+	s.mux.HandleFunc("POST /users", s.createUser)
+	s.mux.HandleFunc("GET /users/{id}", s.getUser)
+	s.mux.HandleFunc("PUT /users/{id}", s.replaceUser)
+	s.mux.HandleFunc("DELETE /users/{id}", s.deleteUser)
+
+	s.mux.HandleFunc("GET /products", s.listProducts)
+	s.mux.HandleFunc("POST /products", s.createProduct)
+	s.mux.HandleFunc("GET /products/{id}", s.getProduct)
+	s.mux.HandleFunc("PUT /products/{id}", s.replaceProduct)
+	s.mux.HandleFunc("DELETE /products/{id}", s.deleteProduct)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Page is the body of every list response.
+type Page[T any] struct {
+	Items   []T `json:"items"`
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Total   int `json:"total"`
+}
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+	maxBodyBytes   = 1 << 20
+)
+
+// paginate cuts one page out of items according to ?page and ?per_page.
+func paginate[T any](r *http.Request, items []T) (Page[T], error) {
+	page, err := intParam(r, "page", 1)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	perPage, err := intParam(r, "per_page", defaultPerPage)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	if page < 1 || perPage < 1 || perPage > maxPerPage {
+		return Page[T]{}, &Error{Status: http.StatusBadRequest, Code: "invalid_parameter",
+			Message: fmt.Sprintf("page must be at least 1 and per_page between 1 and %d", maxPerPage)}
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	// Copy the page so an empty one encodes as [] rather than null.
+	pageItems := append([]T{}, items[start:end]...)
+	return Page[T]{Items: pageItems, Page: page, PerPage: perPage, Total: len(items)}, nil
+}
+
+func intParam(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, &Error{Status: http.StatusBadRequest, Code: "invalid_parameter", Message: fmt.Sprintf("%s must be a number", name)}
+	}
+	return n, nil
+}
+
+func pathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		return 0, &Error{Status: http.StatusBadRequest, Code: "invalid_id", Message: "id must be a positive number"}
+	}
+	return id, nil
+}
+
+// decode reads a JSON body into dst, rejecting unknown fields and bodies
+// larger than maxBodyBytes.
+func decode(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &Error{Status: http.StatusRequestEntityTooLarge, Code: "body_too_large",
+				Message: fmt.Sprintf("the request body must not exceed %d bytes", tooLarge.Limit)}
+		}
+		return &Error{Status: http.StatusBadRequest, Code: "invalid_json", Message: err.Error()}
+	}
+	return nil
+}
+
+// etag computes a strong ETag from the JSON form of v, so any change to a
+// resource changes its tag.
+func etag(v any) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// checkIfMatch enforces optimistic concurrency for writes.
+func checkIfMatch(r *http.Request, current any) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return &Error{Status: http.StatusPreconditionRequired, Code: "precondition_required",
+			Message: "send the resource's ETag in an If-Match header"}
+	}
+	if ifMatch != "*" && ifMatch != etag(current) {
+		return &Error{Status: http.StatusPreconditionFailed, Code: "precondition_failed",
+			Message: "the resource was changed by someone else; fetch it again and retry"}
+	}
+	return nil
+}
+
+// writeResource sends a single resource with its ETag. A GET whose
+// If-None-Match already names the current ETag gets 304 Not Modified.
+func writeResource(w http.ResponseWriter, r *http.Request, status int, v any) {
+	tag := etag(v)
+	w.Header().Set("ETag", tag)
+	if r.Method == http.MethodGet && r.Header.Get("If-None-Match") == tag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, status, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
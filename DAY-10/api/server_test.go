@@ -0,0 +1,130 @@
+// api/server_test.go
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"usermanagement/api"
+	"usermanagement/product"
+	"usermanagement/user"
+)
+
+// newTestServer starts the API over fresh in-memory repositories.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	users := user.NewService(user.NewMemoryRepository())
+	products := product.NewService(product.NewMemoryRepository())
+	srv := httptest.NewServer(api.NewServer(users, products))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do sends a request with an optional JSON body and extra headers, given as
+// name/value pairs.
+func do(t *testing.T, srv *httptest.Server, method, path string, body any, header ...string) *http.Response {
+	t.Helper()
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = strings.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, srv.URL+path, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// decodeBody checks the status code and decodes the JSON body into a T.
+func decodeBody[T any](t *testing.T, resp *http.Response, status int) T {
+	t.Helper()
+	var v T
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s: status %d, want %d; body: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, status, data)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatalf("decoding %s: %v", data, err)
+		}
+	}
+	return v
+}
+
+// errorBody is the envelope every error response uses.
+type errorBody struct {
+	Error api.Error `json:"error"`
+}
+
+// wantError checks an error response's status and code and returns it.
+func wantError(t *testing.T, resp *http.Response, status int, code string) api.Error {
+	t.Helper()
+	body := decodeBody[errorBody](t, resp, status)
+	if body.Error.Code != code {
+		t.Errorf("error code = %q, want %q", body.Error.Code, code)
+	}
+	if body.Error.Message == "" {
+		t.Error("error message is empty")
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	return body.Error
+}
+
+func TestRequestErrors(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		status int
+		code   string
+	}{
+		{"bad id", "GET", "/users/abc", nil, http.StatusBadRequest, "invalid_id"},
+		{"zero id", "GET", "/products/0", nil, http.StatusBadRequest, "invalid_id"},
+		{"unknown user", "GET", "/users/42", nil, http.StatusNotFound, "not_found"},
+		{"unknown product", "GET", "/products/42", nil, http.StatusNotFound, "not_found"},
+		{"malformed json", "POST", "/users", `{"name":`, http.StatusBadRequest, "invalid_json"},
+		{"unknown field", "POST", "/products", `{"name":"Pen","colour":"red"}`, http.StatusBadRequest, "invalid_json"},
+		{"oversized body", "POST", "/users", `{"name":"` + strings.Repeat("x", 2<<20) + `"}`, http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"bad page", "GET", "/users?page=0", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"per_page too large", "GET", "/products?per_page=1000", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"page not a number", "GET", "/products?page=two", nil, http.StatusBadRequest, "invalid_parameter"},
+		{"bad active filter", "GET", "/users?active=maybe", nil, http.StatusBadRequest, "invalid_parameter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantError(t, do(t, srv, tt.method, tt.path, tt.body), tt.status, tt.code)
+		})
+	}
+}
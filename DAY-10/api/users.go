@@ -0,0 +1,155 @@
+// api/users.go
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"usermanagement/model"
+	"usermanagement/user"
+)
+
+// userBody is what clients send to create or replace a user. ID and
+// DeletedAt are managed by the server and can't be set.
+type userBody struct {
+	Name     string        `json:"name"`
+	Email    string        `json:"email"`
+	IsActive *bool         `json:"is_active"`
+	Address  model.Address `json:"address"`
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.users.List(r.Context(), user.ListOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	q := r.URL.Query()
+	if raw := q.Get("active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, &Error{Status: http.StatusBadRequest, Code: "invalid_parameter", Message: "active must be true or false"})
+			return
+		}
+		users = filter(users, func(u model.User) bool { return u.IsActive == active })
+	}
+	if city := q.Get("city"); city != "" {
+		users = filter(users, func(u model.User) bool { return strings.EqualFold(u.Address.City, city) })
+	}
+
+	page, err := paginate(r, users)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var body userBody
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+	u, err := s.users.Create(r.Context(), user.NewUser{Name: body.Name, Email: body.Email, IsActive: body.IsActive, Address: body.Address})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/users/"+strconv.Itoa(u.ID))
+	writeResource(w, r, http.StatusCreated, u)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	u, err := s.users.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResource(w, r, http.StatusOK, u)
+}
+
+func (s *Server) replaceUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var body userBody
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.users.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkIfMatch(r, current); err != nil {
+		writeError(w, err)
+		return
+	}
+	active := current.IsActive
+	if body.IsActive != nil {
+		active = *body.IsActive
+	}
+	u, err := s.users.Update(r.Context(), id, user.Changes{
+		Name:     &body.Name,
+		Email:    &body.Email,
+		IsActive: &active,
+		Address:  &body.Address,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResource(w, r, http.StatusOK, u)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.users.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkIfMatch(r, current); err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.users.Delete(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// filter keeps the items for which keep returns true.
+func filter[T any](items []T, keep func(T) bool) []T {
+	var out []T
+	for _, item := range items {
+		if keep(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+// api/users_test.go
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"usermanagement/api"
+	"usermanagement/model"
+)
+
+func TestUserCRUD(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := do(t, srv, "POST", "/users", map[string]any{
+		"name":    "Alice Wonderland",
+		"email":   "alice@example.com",
+		"address": map[string]string{"city": "Wonderland", "zip_code": "90210", "country": "US"},
+	})
+	created := decodeBody[model.User](t, resp, http.StatusCreated)
+	if created.ID != 1 || !created.IsActive || created.Address.City != "Wonderland" {
+		t.Errorf("created = %+v", created)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/users/1" {
+		t.Errorf("Location = %q, want /users/1", loc)
+	}
+	tag := resp.Header.Get("ETag")
+	if tag == "" {
+		t.Fatal("no ETag on create")
+	}
+
+	resp = do(t, srv, "GET", "/users/1", nil)
+	if got := decodeBody[model.User](t, resp, http.StatusOK); got != created {
+		t.Errorf("GET = %+v, want %+v", got, created)
+	}
+	if got := resp.Header.Get("ETag"); got != tag {
+		t.Errorf("GET ETag = %s, want %s", got, tag)
+	}
+	decodeBody[any](t, do(t, srv, "GET", "/users/1", nil, "If-None-Match", tag), http.StatusNotModified)
+
+	resp = do(t, srv, "PUT", "/users/1", map[string]any{"name": "Alice Liddell", "email": "alice@example.com", "is_active": false},
+		"If-Match", tag)
+	replaced := decodeBody[model.User](t, resp, http.StatusOK)
+	if replaced.Name != "Alice Liddell" || replaced.IsActive || replaced.Address != (model.Address{}) {
+		t.Errorf("replaced = %+v", replaced)
+	}
+	newTag := resp.Header.Get("ETag")
+	if newTag == tag {
+		t.Error("ETag did not change after PUT")
+	}
+
+	decodeBody[any](t, do(t, srv, "DELETE", "/users/1", nil, "If-Match", newTag), http.StatusNoContent)
+	wantError(t, do(t, srv, "GET", "/users/1", nil), http.StatusNotFound, "not_found")
+
+	// The deleted user's email address is free again.
+	resp = do(t, srv, "POST", "/users", map[string]any{"name": "Alice Again", "email": "alice@example.com"})
+	if got := decodeBody[model.User](t, resp, http.StatusCreated); got.ID != 2 {
+		t.Errorf("recreated user has ID %d, want 2", got.ID)
+	}
+}
+
+func TestUserValidation(t *testing.T) {
+	srv := newTestServer(t)
+	decodeBody[model.User](t, do(t, srv, "POST", "/users", map[string]any{"name": "Bob", "email": "bob@example.com"}), http.StatusCreated)
+
+	t.Run("invalid fields", func(t *testing.T) {
+		resp := do(t, srv, "POST", "/users", map[string]any{
+			"name":    "",
+			"email":   "not-an-email",
+			"address": map[string]string{"zip_code": "ABC", "country": "US"},
+		})
+		apiErr := wantError(t, resp, http.StatusUnprocessableEntity, "validation_failed")
+		var paths []string
+		for _, f := range apiErr.Fields {
+			paths = append(paths, f.Path)
+			if f.Message == "" {
+				t.Errorf("field %s has no message", f.Path)
+			}
+		}
+		for _, want := range []string{"name", "email", "address.zip_code"} {
+			if !slices.Contains(paths, want) {
+				t.Errorf("fields %v do not include %s", paths, want)
+			}
+		}
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		resp := do(t, srv, "POST", "/users", map[string]any{"name": "Bobby", "email": "BOB@example.com"})
+		wantError(t, resp, http.StatusConflict, "email_taken")
+	})
+}
+
+func TestUserIfMatch(t *testing.T) {
+	srv := newTestServer(t)
+	resp := do(t, srv, "POST", "/users", map[string]any{"name": "Carol", "email": "carol@example.com"})
+	decodeBody[model.User](t, resp, http.StatusCreated)
+	staleTag := resp.Header.Get("ETag")
+	update := map[string]any{"name": "Carol B", "email": "carol@example.com"}
+
+	wantError(t, do(t, srv, "PUT", "/users/1", update), http.StatusPreconditionRequired, "precondition_required")
+	wantError(t, do(t, srv, "DELETE", "/users/1", nil), http.StatusPreconditionRequired, "precondition_required")
+
+	// Someone else changes the user first; our tag is now stale.
+	decodeBody[model.User](t, do(t, srv, "PUT", "/users/1", update, "If-Match", staleTag), http.StatusOK)
+	wantError(t, do(t, srv, "PUT", "/users/1", map[string]any{"name": "Carol C", "email": "carol@example.com"}, "If-Match", staleTag),
+		http.StatusPreconditionFailed, "precondition_failed")
+	wantError(t, do(t, srv, "DELETE", "/users/1", nil, "If-Match", staleTag), http.StatusPreconditionFailed, "precondition_failed")
+
+	if got := decodeBody[model.User](t, do(t, srv, "GET", "/users/1", nil), http.StatusOK); got.Name != "Carol B" {
+		t.Errorf("name = %q, want the first update to stick", got.Name)
+	}
+	// "*" matches any current version.
+	decodeBody[any](t, do(t, srv, "DELETE", "/users/1", nil, "If-Match", "*"), http.StatusNoContent)
+}
+
+func TestUserListFiltersAndPages(t *testing.T) {
+	srv := newTestServer(t)
+	cities := []string{"Lagos", "Paris", "lagos", "Berlin", "Lagos"}
+	for i, city := range cities {
+		body := map[string]any{
+			"name":    fmt.Sprintf("User %d", i+1),
+			"email":   fmt.Sprintf("user%d@example.com", i+1),
+			"address": map[string]string{"city": city},
+		}
+		if i%2 == 1 {
+			body["is_active"] = false
+		}
+		decodeBody[model.User](t, do(t, srv, "POST", "/users", body), http.StatusCreated)
+	}
+
+	tests := []struct {
+		query   string
+		wantIDs []int
+		total   int
+	}{
+		{"", []int{1, 2, 3, 4, 5}, 5},
+		{"?per_page=2", []int{1, 2}, 5},
+		{"?per_page=2&page=3", []int{5}, 5},
+		{"?per_page=2&page=4", []int{}, 5},
+		{"?active=true", []int{1, 3, 5}, 3},
+		{"?active=false", []int{2, 4}, 2},
+		{"?city=LAGOS", []int{1, 3, 5}, 3},
+		{"?city=lagos&active=true&per_page=2&page=2", []int{5}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			page := decodeBody[api.Page[model.User]](t, do(t, srv, "GET", "/users"+tt.query, nil), http.StatusOK)
+			ids := []int{}
+			for _, u := range page.Items {
+				ids = append(ids, u.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || page.Total != tt.total {
+				t.Errorf("got IDs %v of %d, want %v of %d", ids, page.Total, tt.wantIDs, tt.total)
+			}
+		})
+	}
+}
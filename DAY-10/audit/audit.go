@@ -0,0 +1,263 @@
+// audit/audit.go
+package audit // An append-only, hash-chained log of who changed which field of an entity, and when
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"usermanagement/structutil"
+)
+
+var ErrNoState = errors.New("audit: entity did not exist at that time")
+
+// Action says what kind of change an Entry records.
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// FieldChange is one changed field. Path is a JSON Pointer into the
+// entity's JSON form, such as "/address/zip_code"; "" is the whole entity.
+// Before is absent for an added field and After for a removed one.
+type FieldChange struct {
+	Path   string          `json:"path"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Entry is one recorded change. Hash covers every other field, PrevHash
+// included, so each entry vouches for the whole log before it.
+type Entry struct {
+	Seq      int           `json:"seq"`
+	Entity   string        `json:"entity"`
+	Actor    string        `json:"actor"`
+	At       time.Time     `json:"at"`
+	Action   Action        `json:"action"`
+	Changes  []FieldChange `json:"changes"`
+	PrevHash string        `json:"prev_hash"`
+	Hash     string        `json:"hash"`
+}
+
+// Log is an append-only audit log kept in memory. Entries can be added but
+// never changed or removed; WriteTo and Load move the log to and from a
+// file. A Log is safe for concurrent use.
+type Log struct {
+	// Now returns the current time. Tests can replace it.
+	Now func() time.Time
+
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// New returns an empty Log.
+func New() *Log {
+	return &Log{Now: time.Now}
+}
+
+// Record compares the JSON forms of before and after and appends an entry
+// listing the fields that differ. A nil before records a Create, a nil
+// after a Delete. If nothing changed, nothing is recorded and the returned
+// Entry has Seq 0.
+//
+// Fields are compared object member by object member; arrays and anything
+// else that is not an object are compared whole, so a changed list shows up
+// as one change with the full old and new list.
+func (l *Log) Record(entity, actor string, before, after any) (Entry, error) {
+	old, err := toDoc(before)
+	if err != nil {
+		return Entry{}, err
+	}
+	cur, err := toDoc(after)
+	if err != nil {
+		return Entry{}, err
+	}
+	action := Update
+	switch {
+	case old == nil && cur == nil:
+		return Entry{}, fmt.Errorf("audit: %s: before and after are both nil", entity)
+	case old == nil:
+		action = Create
+	case cur == nil:
+		action = Delete
+	}
+	if old == nil {
+		old = missing{}
+	}
+	if cur == nil {
+		cur = missing{}
+	}
+	var changes []FieldChange
+	if err := diff(&changes, "", old, cur); err != nil {
+		return Entry{}, err
+	}
+	if len(changes) == 0 {
+		return Entry{}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := Entry{
+		Seq:     len(l.entries) + 1,
+		Entity:  entity,
+		Actor:   actor,
+		At:      l.Now().UTC(),
+		Action:  action,
+		Changes: changes,
+	}
+	if n := len(l.entries); n > 0 {
+		e.PrevHash = l.entries[n-1].Hash
+	}
+	if e.Hash, err = e.hash(); err != nil {
+		return Entry{}, err
+	}
+	l.entries = append(l.entries, e)
+	return e, nil
+}
+
+// diff appends the changes between two generic JSON documents, either of
+// which may be missing{}.
+func diff(out *[]FieldChange, path string, old, cur any) error {
+	o, okO := old.(map[string]any)
+	c, okC := cur.(map[string]any)
+	if okO && okC {
+		keys := slices.Collect(maps.Keys(o))
+		for k := range c {
+			if _, ok := o[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			child := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+			if err := diff(out, child, member(o, k), member(c, k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	b, err := raw(old)
+	if err != nil {
+		return err
+	}
+	a, err := raw(cur)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(a, b) {
+		*out = append(*out, FieldChange{Path: path, Before: b, After: a})
+	}
+	return nil
+}
+
+// missing stands for an absent member, which is different from a member
+// that is null.
+type missing struct{}
+
+func member(m map[string]any, k string) any {
+	if v, ok := m[k]; ok {
+		return v
+	}
+	return missing{}
+}
+
+func raw(v any) (json.RawMessage, error) {
+	if v == (missing{}) {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func toDoc(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Entries returns the whole log, oldest first.
+func (l *Log) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return slices.Clone(l.entries)
+}
+
+// History returns the entries of one entity, oldest first.
+func (l *Log) History(entity string) []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []Entry
+	for _, e := range l.entries {
+		if e.Entity == entity {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// StateAt rebuilds the JSON form of an entity as it was at time t, by
+// replaying its changes up to and including t. It returns ErrNoState if
+// the entity had not been created yet or was deleted.
+func (l *Log) StateAt(entity string, t time.Time) (json.RawMessage, error) {
+	var doc json.RawMessage
+	for _, e := range l.History(entity) {
+		if e.At.After(t) {
+			break
+		}
+		var err error
+		if doc, err = replay(doc, e.Changes); err != nil {
+			return nil, fmt.Errorf("audit: %s: replaying entry %d: %w", entity, e.Seq, err)
+		}
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("%w: %s at %s", ErrNoState, entity, t.Format(time.RFC3339))
+	}
+	return doc, nil
+}
+
+// Restore decodes the state of an entity at time t into dst.
+func (l *Log) Restore(entity string, t time.Time, dst any) error {
+	doc, err := l.StateAt(entity, t)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(doc, dst)
+}
+
+func replay(doc json.RawMessage, changes []FieldChange) (json.RawMessage, error) {
+	var p structutil.Patch
+	for _, c := range changes {
+		if c.Path == "" {
+			doc = c.After // Created, deleted or replaced whole.
+			continue
+		}
+		if c.After == nil {
+			p = append(p, structutil.Operation{Op: "remove", Path: c.Path})
+		} else {
+			p = append(p, structutil.Operation{Op: "add", Path: c.Path, Value: c.After})
+		}
+	}
+	if len(p) == 0 {
+		return doc, nil
+	}
+	return structutil.ApplyPatchJSON(doc, p)
+}
@@ -0,0 +1,264 @@
+// audit/audit_test.go
+package audit_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"usermanagement/audit"
+	"usermanagement/model"
+)
+
+var start = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// newLog returns a Log whose clock starts at start and moves on a minute
+// every time it is read.
+func newLog() *audit.Log {
+	l := audit.New()
+	tick := 0
+	l.Now = func() time.Time {
+		tick++
+		return start.Add(time.Duration(tick-1) * time.Minute)
+	}
+	return l
+}
+
+func record(t *testing.T, l *audit.Log, entity string, before, after any) audit.Entry {
+	t.Helper()
+	e, err := l.Record(entity, "admin", before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+var alice = model.User{ID: 1, Name: "Alice", Email: "alice@example.com", IsActive: true,
+	Address: model.Address{City: "Berlin", ZipCode: "10115", Country: "DE"}}
+
+func TestRecord(t *testing.T) {
+	l := newLog()
+	created := record(t, l, "user/1", nil, alice)
+	if created.Seq != 1 || created.Action != audit.Create || len(created.Changes) != 1 || created.Changes[0].Path != "" {
+		t.Errorf("create entry = %+v, want one whole-entity change", created)
+	}
+
+	moved := alice
+	moved.Address.ZipCode, moved.Email = "20095", "alice@example.org"
+	updated := record(t, l, "user/1", alice, moved)
+	want := []audit.FieldChange{
+		{Path: "/address/zip_code", Before: json.RawMessage(`"10115"`), After: json.RawMessage(`"20095"`)},
+		{Path: "/email", Before: json.RawMessage(`"alice@example.com"`), After: json.RawMessage(`"alice@example.org"`)},
+	}
+	if updated.Seq != 2 || updated.Action != audit.Update || updated.PrevHash != created.Hash || !equalChanges(updated.Changes, want) {
+		t.Errorf("update entry = %+v, want changes %+v", updated, want)
+	}
+
+	if e := record(t, l, "user/1", moved, moved); e.Seq != 0 {
+		t.Errorf("recording no change = %+v, want no entry", e)
+	}
+	if deleted := record(t, l, "user/1", moved, nil); deleted.Seq != 3 || deleted.Action != audit.Delete {
+		t.Errorf("delete entry = %+v", deleted)
+	}
+	if _, err := l.Record("user/1", "admin", nil, nil); err == nil {
+		t.Error("Record(nil, nil) succeeded")
+	}
+	if got := l.History("user/1"); len(got) != 3 || l.Head() != got[2].Hash {
+		t.Errorf("History = %d entries, Head = %q", len(got), l.Head())
+	}
+	if err := l.Verify(); err != nil {
+		t.Errorf("Verify = %v", err)
+	}
+}
+
+func equalChanges(got, want []audit.FieldChange) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i].Path != want[i].Path || !bytes.Equal(got[i].Before, want[i].Before) || !bytes.Equal(got[i].After, want[i].After) {
+			return false
+		}
+	}
+	return true
+}
+
+// rehash recomputes an entry's hash the way the log does, as a forger who
+// knows the scheme would.
+func rehash(t *testing.T, e *audit.Entry) {
+	t.Helper()
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	e.Hash = hex.EncodeToString(sum[:])
+}
+
+func TestTamper(t *testing.T) {
+	l := newLog()
+	record(t, l, "user/1", nil, alice)
+	renamed := alice
+	renamed.Name = "Alicia"
+	record(t, l, "user/1", alice, renamed)
+	record(t, l, "user/2", nil, model.User{ID: 2, Name: "Bob", Email: "bob@example.com"})
+	record(t, l, "user/1", renamed, nil)
+
+	var buf bytes.Buffer
+	if _, err := l.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	loaded, err := audit.Load(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Load of an untouched log = %v", err)
+	}
+	if loaded.Head() != l.Head() || len(loaded.Entries()) != 4 {
+		t.Errorf("loaded log has %d entries and head %q, want 4 and %q", len(loaded.Entries()), loaded.Head(), l.Head())
+	}
+
+	entry := func(i int) audit.Entry {
+		var e audit.Entry
+		if err := json.Unmarshal([]byte(lines[i]), &e); err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	encode := func(e audit.Entry) string {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data) + "\n"
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(lines []string) []string
+		wantSeq int
+	}{
+		{"edited entry", func(lines []string) []string {
+			e := entry(1)
+			e.Actor = "nobody"
+			lines[1] = encode(e)
+			return lines
+		}, 2},
+		{"edited entry with its hash recomputed", func(lines []string) []string {
+			e := entry(1)
+			e.Changes[0].After = json.RawMessage(`"Mallory"`)
+			rehash(t, &e)
+			lines[1] = encode(e)
+			return lines
+		}, 3},
+		{"reordered entries", func(lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, 3},
+		{"deleted entry", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}, 3},
+		{"deleted and renumbered", func(lines []string) []string {
+			e := entry(2)
+			e.Seq = 2
+			rehash(t, &e)
+			return []string{lines[0], encode(e), lines[3]}
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := strings.Join(tt.tamper(append([]string(nil), lines...)), "")
+			_, err := audit.Load(strings.NewReader(tampered))
+			var te *audit.TamperError
+			if !errors.As(err, &te) || !errors.Is(err, audit.ErrTampered) {
+				t.Fatalf("Load = %v, want a *TamperError", err)
+			}
+			if te.Seq != tt.wantSeq {
+				t.Errorf("TamperError = %v, want it at entry %d", te, tt.wantSeq)
+			}
+		})
+	}
+}
+
+func TestStateAt(t *testing.T) {
+	l := newLog()
+	var at []time.Time // When each entry was made.
+	for _, step := range []struct{ before, after any }{
+		{nil, map[string]any{"name": "Alice", "tags": []string{"a"}, "nick": "al"}},
+		{map[string]any{"name": "Alice", "tags": []string{"a"}, "nick": "al"}, map[string]any{"name": "Alicia", "tags": []string{"a", "b"}}},
+		{map[string]any{"name": "Alicia", "tags": []string{"a", "b"}}, nil},
+		{nil, map[string]any{"name": "Alice again"}},
+	} {
+		at = append(at, record(t, l, "doc", step.before, step.after).At)
+	}
+	record(t, l, "other", nil, map[string]any{"name": "Bob"})
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string // "" for ErrNoState.
+	}{
+		{"before creation", at[0].Add(-time.Second), ""},
+		{"at creation", at[0], `{"name":"Alice","nick":"al","tags":["a"]}`},
+		{"between changes", at[1].Add(-time.Second), `{"name":"Alice","nick":"al","tags":["a"]}`},
+		{"after update", at[1], `{"name":"Alicia","tags":["a","b"]}`},
+		{"after delete", at[2], ""},
+		{"recreated", at[3].Add(time.Hour), `{"name":"Alice again"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.StateAt("doc", tt.t)
+			if tt.want == "" {
+				if !errors.Is(err, audit.ErrNoState) {
+					t.Errorf("StateAt = %s, %v, want ErrNoState", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, got); err != nil {
+				t.Fatal(err)
+			}
+			if compact.String() != tt.want {
+				t.Errorf("StateAt = %s, want %s", compact.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	l := newLog()
+	first := record(t, l, "user/1", nil, alice)
+	moved := alice
+	moved.Address = model.Address{Street: "Hauptstr. 1", City: "Hamburg", ZipCode: "20095", Country: "DE"}
+	moved.IsActive = false
+	record(t, l, "user/1", alice, moved)
+	deleted := moved
+	deleted.DeletedAt = start.Add(time.Hour)
+	last := record(t, l, "user/1", moved, deleted)
+
+	for _, tt := range []struct {
+		at   time.Time
+		want model.User
+	}{{first.At, alice}, {last.At.Add(-time.Second), moved}, {last.At, deleted}} {
+		var got model.User
+		if err := l.Restore("user/1", tt.at, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Restore at %s = %+v, want %+v", tt.at.Format(time.Kitchen), got, tt.want)
+		}
+	}
+	var got model.User
+	if err := l.Restore("user/2", last.At, &got); !errors.Is(err, audit.ErrNoState) {
+		t.Errorf("Restore(user/2) = %v, want ErrNoState", err)
+	}
+}
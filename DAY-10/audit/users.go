@@ -0,0 +1,85 @@
+// audit/users.go
+package audit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"usermanagement/model"
+	"usermanagement/user"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context saying who is making the changes done with
+// it, e.g. the logged-in admin's email.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or "unknown".
+func ActorFrom(ctx context.Context) string {
+	if a, ok := ctx.Value(actorKey{}).(string); ok && a != "" {
+		return a
+	}
+	return "unknown"
+}
+
+// UserRepository wraps a user.Repository and records every successful
+// Create and Update in a Log under "user/<id>", with the actor taken from
+// the context. Soft deletes are updates that set deleted_at.
+//
+// Changes made through a UserRepository are serialized, so each entry's
+// before state is the state the change replaced. Record only fails on a
+// value that doesn't marshal to JSON, and that is checked before the change
+// is stored, so a stored change always has its entry. Changes made to the
+// wrapped repository directly are neither serialized nor recorded.
+type UserRepository struct {
+	user.Repository
+	log *Log
+	mu  sync.Mutex
+}
+
+var _ user.Repository = (*UserRepository)(nil)
+
+// Users returns repo with auditing added.
+func Users(repo user.Repository, log *Log) *UserRepository {
+	return &UserRepository{Repository: repo, log: log}
+}
+
+// UserEntity is the entity name under which a user's changes are logged.
+func UserEntity(id int) string {
+	return "user/" + strconv.Itoa(id)
+}
+
+func (r *UserRepository) Create(ctx context.Context, u model.User) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := toDoc(u); err != nil {
+		return model.User{}, err
+	}
+	created, err := r.Repository.Create(ctx, u)
+	if err != nil {
+		return created, err
+	}
+	_, err = r.log.Record(UserEntity(created.ID), ActorFrom(ctx), nil, created)
+	return created, err
+}
+
+func (r *UserRepository) Update(ctx context.Context, u model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := toDoc(u); err != nil {
+		return err
+	}
+	old, err := r.Repository.Get(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.Repository.Update(ctx, u); err != nil {
+		return err
+	}
+	_, err = r.log.Record(UserEntity(u.ID), ActorFrom(ctx), old, u)
+	return err
+}
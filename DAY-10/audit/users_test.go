@@ -0,0 +1,93 @@
+// audit/users_test.go
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"usermanagement/audit"
+	"usermanagement/model"
+	"usermanagement/user"
+)
+
+func TestUsers(t *testing.T) {
+	l := newLog()
+	repo := audit.Users(user.NewMemoryRepository(), l)
+	ctx := audit.WithActor(context.Background(), "root@example.com")
+
+	created, err := repo.Create(ctx, model.User{Name: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.IsActive = true
+	if err := repo.Update(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Update(ctx, model.User{ID: 9, Name: "Nobody"}); err == nil {
+		t.Error("Update of a missing user succeeded")
+	}
+
+	history := l.History(audit.UserEntity(created.ID))
+	if len(history) != 2 || history[0].Action != audit.Create || history[1].Action != audit.Update {
+		t.Fatalf("History = %+v, want a create and an update", history)
+	}
+	for _, e := range history {
+		if e.Actor != "root@example.com" {
+			t.Errorf("entry %d actor = %q, want the context's actor", e.Seq, e.Actor)
+		}
+	}
+	if got := audit.ActorFrom(context.Background()); got != "unknown" {
+		t.Errorf("ActorFrom without an actor = %q, want unknown", got)
+	}
+}
+
+// TestUsersConcurrentUpdates checks that each update records the state it
+// replaced: the before of every change is the after of the one before it.
+func TestUsersConcurrentUpdates(t *testing.T) {
+	l := newLog()
+	repo := audit.Users(user.NewMemoryRepository(), l)
+	ctx := context.Background()
+	u, err := repo.Create(ctx, model.User{Name: "name-0", Email: "a@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := u
+			v.Name = fmt.Sprintf("name-%d", i+1)
+			if err := repo.Update(ctx, v); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	history := l.History(audit.UserEntity(u.ID))
+	if len(history) != 51 {
+		t.Fatalf("History has %d entries, want the create and 50 renames", len(history))
+	}
+	name := json.RawMessage(`"name-0"`)
+	for _, e := range history[1:] {
+		if len(e.Changes) != 1 || e.Changes[0].Path != "/name" {
+			t.Fatalf("entry %d changes = %+v, want only the name", e.Seq, e.Changes)
+		}
+		if string(e.Changes[0].Before) != string(name) {
+			t.Errorf("entry %d before = %s, want %s", e.Seq, e.Changes[0].Before, name)
+		}
+		name = e.Changes[0].After
+	}
+	stored, err := repo.Get(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("%q", stored.Name); string(name) != want {
+		t.Errorf("last recorded name = %s, stored name = %s", name, want)
+	}
+}
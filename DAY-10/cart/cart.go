@@ -0,0 +1,130 @@
+// cart/cart.go
+package cart // A shopping cart priced by the catalog, with tax by country
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"usermanagement/catalog"
+	"usermanagement/model"
+	"usermanagement/money"
+)
+
+var ErrNotInCart = errors.New("cart: product not in cart")
+
+// Cart holds the products a user is about to buy. Prices are not stored in
+// the cart; Totals asks the catalog every time, so price changes and new
+// discounts show up right away. A Cart is not safe for concurrent use.
+type Cart struct {
+	catalog *catalog.Catalog
+	lines   []catalog.Line // In the order products were first added.
+	coupons []string
+}
+
+// New returns an empty cart for products in c.
+func New(c *catalog.Catalog) *Cart {
+	return &Cart{catalog: c}
+}
+
+// Add puts qty more units of a product in the cart.
+func (c *Cart) Add(sku catalog.SKU, qty int) error {
+	if qty <= 0 {
+		return fmt.Errorf("%w: quantity must be positive, got %d", catalog.ErrInvalid, qty)
+	}
+	if _, err := c.catalog.Get(sku); err != nil {
+		return err
+	}
+	if i := c.index(sku); i >= 0 {
+		c.lines[i].Quantity += qty
+		return nil
+	}
+	c.lines = append(c.lines, catalog.Line{SKU: sku, Quantity: qty})
+	return nil
+}
+
+// SetQuantity changes how many units of a product are in the cart. Zero
+// removes it.
+func (c *Cart) SetQuantity(sku catalog.SKU, qty int) error {
+	if qty < 0 {
+		return fmt.Errorf("%w: quantity must not be negative, got %d", catalog.ErrInvalid, qty)
+	}
+	i := c.index(sku)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotInCart, sku)
+	}
+	if qty == 0 {
+		c.lines = slices.Delete(c.lines, i, i+1)
+		return nil
+	}
+	c.lines[i].Quantity = qty
+	return nil
+}
+
+// Remove takes a product out of the cart.
+func (c *Cart) Remove(sku catalog.SKU) error {
+	return c.SetQuantity(sku, 0)
+}
+
+// ApplyCoupon adds a coupon code to the cart. Unknown codes are rejected
+// with catalog.ErrUnknownCoupon; applying a code twice has no effect.
+func (c *Cart) ApplyCoupon(code string) error {
+	if _, err := c.catalog.Price(nil, code); err != nil {
+		return err
+	}
+	if !slices.Contains(c.coupons, code) {
+		c.coupons = append(c.coupons, code)
+	}
+	return nil
+}
+
+// Lines returns what is in the cart.
+func (c *Cart) Lines() []catalog.Line {
+	return slices.Clone(c.lines)
+}
+
+// Coupons returns the coupon codes applied to the cart.
+func (c *Cart) Coupons() []string {
+	return slices.Clone(c.coupons)
+}
+
+// Empty reports whether the cart has no products.
+func (c *Cart) Empty() bool {
+	return len(c.lines) == 0
+}
+
+// Clear empties the cart and drops its coupons.
+func (c *Cart) Clear() {
+	c.lines, c.coupons = nil, nil
+}
+
+// Totals is what a cart costs when shipped to a given address.
+type Totals struct {
+	catalog.Quote
+	TaxRate money.Rate   `json:"tax_rate"`
+	Tax     money.Amount `json:"tax"`
+	Total   money.Amount `json:"total"` // Quote.Total plus Tax.
+}
+
+// Totals prices the cart with its coupons and adds the tax due for
+// shipping to addr.
+func (c *Cart) Totals(tax TaxRules, addr model.Address) (Totals, error) {
+	q, err := c.catalog.Price(c.lines, c.coupons...)
+	if err != nil {
+		return Totals{}, err
+	}
+	rate, err := tax.Rate(addr.Country)
+	if err != nil {
+		return Totals{}, err
+	}
+	t := Totals{Quote: q, TaxRate: rate}
+	for _, l := range q.Lines {
+		t.Tax += tax.lineTax(rate, l)
+	}
+	t.Total = q.Total + t.Tax
+	return t, nil
+}
+
+func (c *Cart) index(sku catalog.SKU) int {
+	return slices.IndexFunc(c.lines, func(l catalog.Line) bool { return l.SKU == sku })
+}
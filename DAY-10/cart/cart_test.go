@@ -0,0 +1,151 @@
+// cart/cart_test.go
+package cart_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"usermanagement/cart"
+	"usermanagement/catalog"
+	"usermanagement/model"
+	"usermanagement/money"
+)
+
+func newCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	c := catalog.New()
+	for _, p := range []catalog.Product{
+		{SKU: "PEN", Name: "Pen", Category: "office", Price: money.MustParse("2.00"), Stock: 100},
+		{SKU: "BOOK", Name: "Go Book", Category: "books", Price: money.MustParse("30.00"), Stock: 5},
+	} {
+		if err := c.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.AddCoupon("TENOFF", 0, catalog.PercentOff{Rate: money.Percent(10)}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+var rules = cart.TaxRules{
+	Rates:  map[string]money.Rate{"DE": money.Percent(19), "Germany": money.Percent(19), "GB": money.Percent(20)},
+	Exempt: []catalog.Category{"books"},
+}
+
+func TestCartLines(t *testing.T) {
+	ct := cart.New(newCatalog(t))
+	if !ct.Empty() {
+		t.Fatal("new cart is not empty")
+	}
+	for _, add := range []catalog.Line{{SKU: "PEN", Quantity: 2}, {SKU: "BOOK", Quantity: 1}, {SKU: "PEN", Quantity: 3}} {
+		if err := ct.Add(add.SKU, add.Quantity); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := ct.Lines(), []catalog.Line{{SKU: "PEN", Quantity: 5}, {SKU: "BOOK", Quantity: 1}}; !slices.Equal(got, want) {
+		t.Errorf("Lines = %v, want %v", got, want)
+	}
+
+	if err := ct.Add("NOPE", 1); !errors.Is(err, catalog.ErrUnknownSKU) {
+		t.Errorf("Add(NOPE) = %v, want ErrUnknownSKU", err)
+	}
+	if err := ct.Add("PEN", 0); !errors.Is(err, catalog.ErrInvalid) {
+		t.Errorf("Add(PEN, 0) = %v, want ErrInvalid", err)
+	}
+	if err := ct.SetQuantity("PEN", -1); !errors.Is(err, catalog.ErrInvalid) {
+		t.Errorf("SetQuantity(PEN, -1) = %v, want ErrInvalid", err)
+	}
+	if err := ct.SetQuantity("NOPE", 1); !errors.Is(err, cart.ErrNotInCart) {
+		t.Errorf("SetQuantity(NOPE) = %v, want ErrNotInCart", err)
+	}
+
+	if err := ct.SetQuantity("PEN", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ct.Remove("BOOK"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ct.Lines(), []catalog.Line{{SKU: "PEN", Quantity: 1}}; !slices.Equal(got, want) {
+		t.Errorf("Lines = %v, want %v", got, want)
+	}
+
+	if err := ct.ApplyCoupon("nope"); !errors.Is(err, catalog.ErrUnknownCoupon) {
+		t.Errorf("ApplyCoupon(nope) = %v, want ErrUnknownCoupon", err)
+	}
+	ct.ApplyCoupon("TENOFF")
+	ct.ApplyCoupon("TENOFF")
+	if got := ct.Coupons(); !slices.Equal(got, []string{"TENOFF"}) {
+		t.Errorf("Coupons = %v, want [TENOFF]", got)
+	}
+
+	ct.Clear()
+	if !ct.Empty() || ct.Coupons() != nil {
+		t.Errorf("after Clear: lines %v, coupons %v", ct.Lines(), ct.Coupons())
+	}
+}
+
+func TestTotals(t *testing.T) {
+	ct := cart.New(newCatalog(t))
+	ct.Add("PEN", 5)
+	ct.Add("BOOK", 1)
+	ct.ApplyCoupon("tenoff")
+
+	// 10.00 of pens and a 30.00 book, 10% off both; only the pens are
+	// taxed.
+	got, err := ct.Totals(rules, model.Address{Country: " germany "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]money.Amount{
+		"subtotal": money.MustParse("40.00"),
+		"discount": money.MustParse("4.00"),
+		"tax":      money.MustParse("1.71"), // 19% of 9.00
+		"total":    money.MustParse("37.71"),
+	}
+	for name, amount := range map[string]money.Amount{
+		"subtotal": got.Subtotal, "discount": got.Discount, "tax": got.Tax, "total": got.Total,
+	} {
+		if amount != want[name] {
+			t.Errorf("%s = %s, want %s", name, amount, want[name])
+		}
+	}
+	if got.TaxRate != money.Percent(19) {
+		t.Errorf("TaxRate = %s, want 19%%", got.TaxRate)
+	}
+
+	if _, err := ct.Totals(rules, model.Address{Country: "Atlantis"}); !errors.Is(err, cart.ErrNoTaxRate) {
+		t.Errorf("Totals for an unknown country = %v, want ErrNoTaxRate", err)
+	}
+	withDefault := rules
+	withDefault.AllowDefault, withDefault.Default = true, money.Percent(5)
+	if got, err := ct.Totals(withDefault, model.Address{Country: "Atlantis"}); err != nil || got.TaxRate != money.Percent(5) {
+		t.Errorf("Totals with a default = %v, %v, want a 5%% rate", got.TaxRate, err)
+	}
+}
+
+func TestTaxRulesConflict(t *testing.T) {
+	if err := rules.Validate(); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+
+	conflicting := cart.TaxRules{Rates: map[string]money.Rate{
+		"DE": money.Percent(19), " de": money.Percent(7), "GB": money.Percent(20), "Gb ": money.Percent(20), "FR": money.Percent(20),
+	}}
+	err := conflicting.Validate()
+	if !errors.Is(err, cart.ErrConflictingRates) {
+		t.Fatalf("Validate = %v, want ErrConflictingRates", err)
+	}
+	if msg := err.Error(); !strings.HasSuffix(msg, `[" de" "DE"], ["GB" "Gb "]`) {
+		t.Errorf("Validate = %q, want both conflicts listed in order", msg)
+	}
+	// Even a country without a conflict has no rate: the rules as a whole
+	// are wrong.
+	for _, country := range []string{"DE", "FR"} {
+		if _, err := conflicting.Rate(country); !errors.Is(err, cart.ErrConflictingRates) {
+			t.Errorf("Rate(%s) = %v, want ErrConflictingRates", country, err)
+		}
+	}
+}
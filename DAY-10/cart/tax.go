@@ -0,0 +1,96 @@
+// cart/tax.go
+package cart
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"usermanagement/catalog"
+	"usermanagement/money"
+)
+
+var (
+	ErrNoTaxRate        = errors.New("cart: no tax rate for country")
+	ErrConflictingRates = errors.New("cart: one country has several tax rates")
+)
+
+// TaxRules decide the sales tax on a cart from the country it ships to.
+// Check new rules with Validate:
+//
+//	rules := cart.TaxRules{
+//		Rates:  map[string]money.Rate{"DE": money.Percent(19), "Germany": money.Percent(19)},
+//		Exempt: []catalog.Category{"books"},
+//	}
+//	if err := rules.Validate(); err != nil {
+//		log.Fatal(err)
+//	}
+type TaxRules struct {
+	// Rates maps a country, written as in Address.Country, to its rate.
+	// Lookups ignore case and surrounding spaces, so two keys that only
+	// differ in those, such as "DE" and " de", are not allowed.
+	Rates map[string]money.Rate
+
+	// Default is used for countries missing from Rates when AllowDefault
+	// is set. Otherwise an unknown country is an ErrNoTaxRate error, so a
+	// typo in an address can't silently make an order tax-free.
+	Default      money.Rate
+	AllowDefault bool
+
+	// Exempt categories are never taxed.
+	Exempt []catalog.Category
+}
+
+// Validate reports Rates keys that name the same country once case and
+// surrounding spaces are ignored. Which of their rates a lookup found
+// would depend on map order. The error wraps ErrConflictingRates.
+func (t TaxRules) Validate() error {
+	byKey := make(map[string][]string, len(t.Rates))
+	for c := range t.Rates {
+		byKey[countryKey(c)] = append(byKey[countryKey(c)], c)
+	}
+	var conflicts []string
+	for _, names := range byKey {
+		if len(names) > 1 {
+			slices.Sort(names)
+			conflicts = append(conflicts, fmt.Sprintf("%q", names))
+		}
+	}
+	if conflicts == nil {
+		return nil
+	}
+	slices.Sort(conflicts)
+	return fmt.Errorf("%w: %s", ErrConflictingRates, strings.Join(conflicts, ", "))
+}
+
+// Rate returns the tax rate for country. Rules that fail Validate have no
+// reliable rate for any country, so Rate returns Validate's error.
+func (t TaxRules) Rate(country string) (money.Rate, error) {
+	if err := t.Validate(); err != nil {
+		return 0, err
+	}
+	key := countryKey(country)
+	for c, r := range t.Rates {
+		if countryKey(c) == key {
+			return r, nil
+		}
+	}
+	if t.AllowDefault {
+		return t.Default, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrNoTaxRate, country)
+}
+
+// lineTax is the tax on one line after discounts, rounded to the cent.
+// Rounding per line keeps each line's tax stable when others change.
+func (t TaxRules) lineTax(rate money.Rate, l catalog.QuoteLine) money.Amount {
+	if slices.Contains(t.Exempt, l.Category) {
+		return 0
+	}
+	return l.Total.Apply(rate)
+}
+
+func countryKey(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
@@ -0,0 +1,203 @@
+// catalog/catalog.go
+package catalog // Products with SKUs, categories and price tiers, discount rules and stock reservations
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"usermanagement/money"
+)
+
+var (
+	ErrUnknownSKU         = errors.New("catalog: unknown SKU")
+	ErrDuplicateSKU       = errors.New("catalog: SKU already exists")
+	ErrInvalid            = errors.New("catalog: invalid product")
+	ErrUnknownCoupon      = errors.New("catalog: unknown coupon")
+	ErrDuplicateRule      = errors.New("catalog: rule already exists")
+	ErrOutOfStock         = errors.New("catalog: not enough stock")
+	ErrUnknownReservation = errors.New("catalog: unknown reservation")
+)
+
+// SKU (stock keeping unit) identifies a product, e.g. "LAPTOP-15".
+type SKU string
+
+// Category groups products, e.g. "electronics".
+type Category string
+
+// PriceTier is a volume price: buying MinQty or more units makes every
+// unit cost Price.
+type PriceTier struct {
+	MinQty int          `json:"min_qty"`
+	Price  money.Amount `json:"price"`
+}
+
+// Product is a catalog entry. Price is the unit price when no tier applies.
+type Product struct {
+	SKU      SKU          `json:"sku"`
+	Name     string       `json:"name"`
+	Category Category     `json:"category"`
+	Price    money.Amount `json:"price"`
+	Tiers    []PriceTier  `json:"tiers,omitempty"`
+	Stock    int          `json:"stock"`
+}
+
+// UnitPrice returns the price of one unit when qty units are bought: the
+// price of the largest tier qty reaches, or Price.
+func (p Product) UnitPrice(qty int) money.Amount {
+	price := p.Price
+	for _, t := range p.Tiers { // Sorted by MinQty.
+		if qty >= t.MinQty {
+			price = t.Price
+		}
+	}
+	return price
+}
+
+func (p Product) check() error {
+	switch {
+	case strings.TrimSpace(string(p.SKU)) == "":
+		return fmt.Errorf("%w: SKU is required", ErrInvalid)
+	case strings.TrimSpace(p.Name) == "":
+		return fmt.Errorf("%w: %s: name is required", ErrInvalid, p.SKU)
+	case p.Price < 0:
+		return fmt.Errorf("%w: %s: price is negative", ErrInvalid, p.SKU)
+	case p.Stock < 0:
+		return fmt.Errorf("%w: %s: stock is negative", ErrInvalid, p.SKU)
+	}
+	for i, t := range p.Tiers {
+		if t.MinQty < 2 || t.Price < 0 {
+			return fmt.Errorf("%w: %s: tier %d needs a minimum quantity above 1 and a price of at least 0", ErrInvalid, p.SKU, i)
+		}
+		if i > 0 && t.MinQty == p.Tiers[i-1].MinQty {
+			return fmt.Errorf("%w: %s: two tiers start at %d units", ErrInvalid, p.SKU, t.MinQty)
+		}
+	}
+	return nil
+}
+
+// Catalog holds products, the discount rules that apply to them and the
+// stock reserved for carts. It is safe for concurrent use.
+type Catalog struct {
+	mu       sync.Mutex
+	products map[SKU]*Product
+	reserved map[SKU]int // Units held by open reservations.
+
+	rules   []rule          // Automatic rules.
+	coupons map[string]rule // Upper-cased code -> rule.
+
+	reservations map[ReservationID][]Line
+	lastRes      ReservationID
+}
+
+// New returns an empty Catalog.
+func New() *Catalog {
+	return &Catalog{
+		products:     make(map[SKU]*Product),
+		reserved:     make(map[SKU]int),
+		coupons:      make(map[string]rule),
+		reservations: make(map[ReservationID][]Line),
+	}
+}
+
+// Add puts a new product in the catalog.
+func (c *Catalog) Add(p Product) error {
+	p.Tiers = slices.Clone(p.Tiers)
+	slices.SortFunc(p.Tiers, func(a, b PriceTier) int { return a.MinQty - b.MinQty })
+	if err := p.check(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.products[p.SKU]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateSKU, p.SKU)
+	}
+	c.products[p.SKU] = &p
+	return nil
+}
+
+// Get returns the product with the given SKU.
+func (c *Catalog) Get(sku SKU) (Product, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, err := c.product(sku)
+	if err != nil {
+		return Product{}, err
+	}
+	return p.clone(), nil
+}
+
+// List returns the products in category, or every product if category is
+// empty, sorted by SKU.
+func (c *Catalog) List(category Category) []Product {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Product
+	for _, p := range c.products {
+		if category == "" || p.Category == category {
+			out = append(out, p.clone())
+		}
+	}
+	slices.SortFunc(out, func(a, b Product) int { return cmp.Compare(a.SKU, b.SKU) })
+	return out
+}
+
+// Categories returns every category in use, sorted.
+func (c *Catalog) Categories() []Category {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Category
+	for _, p := range c.products {
+		if !slices.Contains(out, p.Category) {
+			out = append(out, p.Category)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Restock adds n units to the stock of a product.
+func (c *Catalog) Restock(sku SKU, n int) error {
+	if n <= 0 {
+		return fmt.Errorf("%w: restock quantity must be positive, got %d", ErrInvalid, n)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, err := c.product(sku)
+	if err != nil {
+		return err
+	}
+	p.Stock += n
+	return nil
+}
+
+// Available returns how many units of a product are in stock and not
+// reserved.
+func (c *Catalog) Available(sku SKU) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, err := c.product(sku)
+	if err != nil {
+		return 0, err
+	}
+	return p.Stock - c.reserved[sku], nil
+}
+
+// product must be called with c.mu held.
+func (c *Catalog) product(sku SKU) (*Product, error) {
+	p, ok := c.products[sku]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSKU, sku)
+	}
+	return p, nil
+}
+
+func (p *Product) clone() Product {
+	out := *p
+	out.Tiers = slices.Clone(p.Tiers)
+	return out
+}
@@ -0,0 +1,350 @@
+// catalog/catalog_test.go
+package catalog_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"usermanagement/catalog"
+	"usermanagement/money"
+)
+
+// newCatalog returns a catalog with pens (tier priced), paper and a book.
+func newCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	c := catalog.New()
+	for _, p := range []catalog.Product{
+		{SKU: "PEN", Name: "Pen", Category: "office", Price: money.MustParse("1.00"), Stock: 100,
+			Tiers: []catalog.PriceTier{{MinQty: 50, Price: money.MustParse("0.60")}, {MinQty: 10, Price: money.MustParse("0.80")}}},
+		{SKU: "PAPER", Name: "Paper", Category: "office", Price: money.MustParse("5.00"), Stock: 10},
+		{SKU: "BOOK", Name: "Go Book", Category: "books", Price: money.MustParse("30.00"), Stock: 2},
+	} {
+		if err := c.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestAddInvalid(t *testing.T) {
+	c := newCatalog(t)
+	tests := []struct {
+		name   string
+		p      catalog.Product
+		target error
+	}{
+		{"duplicate", catalog.Product{SKU: "PEN", Name: "Pen"}, catalog.ErrDuplicateSKU},
+		{"no SKU", catalog.Product{Name: "Pen"}, catalog.ErrInvalid},
+		{"no name", catalog.Product{SKU: "X"}, catalog.ErrInvalid},
+		{"negative price", catalog.Product{SKU: "X", Name: "X", Price: -1}, catalog.ErrInvalid},
+		{"tier of one", catalog.Product{SKU: "X", Name: "X", Tiers: []catalog.PriceTier{{MinQty: 1}}}, catalog.ErrInvalid},
+		{"same tier twice", catalog.Product{SKU: "X", Name: "X",
+			Tiers: []catalog.PriceTier{{MinQty: 5, Price: 1}, {MinQty: 5, Price: 2}}}, catalog.ErrInvalid},
+	}
+	for _, tt := range tests {
+		if err := c.Add(tt.p); !errors.Is(err, tt.target) {
+			t.Errorf("%s: Add = %v, want %v", tt.name, err, tt.target)
+		}
+	}
+}
+
+func TestTierPricing(t *testing.T) {
+	c := newCatalog(t)
+	tests := []struct {
+		qty  int
+		unit string
+	}{
+		{1, "1.00"},
+		{9, "1.00"},
+		{10, "0.80"},
+		{49, "0.80"},
+		{50, "0.60"},
+		{100, "0.60"},
+	}
+	for _, tt := range tests {
+		q, err := c.Price([]catalog.Line{{SKU: "PEN", Quantity: tt.qty}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		unit := money.MustParse(tt.unit)
+		if l := q.Lines[0]; l.UnitPrice != unit || l.Subtotal != unit.Mul(tt.qty) || q.Total != unit.Mul(tt.qty) {
+			t.Errorf("%d pens: %+v, want %s each", tt.qty, l, unit)
+		}
+	}
+
+	// Lines for the same SKU are merged before the tier is chosen.
+	q, err := c.Price([]catalog.Line{{SKU: "PEN", Quantity: 6}, {SKU: "PAPER", Quantity: 1}, {SKU: "PEN", Quantity: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Lines) != 2 || q.Lines[0].SKU != "PAPER" || q.Lines[1].Quantity != 10 || q.Lines[1].UnitPrice != money.MustParse("0.80") {
+		t.Errorf("merged quote = %+v", q.Lines)
+	}
+
+	if _, err := c.Price([]catalog.Line{{SKU: "NOPE", Quantity: 1}}); !errors.Is(err, catalog.ErrUnknownSKU) {
+		t.Errorf("Price(NOPE) = %v, want ErrUnknownSKU", err)
+	}
+	if _, err := c.Price([]catalog.Line{{SKU: "PEN", Quantity: 0}}); !errors.Is(err, catalog.ErrInvalid) {
+		t.Errorf("Price(0 pens) = %v, want ErrInvalid", err)
+	}
+}
+
+func TestRuleOrder(t *testing.T) {
+	type added struct {
+		name     string
+		priority int
+		rule     catalog.Rule
+	}
+	// A fixed 1.00 off applied before 50% off is worth more than after it,
+	// so the order shows in the total.
+	rules := []added{
+		{"half", 2, catalog.PercentOff{Rate: money.Percent(50)}},
+		{"b-euro", 1, fixedOff{"PAPER", money.MustParse("1.00")}},
+		{"a-euro", 1, fixedOff{"PAPER", money.MustParse("1.00")}},
+	}
+	want := []catalog.Discount{
+		{Rule: "a-euro", SKU: "PAPER", Amount: money.MustParse("1.00")},
+		{Rule: "b-euro", SKU: "PAPER", Amount: money.MustParse("1.00")},
+		{Rule: "half", SKU: "PAPER", Amount: money.MustParse("1.50")},
+	}
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+		c := newCatalog(t)
+		for _, i := range order {
+			r := rules[i]
+			if err := c.AddRule(r.name, r.priority, r.rule); err != nil {
+				t.Fatal(err)
+			}
+		}
+		q, err := c.Price([]catalog.Line{{SKU: "PAPER", Quantity: 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(q.Discounts, want) || q.Total != money.MustParse("1.50") {
+			t.Errorf("rules added in order %v: discounts %+v, total %s; want %+v, 1.50", order, q.Discounts, q.Total, want)
+		}
+	}
+
+	c := newCatalog(t)
+	c.AddRule("half", 0, catalog.PercentOff{Rate: money.Percent(50)})
+	if err := c.AddRule("half", 9, catalog.PercentOff{}); !errors.Is(err, catalog.ErrDuplicateRule) {
+		t.Errorf("AddRule twice = %v, want ErrDuplicateRule", err)
+	}
+}
+
+func TestPercentOff(t *testing.T) {
+	c := newCatalog(t)
+	c.AddRule("office", 1, catalog.PercentOff{Rate: money.Percent(10), Category: "office"})
+	c.AddRule("book", 2, catalog.PercentOff{Rate: money.Percent(10), SKUs: []catalog.SKU{"BOOK"}})
+	c.AddRule("all", 3, catalog.PercentOff{Rate: money.Percent(10)})
+
+	q, err := c.Price([]catalog.Line{{SKU: "BOOK", Quantity: 1}, {SKU: "PAPER", Quantity: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each rule takes 10% of what is left: 30.00 -> 27.00 -> 24.30, and
+	// 10.00 -> 9.00 -> 8.10.
+	if q.Lines[0].Total != money.MustParse("24.30") || q.Lines[1].Total != money.MustParse("8.10") {
+		t.Errorf("line totals = %s, %s, want 24.30, 8.10", q.Lines[0].Total, q.Lines[1].Total)
+	}
+	if q.Subtotal != money.MustParse("40.00") || q.Discount != money.MustParse("7.60") || q.Total != money.MustParse("32.40") {
+		t.Errorf("quote = %s - %s = %s", q.Subtotal, q.Discount, q.Total)
+	}
+}
+
+func TestBuyXGetY(t *testing.T) {
+	tests := []struct {
+		qty  int
+		free int
+	}{
+		{1, 0},
+		{2, 0},
+		{3, 1},
+		{4, 1},
+		{5, 1},
+		{6, 2},
+	}
+	for _, tt := range tests {
+		c := newCatalog(t)
+		c.AddRule("3 for 2", 0, catalog.BuyXGetY{SKU: "PAPER", Buy: 2, Get: 1})
+		q, err := c.Price([]catalog.Line{{SKU: "PAPER", Quantity: tt.qty}, {SKU: "BOOK", Quantity: 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		paper := q.Lines[1]
+		if want := money.MustParse("5.00").Mul(tt.free); paper.Discount != want {
+			t.Errorf("%d papers: discount %s, want %s", tt.qty, paper.Discount, want)
+		}
+		if q.Lines[0].Discount != 0 {
+			t.Errorf("%d papers: the book was discounted too", tt.qty)
+		}
+	}
+
+	// Nonsense parameters give nothing away.
+	c := newCatalog(t)
+	c.AddRule("broken", 0, catalog.BuyXGetY{SKU: "PAPER", Buy: 0, Get: 1})
+	if q, _ := c.Price([]catalog.Line{{SKU: "PAPER", Quantity: 3}}); q.Discount != 0 {
+		t.Errorf("Buy 0 Get 1 took off %s", q.Discount)
+	}
+}
+
+func TestDiscountsAreCapped(t *testing.T) {
+	c := newCatalog(t)
+	c.AddRule("too much", 0, fixedOff{"PAPER", money.MustParse("100.00")})
+	c.AddRule("more", 1, fixedOff{"PAPER", money.MustParse("1.00")})
+	q, err := c.Price([]catalog.Line{{SKU: "PAPER", Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Total != 0 || q.Discount != money.MustParse("5.00") || len(q.Discounts) != 1 {
+		t.Errorf("quote = %+v, want a single 5.00 discount and a zero total", q)
+	}
+}
+
+func TestCoupons(t *testing.T) {
+	c := newCatalog(t)
+	if err := c.AddCoupon(" Save10 ", 0, catalog.PercentOff{Rate: money.Percent(10)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddCoupon("SAVE10", 0, catalog.PercentOff{}); !errors.Is(err, catalog.ErrDuplicateRule) {
+		t.Errorf("AddCoupon with the same code in another case = %v, want ErrDuplicateRule", err)
+	}
+	if err := c.AddCoupon("  ", 0, catalog.PercentOff{}); !errors.Is(err, catalog.ErrInvalid) {
+		t.Errorf("AddCoupon with a blank code = %v, want ErrInvalid", err)
+	}
+
+	lines := []catalog.Line{{SKU: "BOOK", Quantity: 1}}
+	for _, code := range []string{"save10", "SAVE10", " SaVe10"} {
+		q, err := c.Price(lines, code)
+		if err != nil {
+			t.Fatalf("Price with %q: %v", code, err)
+		}
+		if q.Total != money.MustParse("27.00") || q.Discounts[0].Rule != "coupon SAVE10" {
+			t.Errorf("Price with %q = %+v", code, q)
+		}
+	}
+	// The same coupon twice applies once.
+	if q, _ := c.Price(lines, "save10", "SAVE10"); q.Total != money.MustParse("27.00") {
+		t.Errorf("coupon given twice: total %s, want 27.00", q.Total)
+	}
+	if q, _ := c.Price(lines); q.Total != money.MustParse("30.00") {
+		t.Errorf("no coupon: total %s, want 30.00", q.Total)
+	}
+	if _, err := c.Price(lines, "nope"); !errors.Is(err, catalog.ErrUnknownCoupon) {
+		t.Errorf("unknown coupon = %v, want ErrUnknownCoupon", err)
+	}
+}
+
+// TestRuleReadsCatalog checks that rules run without the catalog locked: a
+// rule that looks up another product must not deadlock.
+func TestRuleReadsCatalog(t *testing.T) {
+	c := newCatalog(t)
+	c.AddRule("bundle", 0, bundle{c})
+
+	done := make(chan catalog.Quote)
+	go func() {
+		q, err := c.Price([]catalog.Line{{SKU: "PEN", Quantity: 1}, {SKU: "PAPER", Quantity: 1}})
+		if err != nil {
+			t.Error(err)
+		}
+		done <- q
+	}()
+	select {
+	case q := <-done:
+		if q.Total != money.MustParse("5.00") {
+			t.Errorf("total = %s, want 5.00 (the pen free with paper)", q.Total)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Price deadlocked on a rule that calls Get")
+	}
+}
+
+func TestReserve(t *testing.T) {
+	c := newCatalog(t)
+	avail := func(sku catalog.SKU) int {
+		t.Helper()
+		n, err := c.Available(sku)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	id, err := c.Reserve([]catalog.Line{{SKU: "BOOK", Quantity: 1}, {SKU: "PAPER", Quantity: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avail("BOOK") != 1 || avail("PAPER") != 6 {
+		t.Fatalf("available after Reserve = %d books, %d paper", avail("BOOK"), avail("PAPER"))
+	}
+
+	// A reservation that can't be met in full holds nothing.
+	_, err = c.Reserve([]catalog.Line{{SKU: "PAPER", Quantity: 1}, {SKU: "BOOK", Quantity: 1}, {SKU: "BOOK", Quantity: 1}})
+	var se *catalog.StockError
+	if !errors.As(err, &se) || !errors.Is(err, catalog.ErrOutOfStock) || *se != (catalog.StockError{SKU: "BOOK", Requested: 2, Available: 1}) {
+		t.Fatalf("Reserve over stock = %v, want *StockError for 2 books with 1 available", err)
+	}
+	if avail("PAPER") != 6 {
+		t.Errorf("failed Reserve held paper: %d available, want 6", avail("PAPER"))
+	}
+	if _, err := c.Reserve([]catalog.Line{{SKU: "NOPE", Quantity: 1}}); !errors.Is(err, catalog.ErrUnknownSKU) {
+		t.Errorf("Reserve(NOPE) = %v, want ErrUnknownSKU", err)
+	}
+
+	lines, err := c.Reserved(id)
+	if err != nil || !slices.Equal(lines, []catalog.Line{{SKU: "BOOK", Quantity: 1}, {SKU: "PAPER", Quantity: 4}}) {
+		t.Errorf("Reserved = %v, %v", lines, err)
+	}
+
+	if err := c.Release(id); err != nil {
+		t.Fatal(err)
+	}
+	if avail("BOOK") != 2 || avail("PAPER") != 10 {
+		t.Errorf("available after Release = %d books, %d paper, want 2, 10", avail("BOOK"), avail("PAPER"))
+	}
+	for name, err := range map[string]error{"Release": c.Release(id), "Commit": c.Commit(id)} {
+		if !errors.Is(err, catalog.ErrUnknownReservation) {
+			t.Errorf("%s of a released reservation = %v, want ErrUnknownReservation", name, err)
+		}
+	}
+
+	id, err = c.Reserve([]catalog.Line{{SKU: "BOOK", Quantity: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit(id); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := c.Get("BOOK"); p.Stock != 0 || avail("BOOK") != 0 {
+		t.Errorf("after Commit: stock %d, available %d, want 0, 0", p.Stock, avail("BOOK"))
+	}
+	if err := c.Restock("BOOK", 3); err != nil || avail("BOOK") != 3 {
+		t.Errorf("Restock = %v, available %d, want 3", err, avail("BOOK"))
+	}
+}
+
+// fixedOff takes a fixed amount off one SKU.
+type fixedOff struct {
+	sku    catalog.SKU
+	amount money.Amount
+}
+
+func (r fixedOff) Apply(lines []catalog.QuoteLine) []catalog.Discount {
+	return []catalog.Discount{{SKU: r.sku, Amount: r.amount}}
+}
+
+// bundle makes one pen free when paper is bought, looking the pen's price
+// up in the catalog.
+type bundle struct{ c *catalog.Catalog }
+
+func (r bundle) Apply(lines []catalog.QuoteLine) []catalog.Discount {
+	if !slices.ContainsFunc(lines, func(l catalog.QuoteLine) bool { return l.SKU == "PAPER" }) {
+		return nil
+	}
+	pen, err := r.c.Get("PEN")
+	if err != nil {
+		return nil
+	}
+	return []catalog.Discount{{SKU: "PEN", Amount: pen.Price}}
+}
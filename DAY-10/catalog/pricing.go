@@ -0,0 +1,231 @@
+// catalog/pricing.go
+package catalog
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"usermanagement/money"
+)
+
+// Line asks for Quantity units of a product, as in a cart.
+type Line struct {
+	SKU      SKU `json:"sku"`
+	Quantity int `json:"quantity"`
+}
+
+// QuoteLine is a priced Line. Total is Subtotal minus Discount and never
+// drops below zero.
+type QuoteLine struct {
+	SKU       SKU          `json:"sku"`
+	Name      string       `json:"name"`
+	Category  Category     `json:"category"`
+	Quantity  int          `json:"quantity"`
+	UnitPrice money.Amount `json:"unit_price"`
+	Subtotal  money.Amount `json:"subtotal"`
+	Discount  money.Amount `json:"discount"`
+	Total     money.Amount `json:"total"`
+}
+
+// Discount records how much one rule took off one line.
+type Discount struct {
+	Rule   string       `json:"rule"`
+	SKU    SKU          `json:"sku"`
+	Amount money.Amount `json:"amount"`
+}
+
+// Quote is the price of a set of lines after every discount.
+type Quote struct {
+	Lines     []QuoteLine  `json:"lines"`
+	Discounts []Discount   `json:"discounts"` // In the order they were applied.
+	Subtotal  money.Amount `json:"subtotal"`
+	Discount  money.Amount `json:"discount"`
+	Total     money.Amount `json:"total"`
+}
+
+// Rule is a discount. Apply sees the lines as earlier rules left them and
+// returns how much to take off each; Price caps every discount at what is
+// left of the line, so a Rule doesn't need to.
+type Rule interface {
+	Apply(lines []QuoteLine) []Discount
+}
+
+// rule is a Rule with the name and priority it was added under.
+type rule struct {
+	name     string
+	priority int
+	Rule
+}
+
+// PercentOff takes Rate off every line in Category or SKUs. With neither
+// set it applies to every line. The percentage is of what is left of the
+// line, so two 10% rules take off 19%, not 20%.
+type PercentOff struct {
+	Rate     money.Rate
+	Category Category
+	SKUs     []SKU
+}
+
+func (r PercentOff) Apply(lines []QuoteLine) []Discount {
+	var out []Discount
+	for _, l := range lines {
+		all := r.Category == "" && len(r.SKUs) == 0
+		if all || (r.Category != "" && l.Category == r.Category) || slices.Contains(r.SKUs, l.SKU) {
+			out = append(out, Discount{SKU: l.SKU, Amount: l.Total.Apply(r.Rate)})
+		}
+	}
+	return out
+}
+
+// BuyXGetY makes Get units of SKU free for every Buy units paid for: with
+// Buy 2 and Get 1, three units cost as much as two, and so do four.
+type BuyXGetY struct {
+	SKU SKU
+	Buy int
+	Get int
+}
+
+func (r BuyXGetY) Apply(lines []QuoteLine) []Discount {
+	if r.Buy <= 0 || r.Get <= 0 {
+		return nil
+	}
+	for _, l := range lines {
+		if l.SKU == r.SKU {
+			free := l.Quantity / (r.Buy + r.Get) * r.Get
+			return []Discount{{SKU: l.SKU, Amount: l.UnitPrice.Mul(free)}}
+		}
+	}
+	return nil
+}
+
+// AddRule adds a discount that applies to every quote. Rules run in order
+// of priority, lowest first, and then by name, so the same cart always gets
+// the same price whatever order the rules were added in.
+func (c *Catalog) AddRule(name string, priority int, r Rule) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, existing := range c.rules {
+		if existing.name == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateRule, name)
+		}
+	}
+	c.rules = append(c.rules, rule{name: name, priority: priority, Rule: r})
+	return nil
+}
+
+// AddCoupon adds a discount that only applies to quotes given its code.
+// Codes ignore case. Coupons are ordered along with the automatic rules,
+// under the name "coupon CODE".
+func (c *Catalog) AddCoupon(code string, priority int, r Rule) error {
+	code = couponKey(code)
+	if code == "" {
+		return fmt.Errorf("%w: empty coupon code", ErrInvalid)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.coupons[code]; ok {
+		return fmt.Errorf("%w: coupon %s", ErrDuplicateRule, code)
+	}
+	c.coupons[code] = rule{name: "coupon " + code, priority: priority, Rule: r}
+	return nil
+}
+
+func couponKey(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Price quotes lines with the given coupon codes. Lines for the same SKU
+// are merged, so tier prices see the full quantity, and the quote lists
+// them by SKU.
+//
+// The rules run without the catalog's lock held, so a Rule may call back
+// into the catalog, for example to look up the price of another product.
+func (c *Catalog) Price(lines []Line, coupons ...string) (Quote, error) {
+	merged, err := mergeLines(lines)
+	if err != nil {
+		return Quote{}, err
+	}
+	q, rules, err := c.snapshot(merged, coupons)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	for _, r := range rules {
+		for _, d := range r.Apply(slices.Clone(q.Lines)) {
+			i := slices.IndexFunc(q.Lines, func(l QuoteLine) bool { return l.SKU == d.SKU })
+			if i < 0 || d.Amount <= 0 {
+				continue
+			}
+			l := &q.Lines[i]
+			d.Amount = min(d.Amount, l.Total)
+			if d.Amount == 0 {
+				continue
+			}
+			d.Rule = r.name
+			l.Discount += d.Amount
+			l.Total -= d.Amount
+			q.Discount += d.Amount
+			q.Discounts = append(q.Discounts, d)
+		}
+	}
+	q.Total = q.Subtotal - q.Discount
+	return q, nil
+}
+
+// snapshot prices lines before any discount and collects the rules to run,
+// automatic ones plus the given coupons, in the order they apply. It holds
+// c.mu only while it reads the catalog.
+func (c *Catalog) snapshot(lines []Line, coupons []string) (Quote, []rule, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var q Quote
+	for _, l := range lines {
+		p, err := c.product(l.SKU)
+		if err != nil {
+			return Quote{}, nil, err
+		}
+		unit := p.UnitPrice(l.Quantity)
+		sub := unit.Mul(l.Quantity)
+		q.Lines = append(q.Lines, QuoteLine{
+			SKU: p.SKU, Name: p.Name, Category: p.Category, Quantity: l.Quantity,
+			UnitPrice: unit, Subtotal: sub, Total: sub,
+		})
+		q.Subtotal += sub
+	}
+
+	rules := slices.Clone(c.rules)
+	for _, code := range coupons {
+		r, ok := c.coupons[couponKey(code)]
+		if !ok {
+			return Quote{}, nil, fmt.Errorf("%w: %s", ErrUnknownCoupon, code)
+		}
+		if !slices.ContainsFunc(rules, func(x rule) bool { return x.name == r.name }) {
+			rules = append(rules, r)
+		}
+	}
+	slices.SortFunc(rules, func(a, b rule) int {
+		return cmp.Or(cmp.Compare(a.priority, b.priority), cmp.Compare(a.name, b.name))
+	})
+	return q, rules, nil
+}
+
+// mergeLines adds up the quantities of lines with the same SKU and sorts
+// the result by SKU.
+func mergeLines(lines []Line) ([]Line, error) {
+	var out []Line
+	for _, l := range lines {
+		if l.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: %s: quantity must be positive, got %d", ErrInvalid, l.SKU, l.Quantity)
+		}
+		if i := slices.IndexFunc(out, func(o Line) bool { return o.SKU == l.SKU }); i >= 0 {
+			out[i].Quantity += l.Quantity
+		} else {
+			out = append(out, l)
+		}
+	}
+	slices.SortFunc(out, func(a, b Line) int { return cmp.Compare(a.SKU, b.SKU) })
+	return out, nil
+}
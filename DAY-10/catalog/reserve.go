@@ -0,0 +1,102 @@
+// catalog/reserve.go
+package catalog
+
+import "fmt"
+
+// ReservationID identifies the stock held for one cart.
+type ReservationID int
+
+// StockError is returned when a reservation asks for more units than are
+// available. It wraps ErrOutOfStock.
+type StockError struct {
+	SKU       SKU
+	Requested int
+	Available int
+}
+
+func (e *StockError) Error() string {
+	return fmt.Sprintf("catalog: %s: requested %d, only %d available", e.SKU, e.Requested, e.Available)
+}
+
+func (e *StockError) Unwrap() error {
+	return ErrOutOfStock
+}
+
+// Reserve holds stock for every line, or for none of them: if any product
+// would end up with fewer units in stock than are reserved, nothing is
+// reserved and the error is a *StockError. Held units stay in Stock until
+// Commit, but Available no longer counts them.
+func (c *Catalog) Reserve(lines []Line) (ReservationID, error) {
+	merged, err := mergeLines(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, l := range merged {
+		p, err := c.product(l.SKU)
+		if err != nil {
+			return 0, err
+		}
+		if avail := p.Stock - c.reserved[l.SKU]; l.Quantity > avail {
+			return 0, &StockError{SKU: l.SKU, Requested: l.Quantity, Available: avail}
+		}
+	}
+	for _, l := range merged {
+		c.reserved[l.SKU] += l.Quantity
+	}
+	c.lastRes++
+	c.reservations[c.lastRes] = merged
+	return c.lastRes, nil
+}
+
+// Release gives the stock held by a reservation back, e.g. when a cart is
+// abandoned or checkout fails.
+func (c *Catalog) Release(id ReservationID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines, err := c.takeReservation(id)
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		c.reserved[l.SKU] -= l.Quantity
+	}
+	return nil
+}
+
+// Commit turns a reservation into a sale: its units leave Stock for good.
+func (c *Catalog) Commit(id ReservationID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines, err := c.takeReservation(id)
+	if err != nil {
+		return err
+	}
+	for _, l := range lines {
+		c.reserved[l.SKU] -= l.Quantity
+		c.products[l.SKU].Stock -= l.Quantity
+	}
+	return nil
+}
+
+// Reserved returns the lines held by a reservation.
+func (c *Catalog) Reserved(id ReservationID) ([]Line, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines, ok := c.reservations[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownReservation, id)
+	}
+	return append([]Line(nil), lines...), nil
+}
+
+func (c *Catalog) takeReservation(id ReservationID) ([]Line, error) {
+	lines, ok := c.reservations[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownReservation, id)
+	}
+	delete(c.reservations, id)
+	return lines, nil
+}
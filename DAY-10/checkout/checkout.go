@@ -0,0 +1,148 @@
+// checkout/checkout.go
+package checkout // Turn a cart into an order: reserve stock, charge, confirm, and undo on failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"usermanagement/cart"
+	"usermanagement/catalog"
+	"usermanagement/model"
+)
+
+var (
+	ErrEmptyCart     = errors.New("checkout: cart is empty")
+	ErrInactiveUser  = errors.New("checkout: user is not active")
+	ErrOrderNotFound = errors.New("checkout: order not found")
+)
+
+// Order is a completed checkout.
+type Order struct {
+	ID          int                   `json:"id"`
+	UserID      int                   `json:"user_id"`
+	ShipTo      model.Address         `json:"ship_to"`
+	Lines       []catalog.Line        `json:"lines"`
+	Coupons     []string              `json:"coupons,omitempty"`
+	Totals      cart.Totals           `json:"totals"`
+	Reservation catalog.ReservationID `json:"reservation"`
+	ChargeID    string                `json:"charge_id"`
+	PlacedAt    time.Time             `json:"placed_at"`
+}
+
+// Service runs checkouts against a catalog and a payment provider.
+type Service struct {
+	catalog *catalog.Catalog
+	payment Payment
+	tax     cart.TaxRules
+
+	// Now returns the current time. Tests can replace it.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	orders []Order
+	lastID int
+}
+
+// NewService returns a Service that sells from c, charges through p and
+// taxes according to tax.
+func NewService(c *catalog.Catalog, p Payment, tax cart.TaxRules) *Service {
+	return &Service{catalog: c, payment: p, tax: tax, Now: time.Now}
+}
+
+// Checkout places an order for everything in ct, shipped to u's address.
+// It runs as a saga:
+//
+//  1. reserve the stock for every line (undo: release it)
+//  2. charge the total with tax (undo: refund it)
+//  3. confirm: take the reserved units out of stock and record the order
+//
+// If a step fails, the steps before it are undone in reverse order, so a
+// failed checkout leaves neither stock held nor money taken. The error is a
+// *StepError naming the step. On success the cart is cleared.
+func (s *Service) Checkout(ctx context.Context, ct *cart.Cart, u model.User) (Order, error) {
+	if ct.Empty() {
+		return Order{}, ErrEmptyCart
+	}
+	if !u.IsActive || u.Deleted() {
+		return Order{}, fmt.Errorf("%w: %d", ErrInactiveUser, u.ID)
+	}
+	totals, err := ct.Totals(s.tax, u.Address)
+	if err != nil {
+		return Order{}, err
+	}
+
+	s.mu.Lock()
+	s.lastID++
+	o := Order{ID: s.lastID, UserID: u.ID, ShipTo: u.Address, Lines: ct.Lines(), Coupons: ct.Coupons(), Totals: totals}
+	s.mu.Unlock()
+
+	err = runSaga(ctx, []step{
+		{
+			name: "reserve stock",
+			do: func(ctx context.Context) error {
+				id, err := s.catalog.Reserve(o.Lines)
+				o.Reservation = id
+				return err
+			},
+			undo: func(ctx context.Context) error { return s.catalog.Release(o.Reservation) },
+		},
+		{
+			name: "charge payment",
+			do: func(ctx context.Context) error {
+				id, err := s.payment.Charge(ctx, fmt.Sprintf("order-%d", o.ID), o.Totals.Total)
+				o.ChargeID = id
+				return err
+			},
+			undo: func(ctx context.Context) error { return s.payment.Refund(ctx, o.ChargeID) },
+		},
+		{
+			name: "confirm order",
+			do:   func(ctx context.Context) error { return s.commit(&o) },
+		},
+	})
+	if err != nil {
+		return Order{}, err
+	}
+	ct.Clear()
+	return o, nil
+}
+
+// commit is the confirm step: the sale becomes final.
+func (s *Service) commit(o *Order) error {
+	if err := s.catalog.Commit(o.Reservation); err != nil {
+		return err
+	}
+	o.PlacedAt = s.Now()
+	s.mu.Lock()
+	s.orders = append(s.orders, *o)
+	s.mu.Unlock()
+	return nil
+}
+
+// Order returns a placed order by ID.
+func (s *Service) Order(id int) (Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.orders, func(o Order) bool { return o.ID == id })
+	if i < 0 {
+		return Order{}, fmt.Errorf("%w: %d", ErrOrderNotFound, id)
+	}
+	return s.orders[i], nil
+}
+
+// Orders returns every placed order of a user, oldest first.
+func (s *Service) Orders(userID int) []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Order
+	for _, o := range s.orders {
+		if o.UserID == userID {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,224 @@
+// checkout/checkout_test.go
+package checkout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"usermanagement/cart"
+	"usermanagement/catalog"
+	"usermanagement/checkout"
+	"usermanagement/model"
+	"usermanagement/money"
+)
+
+var (
+	placedAt = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	buyer    = model.User{ID: 7, Name: "Alice", Email: "alice@example.com", IsActive: true,
+		Address: model.Address{City: "Berlin", ZipCode: "10115", Country: "DE"}}
+	tax = cart.TaxRules{Rates: map[string]money.Rate{"DE": money.Percent(19)}}
+)
+
+// fixture is a catalog with 10 pens, a payment provider and a cart holding
+// 3 pens at 2.00.
+type fixture struct {
+	catalog *catalog.Catalog
+	payment *checkout.FakePayment
+	service *checkout.Service
+	cart    *cart.Cart
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{catalog: catalog.New(), payment: &checkout.FakePayment{}}
+	if err := f.catalog.Add(catalog.Product{SKU: "PEN", Name: "Pen", Price: money.MustParse("2.00"), Stock: 10}); err != nil {
+		t.Fatal(err)
+	}
+	f.service = checkout.NewService(f.catalog, f.payment, tax)
+	f.service.Now = func() time.Time { return placedAt }
+	f.cart = cart.New(f.catalog)
+	if err := f.cart.Add("PEN", 3); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// stock returns the pens in stock and how many of them are available.
+func (f *fixture) stock(t *testing.T) (stock, available int) {
+	t.Helper()
+	p, err := f.catalog.Get("PEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	available, err = f.catalog.Available("PEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.Stock, available
+}
+
+func TestCheckout(t *testing.T) {
+	f := newFixture(t)
+	o, err := f.service.Checkout(context.Background(), f.cart, buyer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := money.MustParse("7.14") // 6.00 plus 19% tax.
+	if o.ID != 1 || o.UserID != buyer.ID || o.Totals.Total != total || o.ChargeID != "ch_1" || !o.PlacedAt.Equal(placedAt) {
+		t.Errorf("order = %+v", o)
+	}
+	if stock, avail := f.stock(t); stock != 7 || avail != 7 {
+		t.Errorf("stock, available = %d, %d, want 7, 7", stock, avail)
+	}
+	if f.payment.Balance() != total {
+		t.Errorf("charged %s, want %s", f.payment.Balance(), total)
+	}
+	if !f.cart.Empty() {
+		t.Error("cart not cleared after checkout")
+	}
+	if got, err := f.service.Order(o.ID); err != nil || got.ChargeID != o.ChargeID {
+		t.Errorf("Order(%d) = %+v, %v", o.ID, got, err)
+	}
+	if got := f.service.Orders(buyer.ID); len(got) != 1 {
+		t.Errorf("Orders = %+v, want one", got)
+	}
+}
+
+func TestCheckoutRejects(t *testing.T) {
+	f := newFixture(t)
+	if _, err := f.service.Checkout(context.Background(), cart.New(f.catalog), buyer); !errors.Is(err, checkout.ErrEmptyCart) {
+		t.Errorf("empty cart = %v, want ErrEmptyCart", err)
+	}
+	inactive := buyer
+	inactive.IsActive = false
+	if _, err := f.service.Checkout(context.Background(), f.cart, inactive); !errors.Is(err, checkout.ErrInactiveUser) {
+		t.Errorf("inactive user = %v, want ErrInactiveUser", err)
+	}
+	if _, err := f.service.Order(1); !errors.Is(err, checkout.ErrOrderNotFound) {
+		t.Errorf("Order(1) = %v, want ErrOrderNotFound", err)
+	}
+	if len(f.payment.Charges()) != 0 {
+		t.Errorf("rejected checkouts charged %v", f.payment.Charges())
+	}
+}
+
+func TestPaymentFailureReleasesStock(t *testing.T) {
+	f := newFixture(t)
+	decline := errors.New("card expired")
+	f.payment.Decline = func(ref string, amount money.Amount) error {
+		// The stock is held while the payment runs.
+		if _, avail := f.stock(t); avail != 7 {
+			t.Errorf("available during payment = %d, want 7", avail)
+		}
+		return decline
+	}
+
+	_, err := f.service.Checkout(context.Background(), f.cart, buyer)
+	var se *checkout.StepError
+	if !errors.As(err, &se) || se.Step != "charge payment" || se.Compensation != nil {
+		t.Fatalf("Checkout = %#v, want a *StepError for the payment without compensation errors", err)
+	}
+	if !errors.Is(err, checkout.ErrDeclined) || !errors.Is(err, decline) {
+		t.Errorf("Checkout = %v, want it to wrap ErrDeclined and the decline reason", err)
+	}
+	if stock, avail := f.stock(t); stock != 10 || avail != 10 {
+		t.Errorf("stock, available = %d, %d, want 10, 10: the reservation was not released", stock, avail)
+	}
+	if f.cart.Empty() {
+		t.Error("a failed checkout cleared the cart")
+	}
+	if got := f.service.Orders(buyer.ID); len(got) != 0 {
+		t.Errorf("failed checkout recorded orders %+v", got)
+	}
+}
+
+func TestCommitFailureRefunds(t *testing.T) {
+	f := newFixture(t)
+	// Something else drops the reservation while the payment runs, so the
+	// confirm step finds nothing to commit.
+	f.payment.Decline = func(ref string, amount money.Amount) error {
+		return f.catalog.Release(1)
+	}
+
+	_, err := f.service.Checkout(context.Background(), f.cart, buyer)
+	var se *checkout.StepError
+	if !errors.As(err, &se) || se.Step != "confirm order" {
+		t.Fatalf("Checkout = %v, want a *StepError for the confirm step", err)
+	}
+	if !errors.Is(se.Err, catalog.ErrUnknownReservation) {
+		t.Errorf("StepError.Err = %v, want ErrUnknownReservation", se.Err)
+	}
+	charges := f.payment.Charges()
+	if len(charges) != 1 || !charges[0].Refunded || f.payment.Balance() != 0 {
+		t.Errorf("charges = %+v, want one refunded charge", charges)
+	}
+	if stock, avail := f.stock(t); stock != 10 || avail != 10 {
+		t.Errorf("stock, available = %d, %d, want 10, 10", stock, avail)
+	}
+	// Releasing the reservation again fails; the error says so.
+	if !errors.Is(se.Compensation, catalog.ErrUnknownReservation) {
+		t.Errorf("Compensation = %v, want the failed release", se.Compensation)
+	}
+}
+
+func TestCompensationErrors(t *testing.T) {
+	f := newFixture(t)
+	// The payment is declined, and undoing the reservation fails because
+	// it is already gone.
+	f.payment.Decline = func(ref string, amount money.Amount) error {
+		f.catalog.Release(1)
+		return errors.New("insufficient funds")
+	}
+
+	_, err := f.service.Checkout(context.Background(), f.cart, buyer)
+	var se *checkout.StepError
+	if !errors.As(err, &se) {
+		t.Fatalf("Checkout = %v, want a *StepError", err)
+	}
+	// The error unwraps to both the failure and the failed compensation.
+	if !errors.Is(err, checkout.ErrDeclined) {
+		t.Errorf("Checkout = %v, want it to wrap ErrDeclined", err)
+	}
+	if !errors.Is(err, catalog.ErrUnknownReservation) {
+		t.Errorf("Checkout = %v, want it to wrap the failed release", err)
+	}
+	if len(se.Unwrap()) != 2 {
+		t.Errorf("Unwrap = %v, want the cause and the compensation error", se.Unwrap())
+	}
+}
+
+func TestCancelledCheckoutCompensates(t *testing.T) {
+	f := newFixture(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The request is cancelled once the payment went through: the saga
+	// stops before confirming and must still refund and release, even
+	// though ctx is done.
+	f.payment.Decline = func(ref string, amount money.Amount) error {
+		cancel()
+		return nil
+	}
+
+	_, err := f.service.Checkout(ctx, f.cart, buyer)
+	var se *checkout.StepError
+	if !errors.As(err, &se) || se.Step != "confirm order" || !errors.Is(err, context.Canceled) || se.Compensation != nil {
+		t.Fatalf("Checkout = %v, want a cancelled confirm step with clean compensation", err)
+	}
+	if f.payment.Balance() != 0 {
+		t.Errorf("balance = %s after a cancelled checkout, want 0", f.payment.Balance())
+	}
+	if _, avail := f.stock(t); avail != 10 {
+		t.Errorf("available = %d after a cancelled checkout, want 10", avail)
+	}
+
+	// A request cancelled before it starts does nothing at all.
+	_, err = f.service.Checkout(ctx, f.cart, buyer)
+	if !errors.As(err, &se) || se.Step != "reserve stock" || !errors.Is(err, context.Canceled) {
+		t.Errorf("Checkout with a done context = %v", err)
+	}
+	if len(f.payment.Charges()) != 1 {
+		t.Errorf("charges = %+v, want only the refunded one", f.payment.Charges())
+	}
+}
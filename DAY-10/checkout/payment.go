@@ -0,0 +1,91 @@
+// checkout/payment.go
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"usermanagement/money"
+)
+
+var (
+	ErrDeclined      = errors.New("checkout: payment declined")
+	ErrUnknownCharge = errors.New("checkout: unknown charge")
+)
+
+// Payment takes money from a customer. Charge returns an ID that Refund
+// uses to give the whole amount back. ref identifies the order, so a
+// provider can recognise a retried charge.
+type Payment interface {
+	Charge(ctx context.Context, ref string, amount money.Amount) (chargeID string, err error)
+	Refund(ctx context.Context, chargeID string) error
+}
+
+// Charge is a payment recorded by FakePayment.
+type Charge struct {
+	ID       string
+	Ref      string
+	Amount   money.Amount
+	Refunded bool
+}
+
+// FakePayment is an in-memory Payment for tests and demos. It accepts every
+// charge unless Decline says otherwise.
+type FakePayment struct {
+	// Decline, if set, is asked about every charge; a non-nil error
+	// declines it. The error is wrapped in ErrDeclined.
+	Decline func(ref string, amount money.Amount) error
+
+	mu      sync.Mutex
+	charges []Charge
+}
+
+var _ Payment = (*FakePayment)(nil)
+
+func (p *FakePayment) Charge(ctx context.Context, ref string, amount money.Amount) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if p.Decline != nil {
+		if err := p.Decline(ref, amount); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrDeclined, err)
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := Charge{ID: fmt.Sprintf("ch_%d", len(p.charges)+1), Ref: ref, Amount: amount}
+	p.charges = append(p.charges, c)
+	return c.ID, nil
+}
+
+func (p *FakePayment) Refund(ctx context.Context, chargeID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.charges {
+		if p.charges[i].ID == chargeID && !p.charges[i].Refunded {
+			p.charges[i].Refunded = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownCharge, chargeID)
+}
+
+// Charges returns every charge made so far, refunded ones included.
+func (p *FakePayment) Charges() []Charge {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Charge(nil), p.charges...)
+}
+
+// Balance is the total charged and not refunded.
+func (p *FakePayment) Balance() money.Amount {
+	var sum money.Amount
+	for _, c := range p.Charges() {
+		if !c.Refunded {
+			sum += c.Amount
+		}
+	}
+	return sum
+}
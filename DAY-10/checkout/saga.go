@@ -0,0 +1,71 @@
+// checkout/saga.go
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// step is one action of a saga and the action that undoes it. undo may be
+// nil for a step with nothing to take back.
+type step struct {
+	name string
+	do   func(ctx context.Context) error
+	undo func(ctx context.Context) error
+}
+
+// StepError reports which step of a checkout failed. If undoing the earlier
+// steps failed too, Compensation says why; those steps may need fixing by
+// hand.
+type StepError struct {
+	Step         string
+	Err          error
+	Compensation error
+}
+
+func (e *StepError) Error() string {
+	msg := fmt.Sprintf("checkout: %s: %v", e.Step, e.Err)
+	if e.Compensation != nil {
+		msg += fmt.Sprintf(" (and undoing earlier steps failed: %v)", e.Compensation)
+	}
+	return msg
+}
+
+func (e *StepError) Unwrap() []error {
+	if e.Compensation == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Compensation}
+}
+
+// runSaga runs steps in order. When one fails, or ctx is cancelled between
+// steps, the steps that completed are undone in reverse order. Undoing uses
+// a context that is not cancelled with ctx: a cancelled request must still
+// give back what it took.
+func runSaga(ctx context.Context, steps []step) error {
+	var done []step
+	for _, s := range steps {
+		err := ctx.Err()
+		if err == nil {
+			err = s.do(ctx)
+		}
+		if err != nil {
+			return &StepError{Step: s.name, Err: err, Compensation: compensate(context.WithoutCancel(ctx), done)}
+		}
+		done = append(done, s)
+	}
+	return nil
+}
+
+func compensate(ctx context.Context, done []step) error {
+	var errs []error
+	for i := len(done) - 1; i >= 0; i-- {
+		if s := done[i]; s.undo != nil {
+			if err := s.undo(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("undo %s: %w", s.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+// cmd/server/main.go
+package main
+
+// server exposes users and products over HTTP. Run it from the DAY-10
+// directory with:
+//
+//	go run ./cmd/server -addr :8080
+//
+// Press Ctrl+C to stop it: in-flight requests get a few seconds to finish
+// before the process exits.
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"usermanagement/api"
+	"usermanagement/model"
+	"usermanagement/product"
+	"usermanagement/store"
+	"usermanagement/user"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	seed := flag.Bool("seed", false, "add the user and products from main.go on startup")
+	grace := flag.Duration("grace", 10*time.Second, "how long to wait for in-flight requests on shutdown")
+	data := flag.String("data", "", "database file; empty keeps everything in memory")
+	flag.Parse()
+
+	if err := run(*addr, *data, *seed, *grace); err != nil {
+		log.Fatal(err)
+	}
+	log.Print("bye")
+}
+
+// run serves until the server fails or the process is told to stop. It
+// returns instead of exiting so the database is always closed, and a
+// failing Close is reported along with any other error.
+func run(addr, data string, seed bool, grace time.Duration) (err error) {
+	var userRepo user.Repository = user.NewMemoryRepository()
+	var productRepo product.Repository = product.NewMemoryRepository()
+	if data != "" {
+		var db *store.DB
+		if db, err = store.Open(data, store.Options{CompactInterval: time.Minute}); err != nil {
+			return err
+		}
+		defer func() { err = errors.Join(err, db.Close()) }()
+		if db.Truncated > 0 {
+			log.Printf("recovered %s: dropped %d bytes of an interrupted write", data, db.Truncated)
+		}
+		if userRepo, err = store.NewUserRepository(db); err != nil {
+			return err
+		}
+		if productRepo, err = store.NewProductRepository(db); err != nil {
+			return err
+		}
+	}
+
+	users := user.NewService(userRepo)
+	products := product.NewService(productRepo)
+	if seed {
+		seedData(users, products)
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           api.NewServer(users, products),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s", addr)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Print("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func seedData(users *user.Service, products *product.Service) {
+	ctx := context.Background()
+	users.Create(ctx, user.NewUser{
+		Name:  "Alice Wonderland",
+		Email: "alice@example.com",
+		Address: model.Address{
+			Street:  "123 Main St",
+			City:    "Wonderland",
+			ZipCode: "90210",
+			Country: "Fantasy",
+		},
+	})
+	products.Create(ctx, model.Product{Name: "Laptop", Price: 1200.0, Stock: 5})
+	products.Create(ctx, model.Product{Name: "Desktop", Price: 900.0, Stock: 3})
+}
@@ -0,0 +1,224 @@
+// codec/codec.go
+package codec // Encode and decode values as JSON, YAML, TOML, CSV or MessagePack, driven by their json tags
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a serialization format.
+type Format string
+
+const (
+	JSON        Format = "json"
+	YAML        Format = "yaml"
+	TOML        Format = "toml"
+	CSV         Format = "csv"
+	MessagePack Format = "msgpack"
+)
+
+// Formats lists every supported format.
+var Formats = []Format{JSON, YAML, TOML, CSV, MessagePack}
+
+var ErrUnknownFormat = errors.New("codec: unknown format")
+
+// ParseFormat accepts a format name or a file name with a known extension,
+// such as "users.yml".
+func ParseFormat(s string) (Format, error) {
+	name := strings.ToLower(s)
+	if ext := filepath.Ext(name); ext != "" {
+		name = ext[1:]
+	}
+	switch name {
+	case "json", "jsonl", "ndjson":
+		return JSON, nil
+	case "yaml", "yml":
+		return YAML, nil
+	case "toml":
+		return TOML, nil
+	case "csv":
+		return CSV, nil
+	case "msgpack", "mpk", "messagepack":
+		return MessagePack, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// tomlKey holds the records when a list is written as TOML, whose
+// documents must be tables:
+//
+//	[[items]]
+//	id = 1
+//	name = "Alice"
+const tomlKey = "items"
+
+// Every format goes through the JSON form of a value: v is marshalled with
+// encoding/json and decoded into maps, slices and scalars (a "document"),
+// which the other formats encode. Decoding runs the same way backwards. So
+// the json tags (names, omitempty, omitzero, "-") and the MarshalJSON and
+// MarshalText methods of a type apply to every format, and a User written
+// as YAML has the same keys as a User written as JSON.
+
+// Marshal encodes v as one document in format f. For CSV, v is a struct
+// (one row) or a slice of structs; for TOML a slice becomes an array of
+// tables named "items".
+func Marshal(f Format, v any) ([]byte, error) {
+	switch f {
+	case JSON:
+		return json.Marshal(v)
+	case CSV:
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf, CSV)
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice {
+			enc.csv.elem = rv.Type().Elem()
+			for i := range rv.Len() {
+				if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+					return nil, err
+				}
+			}
+		} else if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	doc, err := toDoc(v)
+	if err != nil {
+		return nil, err
+	}
+	switch f {
+	case YAML:
+		return yaml.Marshal(doc)
+	case TOML:
+		if _, ok := doc.(map[string]any); !ok {
+			doc = map[string]any{tomlKey: doc}
+		}
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(doc)
+		return buf.Bytes(), err
+	case MessagePack:
+		return msgpack.Marshal(doc)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+}
+
+// Unmarshal decodes one document in format f into the value v points to.
+func Unmarshal(f Format, data []byte, v any) error {
+	switch f {
+	case JSON:
+		return json.Unmarshal(data, v)
+	case CSV:
+		return unmarshalCSV(data, v)
+	}
+
+	var doc any
+	switch f {
+	case YAML:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+	case TOML:
+		var table map[string]any
+		if err := toml.Unmarshal(data, &table); err != nil {
+			return err
+		}
+		doc = table
+		if items, ok := table[tomlKey]; ok && isSlicePtr(v) {
+			doc = items
+		}
+	case MessagePack:
+		if err := msgpack.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+	return fromDoc(doc, v)
+}
+
+func isSlicePtr(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Slice
+}
+
+// toDoc returns the JSON form of v as a document. Numbers become int64
+// when they are whole and fit, float64 otherwise, which every format can
+// hold.
+func toDoc(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return numbers(doc), nil
+}
+
+func numbers(doc any) any {
+	switch d := doc.(type) {
+	case map[string]any:
+		for k, v := range d {
+			d[k] = numbers(v)
+		}
+	case []any:
+		for i, v := range d {
+			d[i] = numbers(v)
+		}
+	case json.Number:
+		if n, err := strconv.ParseInt(string(d), 10, 64); err == nil {
+			return n
+		}
+		f, _ := d.Float64()
+		return f
+	}
+	return doc
+}
+
+// fromDoc stores a decoded document in v by way of its JSON form.
+func fromDoc(doc any, v any) error {
+	data, err := json.Marshal(jsonSafe(doc))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jsonSafe converts what YAML and MessagePack decoders may produce but
+// encoding/json can't marshal, namely maps with non-string keys.
+func jsonSafe(doc any) any {
+	switch d := doc.(type) {
+	case map[string]any:
+		for k, v := range d {
+			d[k] = jsonSafe(v)
+		}
+	case map[any]any:
+		m := make(map[string]any, len(d))
+		for k, v := range d {
+			m[fmt.Sprint(k)] = jsonSafe(v)
+		}
+		return m
+	case []any:
+		for i, v := range d {
+			d[i] = jsonSafe(v)
+		}
+	}
+	return doc
+}
@@ -0,0 +1,105 @@
+// codec/codec_test.go
+package codec_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"usermanagement/catalog"
+	"usermanagement/codec"
+	"usermanagement/model"
+	"usermanagement/money"
+	"usermanagement/structutil"
+)
+
+// The samples include the awkward cases: quotes, commas and newlines that
+// CSV must escape, non-ASCII text, a zero and a set time, empty nested
+// structs and numbers too large for a float32.
+var users = []model.User{
+	{
+		ID: 1, Name: "Alice Wonderland", Email: "alice@example.com", IsActive: true,
+		Address: model.Address{Street: "123 Rabbit Hole", City: "Wonderland", ZipCode: "12345", Country: "Fantasy"},
+	},
+	{
+		ID: 2, Name: `Bob "the builder", Jr.`, Email: "bob@example.com",
+		Address:   model.Address{Street: "1 Main St\nFlat 2", City: "Zürich", ZipCode: "8001", Country: "Switzerland"},
+		DeletedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+	},
+	{ID: 3, Name: "Carol", Email: "carol@example.com"}, // Empty address.
+}
+
+var products = []model.Product{
+	{ID: 1, Name: "Laptop", Price: 1200.00, Stock: 10},
+	{ID: 2, Name: "Mouse", Price: 25.50, Stock: 0},
+	{ID: 3, Name: "Cable, 2m", Price: 0.99, Stock: 1 << 40},
+}
+
+var catalogProducts = []catalog.Product{
+	{SKU: "PEN", Name: "Pen", Category: "office", Price: money.MustParse("1.99"), Stock: 100,
+		Tiers: []catalog.PriceTier{{MinQty: 10, Price: money.MustParse("1.50")}}},
+	{SKU: "BOOK", Name: "The Go Programming Language", Category: "books", Price: money.MustParse("39.00")},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range codec.Formats {
+		t.Run(string(f), func(t *testing.T) {
+			t.Run("users", func(t *testing.T) { roundTrip(t, f, users) })
+			t.Run("products", func(t *testing.T) { roundTrip(t, f, products) })
+			t.Run("catalog", func(t *testing.T) { roundTrip(t, f, catalogProducts) })
+			t.Run("empty", func(t *testing.T) { roundTrip(t, f, []model.User{}) })
+		})
+	}
+}
+
+func roundTrip[T any](t *testing.T, f codec.Format, want []T) {
+	t.Helper()
+	data, err := codec.Marshal(f, want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got []T
+	if err := codec.Unmarshal(f, data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, data)
+	}
+	compare(t, want, got)
+}
+
+// compare fails the test if got and want hold different records.
+func compare[T any](t *testing.T, want, got []T) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		// Diff compares times with Equal, so a time that lost its
+		// monotonic reading or location name still matches.
+		for _, c := range structutil.Diff(want[i], got[i]) {
+			t.Errorf("record %d: %v", i+1, c)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want codec.Format
+	}{
+		{"json", codec.JSON},
+		{"users.JSONL", codec.JSON},
+		{"yml", codec.YAML},
+		{"export.yaml", codec.YAML},
+		{"TOML", codec.TOML},
+		{"data.csv", codec.CSV},
+		{"msgpack", codec.MessagePack},
+		{"dump.mpk", codec.MessagePack},
+	}
+	for _, tt := range tests {
+		if got, err := codec.ParseFormat(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := codec.ParseFormat("users.xml"); !errors.Is(err, codec.ErrUnknownFormat) {
+		t.Errorf("ParseFormat(users.xml) error = %v, want ErrUnknownFormat", err)
+	}
+}
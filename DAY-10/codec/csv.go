@@ -0,0 +1,269 @@
+// codec/csv.go
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// CSV is flat, so nested structs are spread over several columns named
+// after their JSON path: a User becomes
+//
+//	id,name,email,is_active,address.street,address.city,address.zip_code,address.country,deleted_at
+//
+// Columns come from the struct type, in field order, so every row has the
+// same ones even when omitempty drops a field. Strings, and types that
+// marshal to text such as time.Time, are written as they are; anything
+// else, including slices and maps, is written as JSON.
+
+// column is one CSV column.
+type column struct {
+	name   string   // Header, e.g. "address.zip_code".
+	path   []string // JSON keys from the record to the value.
+	quoted bool     // The JSON value is a string.
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// columns lists the columns of struct type t.
+func columns(t reflect.Type) ([]column, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("codec: CSV records must be structs, got %s", t)
+	}
+	var cols []column
+	addColumns(&cols, t, nil)
+	return cols, nil
+}
+
+func addColumns(cols *[]column, t reflect.Type, prefix []string) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			addColumns(cols, ft, prefix) // Promoted fields, as encoding/json does.
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		path := append(append([]string(nil), prefix...), name)
+		if ft.Kind() == reflect.Struct && !unmarshals(ft) {
+			addColumns(cols, ft, path)
+			continue
+		}
+		*cols = append(*cols, column{
+			name:   strings.Join(path, "."),
+			path:   path,
+			quoted: ft.Kind() == reflect.String || reflect.PointerTo(ft).Implements(textUnmarshaler),
+		})
+	}
+}
+
+func unmarshals(t reflect.Type) bool {
+	p := reflect.PointerTo(t)
+	return p.Implements(jsonUnmarshaler) || p.Implements(textUnmarshaler)
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	cols   []column
+	elem   reflect.Type // If set, the header is written even with no rows.
+	header bool
+}
+
+func (e *csvEncoder) init(w io.Writer) {
+	e.w = csv.NewWriter(w)
+}
+
+func (e *csvEncoder) writeHeader(t reflect.Type) error {
+	cols, err := columns(t)
+	if err != nil {
+		return err
+	}
+	e.cols, e.header = cols, true
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+	return e.w.Write(names)
+}
+
+func (e *csvEncoder) encode(v any) error {
+	if !e.header {
+		if err := e.writeHeader(reflect.TypeOf(v)); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	row := make([]string, len(e.cols))
+	for i, c := range e.cols {
+		val := doc
+		for _, k := range c.path {
+			m, _ := val.(map[string]any)
+			val = m[k]
+		}
+		switch val := val.(type) {
+		case nil:
+		case string:
+			row[i] = val
+		default:
+			cell, err := json.Marshal(val)
+			if err != nil {
+				return err
+			}
+			row[i] = string(cell)
+		}
+	}
+	return e.w.Write(row)
+}
+
+func (e *csvEncoder) flush() error {
+	if !e.header && e.elem != nil {
+		if err := e.writeHeader(e.elem); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type csvDecoder struct {
+	r    *csv.Reader
+	cols []column // In the order of the header row.
+	line int
+}
+
+func (d *csvDecoder) init(r io.Reader) {
+	d.r = csv.NewReader(r)
+}
+
+func (d *csvDecoder) readHeader(t reflect.Type) error {
+	known, err := columns(t)
+	if err != nil {
+		return err
+	}
+	header, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	d.line++
+	for _, name := range header {
+		i := -1
+		for j, c := range known {
+			if c.name == name {
+				i = j
+			}
+		}
+		if i < 0 {
+			return fmt.Errorf("codec: CSV column %q has no matching field in %s", name, t)
+		}
+		d.cols = append(d.cols, known[i])
+	}
+	return nil
+}
+
+func (d *csvDecoder) decode(v any) error {
+	if d.cols == nil {
+		if err := d.readHeader(reflect.TypeOf(v)); err != nil {
+			return err
+		}
+	}
+	rec, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	d.line++
+
+	doc := make(map[string]any)
+	for i, cell := range rec {
+		c := d.cols[i]
+		if cell == "" {
+			continue
+		}
+		var val any = cell
+		if !c.quoted {
+			if !json.Valid([]byte(cell)) {
+				return fmt.Errorf("codec: CSV line %d, column %s: %q is not a valid value", d.line, c.name, cell)
+			}
+			val = json.RawMessage(cell)
+		}
+		m := doc
+		for _, k := range c.path[:len(c.path)-1] {
+			sub, ok := m[k].(map[string]any)
+			if !ok {
+				sub = make(map[string]any)
+				m[k] = sub
+			}
+			m = sub
+		}
+		m[c.path[len(c.path)-1]] = val
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("codec: CSV line %d: %w", d.line, err)
+	}
+	return nil
+}
+
+// unmarshalCSV decodes every row into *[]T, or the first row into *T.
+func unmarshalCSV(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("codec: need a non-nil pointer, got %T", v)
+	}
+	var d csvDecoder
+	d.init(bytes.NewReader(data))
+	if rv.Elem().Kind() != reflect.Slice {
+		return d.decode(v)
+	}
+	list := reflect.MakeSlice(rv.Elem().Type(), 0, 0)
+	for {
+		item := reflect.New(rv.Elem().Type().Elem())
+		err := d.decode(item.Interface())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		list = reflect.Append(list, item.Elem())
+	}
+	rv.Elem().Set(list)
+	return nil
+}
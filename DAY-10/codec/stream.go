@@ -0,0 +1,171 @@
+// codec/stream.go
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder writes a stream of records, one per Encode call, without holding
+// them all in memory:
+//
+//	JSON         one object per line (JSON Lines)
+//	YAML         one document per record, separated by ---
+//	TOML         one [[items]] table per record
+//	CSV          a header row, then one row per record
+//	MessagePack  one value per record, back to back
+//
+// Call Close when done; it flushes buffered output but does not close w.
+type Encoder struct {
+	f    Format
+	w    io.Writer
+	json *json.Encoder
+	yaml *yaml.Encoder
+	mp   *msgpack.Encoder
+	csv  csvEncoder
+}
+
+// NewEncoder returns an Encoder writing format f to w.
+func NewEncoder(w io.Writer, f Format) *Encoder {
+	e := &Encoder{f: f, w: w}
+	switch f {
+	case JSON:
+		e.json = json.NewEncoder(w)
+	case YAML:
+		e.yaml = yaml.NewEncoder(w)
+	case MessagePack:
+		e.mp = msgpack.NewEncoder(w)
+	case CSV:
+		e.csv.init(w)
+	}
+	return e
+}
+
+// Encode writes one record.
+func (e *Encoder) Encode(v any) error {
+	switch e.f {
+	case JSON:
+		return e.json.Encode(v)
+	case CSV:
+		return e.csv.encode(v)
+	}
+	doc, err := toDoc(v)
+	if err != nil {
+		return err
+	}
+	switch e.f {
+	case YAML:
+		return e.yaml.Encode(doc)
+	case TOML:
+		return toml.NewEncoder(e.w).Encode(map[string]any{tomlKey: []any{doc}})
+	case MessagePack:
+		return e.mp.Encode(doc)
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, e.f)
+}
+
+// Close flushes any buffered output.
+func (e *Encoder) Close() error {
+	switch e.f {
+	case YAML:
+		return e.yaml.Close()
+	case CSV:
+		return e.csv.flush()
+	}
+	return nil
+}
+
+// Decoder reads a stream written by an Encoder, one record per Decode
+// call. Decode returns io.EOF after the last record.
+//
+// TOML has no way to mark where one record ends, so a TOML Decoder reads
+// the whole input on the first call; every other format reads only as far
+// as the record it returns.
+type Decoder struct {
+	f    Format
+	r    io.Reader
+	json *json.Decoder
+	yaml *yaml.Decoder
+	mp   *msgpack.Decoder
+	csv  csvDecoder
+	toml []any // Records not yet returned.
+	read bool  // Whether the TOML input has been read.
+}
+
+// NewDecoder returns a Decoder reading format f from r.
+func NewDecoder(r io.Reader, f Format) *Decoder {
+	d := &Decoder{f: f, r: r}
+	switch f {
+	case JSON:
+		d.json = json.NewDecoder(r)
+	case YAML:
+		d.yaml = yaml.NewDecoder(r)
+	case MessagePack:
+		d.mp = msgpack.NewDecoder(r)
+	case CSV:
+		d.csv.init(r)
+	}
+	return d
+}
+
+// Decode reads the next record into the value v points to.
+func (d *Decoder) Decode(v any) error {
+	var doc any
+	switch d.f {
+	case JSON:
+		return d.json.Decode(v)
+	case CSV:
+		return d.csv.decode(v)
+	case YAML:
+		if err := d.yaml.Decode(&doc); err != nil {
+			return err
+		}
+	case MessagePack:
+		if err := d.mp.Decode(&doc); err != nil {
+			return err
+		}
+	case TOML:
+		if !d.read {
+			if err := d.readTOML(); err != nil {
+				return err
+			}
+		}
+		if len(d.toml) == 0 {
+			return io.EOF
+		}
+		doc, d.toml = d.toml[0], d.toml[1:]
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, d.f)
+	}
+	return fromDoc(doc, v)
+}
+
+func (d *Decoder) readTOML() error {
+	d.read = true
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	var table map[string]any
+	if _, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&table); err != nil {
+		return err
+	}
+	switch items := table[tomlKey].(type) {
+	case nil:
+	case []map[string]any:
+		for _, item := range items {
+			d.toml = append(d.toml, item)
+		}
+	case []any:
+		d.toml = items
+	default:
+		return fmt.Errorf("codec: TOML %q must be an array of tables", tomlKey)
+	}
+	return nil
+}
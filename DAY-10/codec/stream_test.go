@@ -0,0 +1,75 @@
+// codec/stream_test.go
+package codec_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"usermanagement/codec"
+	"usermanagement/model"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	for _, f := range codec.Formats {
+		t.Run(string(f), func(t *testing.T) {
+			t.Run("users", func(t *testing.T) { streamRoundTrip(t, f, users) })
+			t.Run("catalog", func(t *testing.T) { streamRoundTrip(t, f, catalogProducts) })
+		})
+	}
+}
+
+func streamRoundTrip[T any](t *testing.T, f codec.Format, want []T) {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, f)
+	for _, v := range want {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	compare(t, want, decodeAll[T](t, &buf, f))
+}
+
+// decodeAll reads records until io.EOF.
+func decodeAll[T any](t *testing.T, r io.Reader, f codec.Format) []T {
+	t.Helper()
+	var got []T
+	dec := codec.NewDecoder(r, f)
+	for {
+		var v T
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return got
+		}
+		if err != nil {
+			t.Fatalf("Decode record %d: %v", len(got)+1, err)
+		}
+		got = append(got, v)
+	}
+}
+
+func TestStreamEmptyInput(t *testing.T) {
+	for _, f := range codec.Formats {
+		var v model.User
+		if err := codec.NewDecoder(bytes.NewReader(nil), f).Decode(&v); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: Decode on empty input = %v, want io.EOF", f, err)
+		}
+	}
+}
+
+// TestStreamLargeInput streams more records than any reasonable read
+// buffer holds, to catch decoders that only see the first chunk.
+func TestStreamLargeInput(t *testing.T) {
+	many := make([]model.Product, 5000)
+	for i := range many {
+		many[i] = model.Product{ID: i + 1, Name: "Widget", Price: float64(i) / 4, Stock: i}
+	}
+	for _, f := range codec.Formats {
+		t.Run(string(f), func(t *testing.T) { streamRoundTrip(t, f, many) })
+	}
+}
@@ -0,0 +1,37 @@
+// model/model.go
+package model // The User, Address and Product types from main.go, shared by every package
+
+import "time"
+
+// Address is a postal address, embedded in User by value. ZipCode is only
+// checked against Country when both are set.
+type Address struct {
+	Street  string `json:"street" validate:"max=200"`
+	City    string `json:"city" validate:"max=100"`
+	ZipCode string `json:"zip_code" validate:"zipcode=Country"`
+	Country string `json:"country" validate:"max=100"`
+}
+
+// User is a registered user. DeletedAt is set when the user is soft-deleted;
+// the record is kept but hidden from normal lookups.
+type User struct {
+	ID        int       `json:"id" validate:"min=0"`
+	Name      string    `json:"name" validate:"required,max=100"`
+	Email     string    `json:"email" validate:"required,email"`
+	IsActive  bool      `json:"is_active"`
+	Address   Address   `json:"address"`
+	DeletedAt time.Time `json:"deleted_at,omitzero"`
+}
+
+// Deleted reports whether the user has been soft-deleted.
+func (u User) Deleted() bool {
+	return !u.DeletedAt.IsZero()
+}
+
+// Product is something we sell.
+type Product struct {
+	ID    int     `json:"id" validate:"min=0"`
+	Name  string  `json:"name" validate:"required,max=200"`
+	Price float64 `json:"price" validate:"min=0"`
+	Stock int     `json:"stock" validate:"min=0"`
+}
@@ -0,0 +1,56 @@
+// model/validation.go
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"usermanagement/address/country"
+	"usermanagement/validate"
+)
+
+// validator knows the generic rules plus the model-specific ones below.
+var validator = validate.New()
+
+func init() {
+	validator.Register("zipcode", zipCode)
+}
+
+// Validate checks u against its validate tags, including its Address, and
+// returns a validate.Errors listing every problem.
+func (u User) Validate() error {
+	return validator.Struct(u)
+}
+
+// Validate checks a on its own.
+func (a Address) Validate() error {
+	return validator.Struct(a)
+}
+
+// Validate checks p against its validate tags.
+func (p Product) Validate() error {
+	return validator.Struct(p)
+}
+
+// zipCode is the "zipcode=<CountryField>" rule: the zip code must fit the
+// postal code format that the address package's country data gives for the
+// country held in the named sibling field. Countries missing from that data
+// are not checked.
+func zipCode(f validate.Field) error {
+	field := f.Parent.FieldByName(f.Param)
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return fmt.Errorf("zipcode: no string field %q next to %s", f.Param, f.Name)
+	}
+	if f.Value.String() == "" || strings.TrimSpace(field.String()) == "" {
+		return nil
+	}
+	c, err := country.Lookup(field.String())
+	if err != nil {
+		return nil
+	}
+	if _, err := c.NormalizePostalCode(f.Value.String()); err != nil {
+		return fmt.Errorf("%q is not a valid zip code for %s (example: %s)", f.Value.String(), field.String(), c.Example)
+	}
+	return nil
+}
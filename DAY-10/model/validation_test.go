@@ -0,0 +1,45 @@
+// model/validation_test.go
+package model_test
+
+import (
+	"testing"
+
+	"usermanagement/model"
+)
+
+func TestAddressZipCode(t *testing.T) {
+	tests := []struct {
+		zip, country string
+		valid        bool
+	}{
+		{"90210", "US", true},
+		{"90210-1234", "USA", true},
+		{"9021", "United States", false},
+		{"K1A 0B1", "CA", true},
+		{"k1a0b1", "Canada", true}, // Spacing and case are normalized first.
+		{"D1A 0B1", "CA", false},   // D is not a valid first letter.
+		{"SW1A 1AA", "UK", true},
+		{"10115", "Deutschland", true},
+		{"100001", "NG", true},
+		{"anything", "Fantasy", true}, // Unknown countries are not checked.
+		{"", "US", true},
+		{"12345", "", true},
+	}
+	for _, tt := range tests {
+		err := model.Address{ZipCode: tt.zip, Country: tt.country}.Validate()
+		if got := err == nil; got != tt.valid {
+			t.Errorf("zip %q in %q: valid = %v, want %v (err %v)", tt.zip, tt.country, got, tt.valid, err)
+		}
+	}
+}
+
+func TestUserValidate(t *testing.T) {
+	u := model.User{Name: "Alice", Email: "alice@example.com", Address: model.Address{ZipCode: "ABC", Country: "US"}}
+	if err := u.Validate(); err == nil {
+		t.Error("user with a bad zip code passed validation")
+	}
+	u.Address.ZipCode = "90210"
+	if err := u.Validate(); err != nil {
+		t.Errorf("valid user: %v", err)
+	}
+}
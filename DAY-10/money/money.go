@@ -0,0 +1,155 @@
+// money/money.go
+package money // Exact amounts of money, counted in cents
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount is an amount of money in cents. Using an integer avoids the
+// rounding surprises of float64: 0.1 + 0.2 is exactly 0.30 here.
+type Amount int64
+
+// Cents returns an Amount of c cents.
+func Cents(c int64) Amount {
+	return Amount(c)
+}
+
+// Parse reads amounts such as "12", "12.3", "12.34" or "-0.50". More than
+// two decimals is an error rather than a silent rounding, and so is anything
+// but one optional leading minus and ASCII digits around the point.
+func Parse(s string) (Amount, error) {
+	input := s
+	s = strings.TrimSpace(s)
+	digits, neg := strings.CutPrefix(s, "-")
+	whole, frac, hasFrac := strings.Cut(digits, ".")
+	if !isDigits(whole) || (hasFrac && (!isDigits(frac) || len(frac) > 2)) {
+		return 0, fmt.Errorf("money: invalid amount %q", input)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > (math.MaxInt64-99)/100 {
+		return 0, fmt.Errorf("money: amount %q out of range", input)
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64) // Two digits always parse.
+	a := Amount(w*100 + f)
+	if neg {
+		a = -a
+	}
+	return a, nil
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MustParse is like Parse but panics on error. Use it for constants.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// String formats the amount with two decimals, e.g. "1200.00".
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+		a = -a
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, a/100, a%100)
+}
+
+// Mul returns a times n, e.g. a unit price times a quantity.
+func (a Amount) Mul(n int) Amount {
+	return a * Amount(n)
+}
+
+// Rate is a percentage in basis points: 1 basis point is 0.01%, so 2000 is
+// 20% and 725 is 7.25%.
+type Rate int64
+
+// Percent returns a Rate of p percent, which may have up to two decimals.
+// Further decimals are rounded half away from zero.
+func Percent(p float64) Rate {
+	return Rate(math.Round(p * 100))
+}
+
+// String formats the rate as a percentage without trailing zeros, e.g.
+// "20%", "7.25%" or "-0.5%".
+func (r Rate) String() string {
+	sign := ""
+	if r < 0 {
+		sign = "-"
+		r = -r
+	}
+	return sign + strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%d.%02d", r/100, r%100), "0"), ".") + "%"
+}
+
+// Apply returns the share r of a, rounded half away from zero to the
+// nearest cent: 7.25% of 9.99 is 0.72.
+func (a Amount) Apply(r Rate) Amount {
+	n := int64(a) * int64(r)
+	q, rem := n/10000, n%10000
+	if rem >= 5000 {
+		q++
+	} else if rem <= -5000 {
+		q--
+	}
+	return Amount(q)
+}
+
+// ErrNegative is returned by Split when asked to split a negative amount.
+var ErrNegative = errors.New("money: negative amount")
+
+// Split divides a into n parts that differ by at most one cent and add up
+// to exactly a. The first parts get the extra cents.
+func (a Amount) Split(n int) ([]Amount, error) {
+	if a < 0 {
+		return nil, ErrNegative
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("money: cannot split into %d parts", n)
+	}
+	parts := make([]Amount, n)
+	base, extra := a/Amount(n), int(a%Amount(n))
+	for i := range parts {
+		parts[i] = base
+		if i < extra {
+			parts[i]++
+		}
+	}
+	return parts, nil
+}
+
+// MarshalText encodes the amount as "12.34", so JSON holds a string that no
+// client will parse into a float by accident.
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText is the reverse of MarshalText.
+func (a *Amount) UnmarshalText(b []byte) error {
+	v, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
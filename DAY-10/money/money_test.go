@@ -0,0 +1,115 @@
+// money/money_test.go
+package money_test
+
+import (
+	"testing"
+
+	"usermanagement/money"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want money.Amount
+	}{
+		{"12", 1200},
+		{"12.3", 1230},
+		{"12.34", 1234},
+		{"-0.50", -50},
+		{" 7.05 ", 705},
+		{"0", 0},
+		{"92233720368547757.99", 9223372036854775799},
+	}
+	for _, tt := range tests {
+		got, err := money.Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, in := range []string{
+		"", "-", ".", "12.", ".5", "1.234",
+		"--5", "+5", "-+5", "1.+5", "1.-5", "1.5x", "1,50", "١٢", "1 000",
+		"92233720368547758.00", "99999999999999999999",
+	} {
+		if got, err := money.Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseErrorQuotesInput(t *testing.T) {
+	_, err := money.Parse("-1.234")
+	if want := `money: invalid amount "-1.234"`; err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	for a, want := range map[money.Amount]string{0: "0.00", 5: "0.05", 1200: "12.00", -50: "-0.50", -1234: "-12.34"} {
+		if got := a.String(); got != want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(a), got, want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want money.Rate
+		str  string
+	}{
+		{20, 2000, "20%"},
+		{7.25, 725, "7.25%"},
+		{0.5, 50, "0.5%"},
+		{-5, -500, "-5%"},
+		{-7.25, -725, "-7.25%"},
+		{-0.5, -50, "-0.5%"},
+		{19.999, 2000, "20%"},
+	}
+	for _, tt := range tests {
+		got := money.Percent(tt.in)
+		if got != tt.want || got.String() != tt.str {
+			t.Errorf("Percent(%v) = %d (%s), want %d (%s)", tt.in, int64(got), got, int64(tt.want), tt.str)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		a    money.Amount
+		r    money.Rate
+		want money.Amount
+	}{
+		{999, 725, 72},
+		{1000, 2000, 200},
+		{-999, 725, -72},
+		{50, 1000, 5},
+		{5, 1000, 1}, // 0.5 cents rounds away from zero.
+		{-5, 1000, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Apply(tt.r); got != tt.want {
+			t.Errorf("%v.Apply(%v) = %v, want %v", tt.a, tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	parts, err := money.MustParse("10.00").Split(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum money.Amount
+	for _, p := range parts {
+		sum += p
+	}
+	if parts[0] != 334 || parts[2] != 333 || sum != 1000 {
+		t.Errorf("Split(3) = %v", parts)
+	}
+	if _, err := money.Amount(-1).Split(2); err != money.ErrNegative {
+		t.Errorf("negative Split error = %v, want ErrNegative", err)
+	}
+}
@@ -0,0 +1,151 @@
+// product/product.go
+package product // Create, read, update and delete products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"usermanagement/model"
+)
+
+var (
+	ErrNotFound = errors.New("product: not found")
+	ErrInvalid  = errors.New("product: invalid product")
+)
+
+// Repository stores products. Create assigns a new, unique ID and returns
+// the stored product; the other methods return ErrNotFound for unknown IDs.
+type Repository interface {
+	Create(ctx context.Context, p model.Product) (model.Product, error)
+	Get(ctx context.Context, id int) (model.Product, error)
+	Update(ctx context.Context, p model.Product) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context) ([]model.Product, error)
+}
+
+// Service implements the product rules on top of a Repository.
+type Service struct {
+	repo Repository
+}
+
+// NewService returns a Service storing its products in repo.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create validates p and stores it with a new ID. Any ID set on p is ignored.
+func (s *Service) Create(ctx context.Context, p model.Product) (model.Product, error) {
+	p.ID = 0
+	p.Name = strings.TrimSpace(p.Name)
+	if err := check(p); err != nil {
+		return model.Product{}, err
+	}
+	return s.repo.Create(ctx, p)
+}
+
+// Get returns the product with the given ID.
+func (s *Service) Get(ctx context.Context, id int) (model.Product, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Replace overwrites every field of an existing product with p.
+func (s *Service) Replace(ctx context.Context, p model.Product) (model.Product, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	if err := check(p); err != nil {
+		return model.Product{}, err
+	}
+	if err := s.repo.Update(ctx, p); err != nil {
+		return model.Product{}, err
+	}
+	return p, nil
+}
+
+// Delete removes a product.
+func (s *Service) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// List returns every product sorted by ID.
+func (s *Service) List(ctx context.Context) ([]model.Product, error) {
+	return s.repo.List(ctx)
+}
+
+func check(p model.Product) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return nil
+}
+
+// MemoryRepository is a Repository that keeps products in a map. It is safe
+// for concurrent use.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	products map[int]model.Product
+	lastID   int
+}
+
+// NewMemoryRepository returns an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{products: make(map[int]model.Product)}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, p model.Product) (model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	p.ID = r.lastID
+	r.products[p.ID] = p
+	return p, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id int) (model.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.products[id]
+	if !ok {
+		return model.Product{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, p model.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[p.ID]; !ok {
+		return ErrNotFound
+	}
+	r.products[p.ID] = p
+	return nil
+}
+
+func (r *MemoryRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.products, id)
+	return nil
+}
+
+// List returns every stored product sorted by ID.
+func (r *MemoryRepository) List(ctx context.Context) ([]model.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]model.Product, 0, len(r.products))
+	for _, p := range r.products {
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(a, b model.Product) int { return a.ID - b.ID })
+	return out, nil
+}
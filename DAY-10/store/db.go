@@ -0,0 +1,363 @@
+// store/db.go
+package store // A pure-Go embedded key-value store: an append-only log plus an in-memory index
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrKeyNotFound = errors.New("store: key not found")
+	ErrClosed      = errors.New("store: database is closed")
+)
+
+// Options tune a DB. The zero value is a sensible default.
+type Options struct {
+	// NoSync skips the fsync after each write. Writes get much faster, but
+	// the last ones may be lost if the machine (not just the program)
+	// crashes.
+	NoSync bool
+
+	// CompactInterval is how often the log is checked for garbage. Zero
+	// disables background compaction; Compact can still be called by hand.
+	CompactInterval time.Duration
+
+	// CompactRatio is the share of the log, between 0 and 1, that must be
+	// garbage (overwritten or deleted records) before a background
+	// compaction runs. It defaults to 0.5.
+	CompactRatio float64
+}
+
+// entry locates the latest record of a key in the log.
+type entry struct {
+	off  int64
+	size int64
+}
+
+// DB is a key-value store kept in a single append-only file. Every Put and
+// Delete appends a record; an in-memory index maps each key to its latest
+// record, so reads are one disk access. Overwritten and deleted records
+// stay in the file as garbage until Compact rewrites it.
+//
+// A DB is safe for concurrent use by multiple goroutines, but only one
+// process may open a file at a time.
+type DB struct {
+	path string
+	opts Options
+
+	mu      sync.RWMutex
+	f       *os.File
+	index   map[string]entry
+	size    int64 // Bytes of valid log.
+	garbage int64 // Bytes of records no longer referenced by the index.
+
+	// Truncated is how many bytes of torn or corrupt data Open cut off the
+	// end of the log while recovering from a crash.
+	Truncated int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Open opens or creates the database file at path and rebuilds the index by
+// scanning the log. If the log ends in a torn or corrupt record, as happens
+// when the program dies halfway through a write, the file is truncated to
+// the last good record. A damaged record with a valid record somewhere
+// after it is not a torn write, so Open leaves the file alone and returns an
+// error wrapping ErrCorrupt rather than throw the later records away.
+func Open(path string, opts Options) (*DB, error) {
+	if opts.CompactRatio <= 0 || opts.CompactRatio > 1 {
+		opts.CompactRatio = 0.5
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{path: path, opts: opts, f: f}
+	if err := db.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if opts.CompactInterval > 0 {
+		db.stop = make(chan struct{})
+		db.done = make(chan struct{})
+		go db.compactLoop()
+	}
+	return db, nil
+}
+
+// load scans the log, builds the index and cuts off a torn tail.
+func (db *DB) load() error {
+	db.index = make(map[string]entry)
+	db.size, db.garbage = 0, 0
+
+	var off int64
+	for {
+		rec, n, err := readRecord(db.f, off)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, ErrCorrupt) {
+			info, statErr := db.f.Stat()
+			if statErr != nil {
+				return statErr
+			}
+			torn, tornErr := db.tornTail(off, info.Size())
+			if tornErr != nil {
+				return tornErr
+			}
+			if !torn {
+				return fmt.Errorf("store: damaged record at offset %d of %d in %s: %w", off, info.Size(), db.path, err)
+			}
+			db.Truncated = info.Size() - off
+			if err := db.f.Truncate(off); err != nil {
+				return fmt.Errorf("store: truncating torn write: %w", err)
+			}
+			if err := db.f.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		db.apply(rec, entry{off: off, size: n})
+		off += n
+	}
+	db.size = off
+	return nil
+}
+
+// tornTail reports whether the damaged record at off is an interrupted
+// final write rather than damage in the middle of the log. It is if no
+// valid record starts anywhere after off: either the bytes from off on are
+// the partial record the program was writing when it died, or they are all
+// zeros, as some file systems leave behind when the size reached the disk
+// before the data did. A damaged length field can claim that the record
+// runs to the end of the file, so the length is not trusted; and since a
+// torn write is at most one record long, more data than that is never torn.
+func (db *DB) tornTail(off, size int64) (bool, error) {
+	if size-off > headerSize+maxRecordSize {
+		return false, nil
+	}
+	tail := make([]byte, size-off)
+	if _, err := db.f.ReadAt(tail, off); err != nil && err != io.EOF {
+		return false, err
+	}
+	for i := 1; i < len(tail); i++ {
+		if validRecord(tail[i:]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// apply updates the index and garbage count for a record at e.
+func (db *DB) apply(rec record, e entry) {
+	if old, ok := db.index[rec.key]; ok {
+		db.garbage += old.size
+	}
+	switch rec.kind {
+	case kindPut:
+		db.index[rec.key] = e
+	case kindDelete:
+		delete(db.index, rec.key)
+		db.garbage += e.size // A tombstone is garbage as soon as it's written.
+	}
+}
+
+// Get returns the value stored under key, or ErrKeyNotFound.
+func (db *DB) Get(key string) ([]byte, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.f == nil {
+		return nil, ErrClosed
+	}
+	e, ok := db.index[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	rec, _, err := readRecord(db.f, e.off)
+	if err != nil {
+		return nil, fmt.Errorf("store: reading %q: %w", key, err)
+	}
+	return rec.value, nil
+}
+
+// Put stores value under key, replacing any previous value.
+func (db *DB) Put(key string, value []byte) error {
+	if key == "" {
+		return errors.New("store: empty key")
+	}
+	return db.write(record{kind: kindPut, key: key, value: value})
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (db *DB) Delete(key string) error {
+	db.mu.RLock()
+	_, ok := db.index[key]
+	db.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return db.write(record{kind: kindDelete, key: key})
+}
+
+func (db *DB) write(rec record) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.f == nil {
+		return ErrClosed
+	}
+	buf := rec.encode()
+	if _, err := db.f.WriteAt(buf, db.size); err != nil {
+		// Drop whatever part of the record made it to disk so the next
+		// write starts on a record boundary.
+		db.f.Truncate(db.size)
+		return err
+	}
+	if !db.opts.NoSync {
+		if err := db.f.Sync(); err != nil {
+			return err
+		}
+	}
+	db.apply(rec, entry{off: db.size, size: int64(len(buf))})
+	db.size += int64(len(buf))
+	return nil
+}
+
+// Keys returns the stored keys that start with prefix, sorted.
+func (db *DB) Keys(prefix string) []string {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var keys []string
+	for k := range db.index {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Stats reports the size of the log and how much of it is garbage.
+func (db *DB) Stats() (size, garbage int64) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.size, db.garbage
+}
+
+// Compact rewrites the log with only the live records. The new log is
+// written to a temporary file, synced and renamed over the old one, so a
+// crash during compaction leaves either the old or the new log, never a mix.
+func (db *DB) Compact() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.f == nil {
+		return ErrClosed
+	}
+	tmpPath := db.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath) // No-op once the rename succeeded.
+
+	keys := make([]string, 0, len(db.index))
+	for k := range db.index {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	index := make(map[string]entry, len(keys))
+	var off int64
+	for _, k := range keys {
+		rec, _, err := readRecord(db.f, db.index[k].off)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("store: compacting %q: %w", k, err)
+		}
+		buf := rec.encode()
+		if _, err := tmp.WriteAt(buf, off); err != nil {
+			tmp.Close()
+			return err
+		}
+		index[k] = entry{off: off, size: int64(len(buf))}
+		off += int64(len(buf))
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := os.Rename(tmpPath, db.path); err != nil {
+		tmp.Close()
+		return err
+	}
+	syncDir(filepath.Dir(db.path))
+
+	db.f.Close()
+	db.f = tmp
+	db.index = index
+	db.size = off
+	db.garbage = 0
+	return nil
+}
+
+func (db *DB) compactLoop() {
+	defer close(db.done)
+	ticker := time.NewTicker(db.opts.CompactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-ticker.C:
+			size, garbage := db.Stats()
+			if size > 0 && float64(garbage)/float64(size) >= db.opts.CompactRatio {
+				db.Compact() // A failed compaction leaves the old log in place.
+			}
+		}
+	}
+}
+
+// Close stops background compaction and closes the file. Closing an
+// already closed DB returns ErrClosed.
+func (db *DB) Close() error {
+	db.stopOnce.Do(func() {
+		if db.stop != nil {
+			close(db.stop)
+			<-db.done
+		}
+	})
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.f == nil {
+		return ErrClosed
+	}
+	err := db.f.Close()
+	db.f = nil
+	return err
+}
+
+// syncDir makes a rename durable on file systems that need it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,292 @@
+// store/db_test.go
+package store_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"usermanagement/store"
+)
+
+// newLog writes count records to a fresh database file and returns its path
+// and the file size after each record.
+func newLog(t *testing.T, count int) (string, []int64) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := store.Open(path, store.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ends []int64
+	for i := 0; i < count; i++ {
+		if err := db.Put(fmt.Sprint("key", i), []byte(fmt.Sprint("value", i))); err != nil {
+			t.Fatal(err)
+		}
+		size, _ := db.Stats()
+		ends = append(ends, size)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path, ends
+}
+
+// damage applies edit to the raw bytes of the file at path.
+func damage(t *testing.T, path string, edit func([]byte) []byte) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, edit(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenTruncatesTornTail(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(data []byte, ends []int64) []byte
+	}{
+		{"half a header", func(data []byte, ends []int64) []byte { return data[:ends[1]+4] }},
+		{"half a body", func(data []byte, ends []int64) []byte { return data[:ends[2]-3] }},
+		{"garbage final record", func(data []byte, ends []int64) []byte {
+			data[ends[2]-1] ^= 0xff
+			return data
+		}},
+		{"zero-filled tail", func(data []byte, ends []int64) []byte {
+			clear(data[ends[1]:])
+			return data
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ends := newLog(t, 3)
+			damage(t, path, func(data []byte) []byte { return tt.edit(data, ends) })
+			info, _ := os.Stat(path)
+
+			db, err := store.Open(path, store.Options{})
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer db.Close()
+
+			if want := info.Size() - ends[1]; db.Truncated != want {
+				t.Errorf("Truncated = %d, want %d", db.Truncated, want)
+			}
+			for i, want := range []bool{true, true, false} {
+				_, err := db.Get(fmt.Sprint("key", i))
+				if got := err == nil; got != want {
+					t.Errorf("key%d present = %v, want %v (err %v)", i, got, want, err)
+				}
+			}
+			// The log must accept new writes right after the cut.
+			if err := db.Put("key2", []byte("again")); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestOpenRefusesMidLogCorruption(t *testing.T) {
+	// Each edit damages the second of three records; the third is intact.
+	setLength := func(data []byte, off int64, length uint32) {
+		binary.LittleEndian.PutUint32(data[off+4:], length)
+	}
+	tests := []struct {
+		name string
+		edit func(data []byte, ends []int64) []byte
+	}{
+		{"flipped body bit", func(data []byte, ends []int64) []byte {
+			data[ends[0]+10] ^= 0x01
+			return data
+		}},
+		{"length runs past the end", func(data []byte, ends []int64) []byte {
+			setLength(data, ends[0], uint32(int64(len(data))-ends[0]))
+			return data
+		}},
+		{"length runs exactly to the end", func(data []byte, ends []int64) []byte {
+			setLength(data, ends[0], uint32(int64(len(data))-ends[0]-8))
+			return data
+		}},
+		{"length too short", func(data []byte, ends []int64) []byte {
+			setLength(data, ends[0], 3)
+			return data
+		}},
+		{"length zero", func(data []byte, ends []int64) []byte {
+			setLength(data, ends[0], 0)
+			return data
+		}},
+		{"length too large", func(data []byte, ends []int64) []byte {
+			setLength(data, ends[0], 1<<31)
+			return data
+		}},
+		{"zeroed header", func(data []byte, ends []int64) []byte {
+			clear(data[ends[0] : ends[0]+8])
+			return data
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ends := newLog(t, 3)
+			damage(t, path, func(data []byte) []byte { return tt.edit(data, ends) })
+			before, _ := os.ReadFile(path)
+
+			_, err := store.Open(path, store.Options{})
+			if !errors.Is(err, store.ErrCorrupt) {
+				t.Fatalf("Open error = %v, want ErrCorrupt", err)
+			}
+			after, _ := os.ReadFile(path)
+			if !bytes.Equal(after, before) {
+				t.Errorf("file changed from %d to %d bytes; later records were lost", len(before), len(after))
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := store.Open(path, store.Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for round := range 3 {
+		for i := range 10 {
+			if err := db.Put(fmt.Sprint("key", i), []byte(fmt.Sprint("value", i, "-", round))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for i := range 5 {
+		if err := db.Delete(fmt.Sprint("key", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	size, garbage := db.Stats()
+	if garbage == 0 || garbage >= size {
+		t.Fatalf("Stats = %d, %d before compaction; want some garbage", size, garbage)
+	}
+
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	newSize, newGarbage := db.Stats()
+	if newGarbage != 0 || newSize != size-garbage {
+		t.Errorf("Stats after Compact = %d, %d, want %d, 0", newSize, newGarbage, size-garbage)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != newSize {
+		t.Errorf("file size after Compact = %v, %v, want %d", info, err, newSize)
+	}
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+	check := func(db *store.DB) {
+		t.Helper()
+		for i := range 10 {
+			got, err := db.Get(fmt.Sprint("key", i))
+			if i < 5 {
+				if !errors.Is(err, store.ErrKeyNotFound) {
+					t.Errorf("key%d = %q, %v, want ErrKeyNotFound", i, got, err)
+				}
+				continue
+			}
+			if want := fmt.Sprint("value", i, "-2"); err != nil || string(got) != want {
+				t.Errorf("key%d = %q, %v, want %q", i, got, err, want)
+			}
+		}
+	}
+	check(db)
+
+	// Writes after compaction go to the new file, and everything survives
+	// a reopen.
+	if err := db.Put("key9", []byte("value9-2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Compact(); !errors.Is(err, store.ErrClosed) {
+		t.Errorf("Compact after Close = %v, want ErrClosed", err)
+	}
+	db, err = store.Open(path, store.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	check(db)
+	if db.Truncated != 0 {
+		t.Errorf("Truncated = %d after a clean compaction", db.Truncated)
+	}
+}
+
+func TestBackgroundCompaction(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "test.db"), store.Options{
+		NoSync:          true,
+		CompactInterval: time.Millisecond,
+		CompactRatio:    0.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// One overwrite makes half the log garbage; the next tick compacts it.
+	for _, v := range []string{"old", "new"} {
+		if err := db.Put("key", []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, garbage := db.Stats(); garbage == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background compaction did not run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, err := db.Get("key"); err != nil || string(got) != "new" {
+		t.Errorf("Get after compaction = %q, %v", got, err)
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "test.db"), store.Options{CompactInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = db.Close()
+		}()
+	}
+	wg.Wait()
+
+	closed := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			closed++
+		case !errors.Is(err, store.ErrClosed):
+			t.Errorf("Close: %v", err)
+		}
+	}
+	if closed != 1 {
+		t.Errorf("%d Close calls succeeded, want exactly 1", closed)
+	}
+}
@@ -0,0 +1,127 @@
+// store/record.go
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// On disk the log is a sequence of records:
+//
+//	+--------+--------+------+------------+-----+-------+
+//	| crc32  | length | kind | key length | key | value |
+//	| 4 B    | 4 B    | 1 B  | uvarint    |     |       |
+//	+--------+--------+------+------------+-----+-------+
+//	                  \________________ body ___________/
+//
+// length is the size of the body and crc32 (Castagnoli) covers the body. A
+// record whose header or body is cut short, or whose checksum doesn't
+// match, marks the end of the usable log if it is the last record in the
+// file; anywhere else it means the file is damaged.
+
+const headerSize = 8
+
+// maxRecordSize guards against a corrupted length field making us allocate
+// gigabytes while reading.
+const maxRecordSize = 64 << 20
+
+type recordKind byte
+
+const (
+	kindPut    recordKind = 1
+	kindDelete recordKind = 2
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrCorrupt is returned by readRecord for a torn or damaged record, and by
+// Open when such a record is followed by a valid one, so cutting it off
+// would lose good records.
+var ErrCorrupt = errors.New("store: corrupt record")
+
+type record struct {
+	kind  recordKind
+	key   string
+	value []byte
+}
+
+// encode returns the full on-disk form of r, header included.
+func (r record) encode() []byte {
+	body := make([]byte, 0, 1+binary.MaxVarintLen64+len(r.key)+len(r.value))
+	body = append(body, byte(r.kind))
+	body = binary.AppendUvarint(body, uint64(len(r.key)))
+	body = append(body, r.key...)
+	body = append(body, r.value...)
+
+	buf := make([]byte, headerSize, headerSize+len(body))
+	binary.LittleEndian.PutUint32(buf[0:4], crc32.Checksum(body, crcTable))
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(len(body)))
+	return append(buf, body...)
+}
+
+// readRecord reads the record starting at off. It returns the record and
+// its total size on disk, io.EOF at a clean end of the log, or ErrCorrupt.
+func readRecord(r io.ReaderAt, off int64) (record, int64, error) {
+	var header [headerSize]byte
+	n, err := r.ReadAt(header[:], off)
+	if n == 0 && err == io.EOF {
+		return record{}, 0, io.EOF
+	}
+	if n < headerSize {
+		return record{}, 0, ErrCorrupt
+	}
+	sum := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if length == 0 || length > maxRecordSize {
+		return record{}, 0, ErrCorrupt
+	}
+
+	body := make([]byte, length)
+	if n, _ := r.ReadAt(body, off+headerSize); n < len(body) {
+		return record{}, 0, ErrCorrupt
+	}
+	if crc32.Checksum(body, crcTable) != sum {
+		return record{}, 0, ErrCorrupt
+	}
+
+	rec, ok := decodeBody(body)
+	if !ok {
+		return record{}, 0, ErrCorrupt
+	}
+	return rec, headerSize + int64(length), nil
+}
+
+// validRecord reports whether buf starts with a complete record whose
+// checksum matches.
+func validRecord(buf []byte) bool {
+	if len(buf) < headerSize {
+		return false
+	}
+	length := binary.LittleEndian.Uint32(buf[4:8])
+	if length == 0 || length > maxRecordSize || uint64(len(buf)-headerSize) < uint64(length) {
+		return false
+	}
+	body := buf[headerSize : headerSize+length]
+	// decodeBody is much cheaper than the checksum and rejects most
+	// offsets that merely look like a header.
+	if _, ok := decodeBody(body); !ok {
+		return false
+	}
+	return crc32.Checksum(body, crcTable) == binary.LittleEndian.Uint32(buf[0:4])
+}
+
+func decodeBody(body []byte) (record, bool) {
+	kind := recordKind(body[0])
+	if kind != kindPut && kind != kindDelete {
+		return record{}, false
+	}
+	keyLen, n := binary.Uvarint(body[1:])
+	if n <= 0 || uint64(len(body)-1-n) < keyLen {
+		return record{}, false
+	}
+	start := 1 + n
+	end := start + int(keyLen)
+	return record{kind: kind, key: string(body[start:end]), value: body[end:]}, true
+}
@@ -0,0 +1,249 @@
+// store/repo.go
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"usermanagement/model"
+	"usermanagement/product"
+	"usermanagement/user"
+)
+
+const (
+	userPrefix    = "user/"
+	productPrefix = "product/"
+	seqPrefix     = "seq/" // Highest ID handed out, so deleted IDs are never reused.
+)
+
+var (
+	_ user.Repository    = (*UserRepository)(nil)
+	_ product.Repository = (*ProductRepository)(nil)
+)
+
+// UserRepository is a user.Repository kept in a DB, so users survive a
+// restart. Users are stored as JSON under "user/<id>".
+type UserRepository struct {
+	db *DB
+
+	mu      sync.Mutex // Serializes writes so ID and email checks can't race.
+	lastID  int
+	byEmail map[string]int // user.EmailKey -> ID, only for users not deleted.
+}
+
+// NewUserRepository loads the users in db and returns a repository for them.
+func NewUserRepository(db *DB) (*UserRepository, error) {
+	r := &UserRepository{db: db, byEmail: make(map[string]int)}
+	users, err := loadAll[model.User](db, userPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if r.lastID, err = loadSeq(db, userPrefix); err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		r.lastID = max(r.lastID, u.ID)
+		if !u.Deleted() {
+			r.byEmail[user.EmailKey(u.Email)] = u.ID
+		}
+	}
+	return r, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, u model.User) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := user.EmailKey(u.Email)
+	if _, taken := r.byEmail[key]; taken && !u.Deleted() {
+		return model.User{}, user.ErrEmailTaken
+	}
+	u.ID = r.lastID + 1
+	if err := r.db.Put(seqPrefix+userPrefix, []byte(strconv.Itoa(u.ID))); err != nil {
+		return model.User{}, err
+	}
+	if err := putJSON(r.db, userPrefix, u.ID, u); err != nil {
+		return model.User{}, err
+	}
+	r.lastID = u.ID
+	if !u.Deleted() {
+		r.byEmail[key] = u.ID
+	}
+	return u, nil
+}
+
+func (r *UserRepository) Get(ctx context.Context, id int) (model.User, error) {
+	return getJSON[model.User](r.db, userPrefix, id, user.ErrNotFound)
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (model.User, error) {
+	r.mu.Lock()
+	id, ok := r.byEmail[user.EmailKey(email)]
+	r.mu.Unlock()
+	if !ok {
+		return model.User{}, user.ErrNotFound
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *UserRepository) Update(ctx context.Context, u model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, err := r.Get(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	key := user.EmailKey(u.Email)
+	if id, taken := r.byEmail[key]; taken && id != u.ID && !u.Deleted() {
+		return user.ErrEmailTaken
+	}
+	if err := putJSON(r.db, userPrefix, u.ID, u); err != nil {
+		return err
+	}
+	if !old.Deleted() {
+		delete(r.byEmail, user.EmailKey(old.Email))
+	}
+	if !u.Deleted() {
+		r.byEmail[key] = u.ID
+	}
+	return nil
+}
+
+// List returns every stored user, deleted ones included, sorted by ID.
+func (r *UserRepository) List(ctx context.Context) ([]model.User, error) {
+	users, err := loadAll[model.User](r.db, userPrefix)
+	slices.SortFunc(users, func(a, b model.User) int { return a.ID - b.ID })
+	return users, err
+}
+
+// ProductRepository is a product.Repository kept in a DB. Products are
+// stored as JSON under "product/<id>".
+type ProductRepository struct {
+	db *DB
+
+	mu     sync.Mutex
+	lastID int
+}
+
+// NewProductRepository loads the products in db and returns a repository
+// for them.
+func NewProductRepository(db *DB) (*ProductRepository, error) {
+	products, err := loadAll[model.Product](db, productPrefix)
+	if err != nil {
+		return nil, err
+	}
+	r := &ProductRepository{db: db}
+	if r.lastID, err = loadSeq(db, productPrefix); err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		r.lastID = max(r.lastID, p.ID)
+	}
+	return r, nil
+}
+
+func (r *ProductRepository) Create(ctx context.Context, p model.Product) (model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.ID = r.lastID + 1
+	if err := r.db.Put(seqPrefix+productPrefix, []byte(strconv.Itoa(p.ID))); err != nil {
+		return model.Product{}, err
+	}
+	if err := putJSON(r.db, productPrefix, p.ID, p); err != nil {
+		return model.Product{}, err
+	}
+	r.lastID = p.ID
+	return p, nil
+}
+
+func (r *ProductRepository) Get(ctx context.Context, id int) (model.Product, error) {
+	return getJSON[model.Product](r.db, productPrefix, id, product.ErrNotFound)
+}
+
+func (r *ProductRepository) Update(ctx context.Context, p model.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.Get(ctx, p.ID); err != nil {
+		return err
+	}
+	return putJSON(r.db, productPrefix, p.ID, p)
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.Get(ctx, id); err != nil {
+		return err
+	}
+	return r.db.Delete(productPrefix + strconv.Itoa(id))
+}
+
+// List returns every stored product sorted by ID.
+func (r *ProductRepository) List(ctx context.Context) ([]model.Product, error) {
+	products, err := loadAll[model.Product](r.db, productPrefix)
+	slices.SortFunc(products, func(a, b model.Product) int { return a.ID - b.ID })
+	return products, err
+}
+
+func putJSON(db *DB, prefix string, id int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return db.Put(prefix+strconv.Itoa(id), data)
+}
+
+func getJSON[T any](db *DB, prefix string, id int, notFound error) (T, error) {
+	var v T
+	data, err := db.Get(prefix + strconv.Itoa(id))
+	if errors.Is(err, ErrKeyNotFound) {
+		return v, notFound
+	}
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("store: decoding %s%d: %w", prefix, id, err)
+	}
+	return v, nil
+}
+
+func loadAll[T any](db *DB, prefix string) ([]T, error) {
+	var out []T
+	for _, key := range db.Keys(prefix) {
+		id, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			continue // Not one of ours.
+		}
+		v, err := getJSON[T](db, prefix, id, ErrKeyNotFound)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue // Deleted since Keys was called.
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func loadSeq(db *DB, prefix string) (int, error) {
+	data, err := db.Get(seqPrefix + prefix)
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(data))
+}
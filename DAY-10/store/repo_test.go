@@ -0,0 +1,142 @@
+// store/repo_test.go
+package store_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"usermanagement/model"
+	"usermanagement/product"
+	"usermanagement/store"
+	"usermanagement/user"
+)
+
+// reopen closes db, if any, and opens the file at path again with fresh
+// repositories, as a restarted server would.
+func reopen(t *testing.T, db *store.DB, path string) (*store.DB, *store.UserRepository, *store.ProductRepository) {
+	t.Helper()
+	if db != nil {
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db, err := store.Open(path, store.Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	users, err := store.NewUserRepository(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	products, err := store.NewProductRepository(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, users, products
+}
+
+func TestUserRepositoryReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "users.db")
+	db, repo, _ := reopen(t, nil, path)
+
+	alice, err := repo.Create(ctx, model.User{Name: "Alice", Email: "alice@example.com", IsActive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := repo.Create(ctx, model.User{Name: "Bob", Email: "bob@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob.DeletedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.Update(ctx, bob); err != nil {
+		t.Fatal(err)
+	}
+
+	_, repo, _ = reopen(t, db, path)
+
+	users, err := repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0] != alice || !users[1].DeletedAt.Equal(bob.DeletedAt) {
+		t.Fatalf("List after reopen = %+v", users)
+	}
+	// The email index is rebuilt: alice's address is taken, the deleted
+	// bob's is free.
+	if got, err := repo.GetByEmail(ctx, "ALICE@example.com"); err != nil || got != alice {
+		t.Errorf("GetByEmail(alice) = %+v, %v", got, err)
+	}
+	if _, err := repo.GetByEmail(ctx, "bob@example.com"); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("GetByEmail(deleted bob) = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.Create(ctx, model.User{Name: "Alias", Email: "alice@example.com"}); !errors.Is(err, user.ErrEmailTaken) {
+		t.Errorf("Create with alice's email = %v, want ErrEmailTaken", err)
+	}
+	bob2, err := repo.Create(ctx, model.User{Name: "Bob", Email: "bob@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bob2.ID != 3 {
+		t.Errorf("new user got ID %d, want 3", bob2.ID)
+	}
+
+	if err := repo.Update(ctx, model.User{ID: 9, Name: "Nobody"}); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("Update(9) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProductRepositoryReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "products.db")
+	db, _, repo := reopen(t, nil, path)
+
+	laptop, err := repo.Create(ctx, model.Product{Name: "Laptop", Price: 1200, Stock: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	desktop, err := repo.Create(ctx, model.Product{Name: "Desktop", Price: 900, Stock: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	laptop.Stock = 4
+	if err := repo.Update(ctx, laptop); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Delete(ctx, desktop.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Compaction drops the old records but must keep the ID sequence.
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	_, _, repo = reopen(t, db, path)
+
+	products, err := repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != 1 || products[0] != laptop {
+		t.Fatalf("List after reopen = %+v, want [%+v]", products, laptop)
+	}
+	if _, err := repo.Get(ctx, desktop.ID); !errors.Is(err, product.ErrNotFound) {
+		t.Errorf("Get(deleted) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, desktop.ID); !errors.Is(err, product.ErrNotFound) {
+		t.Errorf("Delete(deleted) = %v, want ErrNotFound", err)
+	}
+
+	// The deleted product's ID is never handed out again.
+	monitor, err := repo.Create(ctx, model.Product{Name: "Monitor", Price: 300, Stock: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.ID != 3 {
+		t.Errorf("new product got ID %d, want 3", monitor.ID)
+	}
+}
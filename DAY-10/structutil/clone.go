@@ -0,0 +1,112 @@
+// structutil/clone.go
+package structutil // Deep copies, field-level diffs and JSON patches of Go values
+
+import "reflect"
+
+// Clone returns a deep copy of v: pointers, maps, slices and interfaces are
+// followed and copied, so changing the copy never changes v. Values that are
+// shared within v stay shared in the copy, which also makes cycles safe: a
+// node pointing to itself is cloned into a node pointing to itself.
+//
+// Channels and functions are not copied; the clone refers to the same
+// ones. Unexported struct fields are copied as they are, without following
+// them, because reflection can't set them.
+func Clone[T any](v T) T {
+	c := cloner{seen: make(map[seenKey]reflect.Value)}
+	src := reflect.ValueOf(&v).Elem()
+	// When T is an interface type holding nil, Interface returns a nil
+	// interface that a plain type assertion would reject.
+	clone, _ := c.value(src).Interface().(T)
+	return clone
+}
+
+// seenKey identifies memory already copied. The type is part of the key
+// because a struct and its first field share an address.
+type seenKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+type cloner struct {
+	seen map[seenKey]reflect.Value
+}
+
+func (c *cloner) value(src reflect.Value) reflect.Value {
+	dst := reflect.New(src.Type()).Elem()
+	c.copy(dst, src)
+	return dst
+}
+
+// copy deep-copies src into dst, which must be settable and of the same type.
+func (c *cloner) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := seenKey{ptr: src.Pointer(), typ: src.Type()}
+		if p, ok := c.seen[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.seen[key] = p
+		dst.Set(p)
+		c.copy(p.Elem(), src.Elem())
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := seenKey{ptr: src.Pointer(), typ: src.Type()}
+		if m, ok := c.seen[key]; ok {
+			dst.Set(m)
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.seen[key] = m
+		dst.Set(m)
+		for it := src.MapRange(); it.Next(); {
+			m.SetMapIndex(c.value(it.Key()), c.value(it.Value()))
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		key := seenKey{ptr: src.Pointer(), typ: src.Type(), len: src.Len()}
+		if s, ok := c.seen[key]; ok {
+			dst.Set(s)
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.seen[key] = s
+		dst.Set(s)
+		for i := range src.Len() {
+			c.copy(s.Index(i), src.Index(i))
+		}
+
+	case reflect.Array:
+		for i := range src.Len() {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Struct:
+		dst.Set(src) // Unexported fields keep this shallow copy.
+		for i := range src.NumField() {
+			if dst.Field(i).CanSet() {
+				c.copy(dst.Field(i), src.Field(i))
+			}
+		}
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(c.value(src.Elem()))
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,55 @@
+// structutil/clone_test.go
+package structutil_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"usermanagement/structutil"
+)
+
+type node struct {
+	Name string
+	Next *node
+	Tags []string
+	Meta map[string]any
+}
+
+func TestCloneIsDeep(t *testing.T) {
+	orig := &node{Name: "a", Tags: []string{"x"}, Meta: map[string]any{"list": []int{1, 2}}}
+	orig.Next = orig // A cycle.
+
+	clone := structutil.Clone(orig)
+	if clone == orig || clone.Next != clone {
+		t.Fatalf("cycle not preserved: clone=%p clone.Next=%p", clone, clone.Next)
+	}
+	clone.Tags[0] = "y"
+	clone.Meta["list"].([]int)[0] = 9
+	if orig.Tags[0] != "x" || orig.Meta["list"].([]int)[0] != 1 {
+		t.Errorf("changing the clone changed the original: %+v", orig)
+	}
+}
+
+func TestCloneNilInterface(t *testing.T) {
+	if got := structutil.Clone[error](nil); got != nil {
+		t.Errorf("Clone[error](nil) = %v, want nil", got)
+	}
+	if got := structutil.Clone[any](nil); got != nil {
+		t.Errorf("Clone[any](nil) = %v, want nil", got)
+	}
+
+	err := errors.New("boom")
+	if got := structutil.Clone(err); got == nil || got.Error() != "boom" {
+		t.Errorf("Clone(err) = %v, want boom", got)
+	}
+}
+
+func TestCloneNilValues(t *testing.T) {
+	tests := []any{(*node)(nil), []int(nil), map[string]int(nil), node{}}
+	for _, v := range tests {
+		if got := structutil.Clone(v); !reflect.DeepEqual(got, v) {
+			t.Errorf("Clone(%#v) = %#v", v, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// structutil/diff.go
+package structutil
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is one difference found by Diff. Old or New is nil when the value
+// only exists on one side, e.g. a map key that was added.
+type Change struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// String formats the change as
+//
+//	User.Address.ZipCode: "90210" → "90211"
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Path, format(c.Old), format(c.New))
+}
+
+func format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Diff lists the exported fields, map entries and slice elements that
+// differ between a and b, which should have the same type or be pointers to
+// it. Paths start with the type name and use Go field names:
+// User.Address.ZipCode, Tags["color"], Items[2].Price. Pointers are
+// followed; types like time.Time that have an Equal method are compared
+// with it rather than field by field.
+func Diff(a, b any) []Change {
+	d := differ{seen: make(map[[2]uintptr]bool)}
+	va, vb := deref(reflect.ValueOf(a)), deref(reflect.ValueOf(b))
+	root := ""
+	if va.IsValid() {
+		root = va.Type().Name()
+	}
+	d.walk(root, va, vb)
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+	seen    map[[2]uintptr]bool // Pointer pairs already compared, for cycles.
+}
+
+func (d *differ) add(path string, a, b reflect.Value) {
+	c := Change{Path: path}
+	if a.IsValid() {
+		c.Old = a.Interface()
+	}
+	if b.IsValid() {
+		c.New = b.Interface()
+	}
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) walk(path string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		if a.IsValid() || b.IsValid() {
+			d.add(path, a, b)
+		}
+		return
+	}
+	if eq, ok := equalMethod(a, b); ok {
+		if !eq {
+			d.add(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, a, b)
+			}
+			return
+		}
+		if a.Kind() == reflect.Pointer {
+			key := [2]uintptr{a.Pointer(), b.Pointer()}
+			if d.seen[key] {
+				return
+			}
+			d.seen[key] = true
+		}
+		d.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		fields := exportedFields(a.Type())
+		if len(fields) == 0 {
+			if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+				d.add(path, a, b)
+			}
+			return
+		}
+		for _, i := range fields {
+			d.walk(join(path, a.Type().Field(i).Name), a.Field(i), b.Field(i))
+		}
+
+	case reflect.Map:
+		keys := a.MapKeys()
+		for _, k := range b.MapKeys() {
+			if !a.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		slices.SortFunc(keys, func(x, y reflect.Value) int {
+			return cmp.Compare(fmt.Sprint(x.Interface()), fmt.Sprint(y.Interface()))
+		})
+		for _, k := range keys {
+			d.walk(path+index(k.Interface()), a.MapIndex(k), b.MapIndex(k))
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := range max(a.Len(), b.Len()) {
+			var ea, eb reflect.Value
+			if i < a.Len() {
+				ea = a.Index(i)
+			}
+			if i < b.Len() {
+				eb = b.Index(i)
+			}
+			d.walk(path+index(i), ea, eb)
+		}
+
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			d.add(path, a, b)
+		}
+	}
+}
+
+// deref follows non-nil pointers, so Diff(u, &v) compares two Users.
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// equalMethod compares a and b with an Equal(T) bool method if T has one.
+func equalMethod(a, b reflect.Value) (equal, ok bool) {
+	m := a.MethodByName("Equal")
+	if !m.IsValid() || m.Type().NumIn() != 1 || m.Type().In(0) != a.Type() ||
+		m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+	return m.Call([]reflect.Value{b})[0].Bool(), true
+}
+
+func exportedFields(t reflect.Type) []int {
+	var out []int
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func index(k any) string {
+	if s, ok := k.(string); ok {
+		return fmt.Sprintf("[%q]", s)
+	}
+	return "[" + strings.TrimSpace(fmt.Sprint(k)) + "]"
+}
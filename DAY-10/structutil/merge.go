@@ -0,0 +1,97 @@
+// structutil/merge.go
+package structutil
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// CreateMergePatch returns a JSON Merge Patch (RFC 7386) that turns the
+// JSON form of a into the JSON form of b: an object holding only what
+// changed, with null for removed members. Arrays are replaced whole.
+//
+// Merge patches can't set a member to null, since null means "remove"; use
+// CreatePatch when that matters.
+func CreateMergePatch(a, b any) ([]byte, error) {
+	da, err := toDoc(a)
+	if err != nil {
+		return nil, err
+	}
+	db, err := toDoc(b)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeDiff(da, db))
+}
+
+func mergeDiff(a, b any) any {
+	ma, okA := a.(map[string]any)
+	mb, okB := b.(map[string]any)
+	if !okA || !okB {
+		return b
+	}
+	out := make(map[string]any)
+	for _, k := range slices.Sorted(maps.Keys(ma)) {
+		if _, ok := mb[k]; !ok {
+			out[k] = nil
+		}
+	}
+	for k, v := range mb {
+		old, ok := ma[k]
+		switch {
+		case !ok:
+			out[k] = v
+		case isObject(old) && isObject(v):
+			if sub := mergeDiff(old, v).(map[string]any); len(sub) > 0 {
+				out[k] = sub
+			}
+		case !equalDoc(old, v):
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func isObject(v any) bool {
+	_, ok := v.(map[string]any)
+	return ok
+}
+
+// ApplyMergePatch applies a JSON Merge Patch to the value dst points to,
+// e.g. {"address": {"zip_code": "90211"}} changes only the zip code.
+func ApplyMergePatch(dst any, patch []byte) error {
+	return applyJSON(dst, func(doc []byte) ([]byte, error) { return MergePatchJSON(doc, patch) })
+}
+
+// MergePatchJSON applies a JSON Merge Patch to a JSON document.
+func MergePatchJSON(doc, patch []byte) ([]byte, error) {
+	d, err := decodeDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodeDoc(patch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(d, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
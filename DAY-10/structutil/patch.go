@@ -0,0 +1,395 @@
+// structutil/patch.go
+package structutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch = errors.New("structutil: invalid patch")
+	ErrPathNotFound = errors.New("structutil: path not found")
+	ErrTestFailed   = errors.New("structutil: test operation failed")
+)
+
+// Operation is one step of a JSON Patch (RFC 6902). Op is "add", "remove",
+// "replace", "move", "copy" or "test"; Path and From are JSON Pointers
+// (RFC 6901) such as "/address/zip_code" or "/items/0".
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a JSON Patch document. It marshals to the standard JSON array.
+type Patch []Operation
+
+// CreatePatch returns a Patch that turns the JSON form of a into the JSON
+// form of b. Paths use JSON names, so struct tags are honoured. Object keys
+// are visited in sorted order, so the same inputs always give the same
+// patch.
+func CreatePatch(a, b any) (Patch, error) {
+	da, err := toDoc(a)
+	if err != nil {
+		return nil, err
+	}
+	db, err := toDoc(b)
+	if err != nil {
+		return nil, err
+	}
+	var p Patch
+	if err := diffDoc(&p, "", da, db); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func diffDoc(p *Patch, path string, a, b any) error {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, k := range slices.Sorted(maps.Keys(a)) {
+			if _, ok := b[k]; !ok {
+				*p = append(*p, Operation{Op: "remove", Path: path + "/" + escape(k)})
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(b)) {
+			child := path + "/" + escape(k)
+			old, ok := a[k]
+			if !ok {
+				if err := p.add("add", child, b[k]); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := diffDoc(p, child, old, b[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case []any:
+		b, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := range min(len(a), len(b)) {
+			if err := diffDoc(p, path+"/"+strconv.Itoa(i), a[i], b[i]); err != nil {
+				return err
+			}
+		}
+		for i := len(a) - 1; i >= len(b); i-- { // From the end, so indexes stay valid.
+			*p = append(*p, Operation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := len(a); i < len(b); i++ {
+			if err := p.add("add", path+"/-", b[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if equalDoc(a, b) {
+		return nil
+	}
+	return p.add("replace", path, b)
+}
+
+func (p *Patch) add(op, path string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	*p = append(*p, Operation{Op: op, Path: path, Value: raw})
+	return nil
+}
+
+// ApplyPatch applies p to the value dst points to. The patch is applied to
+// the JSON form of the value, which is then decoded into a fresh value of
+// the same type, so a field the patch removes ends up as its zero value. If
+// any operation fails, dst is left untouched.
+func ApplyPatch(dst any, p Patch) error {
+	return applyJSON(dst, func(doc []byte) ([]byte, error) { return ApplyPatchJSON(doc, p) })
+}
+
+// ApplyPatchJSON applies p to a JSON document.
+func ApplyPatchJSON(doc []byte, p Patch) ([]byte, error) {
+	d, err := decodeDoc(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, op := range p {
+		if d, err = applyOp(d, op); err != nil {
+			return nil, fmt.Errorf("structutil: patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(d)
+}
+
+func applyOp(doc any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		if value, err = decodeDoc(op.Value); err != nil {
+			return nil, err
+		}
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if hasPrefix(path, from) && len(path) > len(from) {
+				return nil, fmt.Errorf("%w: cannot move a value into itself", ErrInvalidPatch)
+			}
+			if doc, value, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = get(doc, from); err != nil {
+				return nil, err
+			}
+			value = Clone(value)
+		}
+	case "remove":
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+
+	switch op.Op {
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return insert(doc, path, value)
+	case "test":
+		got, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalDoc(got, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+	return insert(doc, path, value) // add, move and copy.
+}
+
+// insert adds value at path: it sets an object member, or inserts into an
+// array, where "-" means after the last element.
+func insert(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(parent any, key string) (any, error) {
+		switch parent := parent.(type) {
+		case map[string]any:
+			parent[key] = value
+			return parent, nil
+		case []any:
+			if key == "-" {
+				return append(parent, value), nil
+			}
+			i, err := arrayIndex(key, len(parent)+1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(parent, i, value), nil
+		}
+		return nil, fmt.Errorf("%w: %q is not in an object or array", ErrPathNotFound, key)
+	})
+}
+
+// remove deletes the value at path and returns it.
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+	var removed any
+	doc, err := update(doc, path, func(parent any, key string) (any, error) {
+		switch parent := parent.(type) {
+		case map[string]any:
+			v, ok := parent[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, key)
+			}
+			removed = v
+			delete(parent, key)
+			return parent, nil
+		case []any:
+			i, err := arrayIndex(key, len(parent))
+			if err != nil {
+				return nil, err
+			}
+			removed = parent[i]
+			return slices.Delete(parent, i, i+1), nil
+		}
+		return nil, fmt.Errorf("%w: %q is not in an object or array", ErrPathNotFound, key)
+	})
+	return doc, removed, err
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, key := range path {
+		switch d := doc.(type) {
+		case map[string]any:
+			v, ok := d[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrPathNotFound, key)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(key, len(d))
+			if err != nil {
+				return nil, err
+			}
+			doc = d[i]
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrPathNotFound, key)
+		}
+	}
+	return doc, nil
+}
+
+// update walks to the container holding the last key of path and replaces
+// it with what fn returns. Arrays may grow or shrink, so every level stores
+// the child it got back.
+func update(doc any, path []string, fn func(parent any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	key := path[0]
+	switch d := doc.(type) {
+	case map[string]any:
+		child, ok := d[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrPathNotFound, key)
+		}
+		child, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		d[key] = child
+		return d, nil
+	case []any:
+		i, err := arrayIndex(key, len(d))
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(d[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		d[i] = child
+		return d, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrPathNotFound, key)
+}
+
+// arrayIndex parses an array index that must be below limit.
+func arrayIndex(key string, limit int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (key != "0" && strings.HasPrefix(key, "0")) {
+		return 0, fmt.Errorf("%w: bad array index %q", ErrInvalidPatch, key)
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("%w: index %d out of range", ErrPathNotFound, i)
+	}
+	return i, nil
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrInvalidPatch, ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func hasPrefix(path, prefix []string) bool {
+	return len(path) >= len(prefix) && slices.Equal(path[:len(prefix)], prefix)
+}
+
+// toDoc converts v to the generic form of its JSON: maps, slices, strings,
+// json.Numbers, bools and nils.
+func toDoc(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDoc(data)
+}
+
+func decodeDoc(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // Keep int64 IDs exact.
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+	return doc, nil
+}
+
+// equalDoc compares two generic documents. Marshalling sorts object keys,
+// so equal documents give equal bytes.
+func equalDoc(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// applyJSON runs fn on the JSON form of the value dst points to and decodes
+// the result into dst.
+func applyJSON(dst any, fn func(doc []byte) ([]byte, error)) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("structutil: need a non-nil pointer, got %T", dst)
+	}
+	doc, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	if doc, err = fn(doc); err != nil {
+		return err
+	}
+	fresh := reflect.New(rv.Type().Elem())
+	if err := json.Unmarshal(doc, fresh.Interface()); err != nil {
+		return fmt.Errorf("structutil: patched document does not fit %s: %w", rv.Type().Elem(), err)
+	}
+	rv.Elem().Set(fresh.Elem())
+	return nil
+}
@@ -0,0 +1,83 @@
+// structutil/patch_test.go
+package structutil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"usermanagement/structutil"
+)
+
+func TestApplyPatchJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"copy null member", `{"a":null}`, `[{"op":"copy","from":"/a","path":"/b"}]`, `{"a":null,"b":null}`},
+		{"move null member", `{"a":null}`, `[{"op":"move","from":"/a","path":"/b"}]`, `{"b":null}`},
+		{"copy object", `{"a":{"x":1}}`, `[{"op":"copy","from":"/a","path":"/b"},{"op":"replace","path":"/b/x","value":2}]`,
+			`{"a":{"x":1},"b":{"x":2}}`},
+		{"add to array", `{"list":[1,3]}`, `[{"op":"add","path":"/list/1","value":2},{"op":"add","path":"/list/-","value":4}]`,
+			`{"list":[1,2,3,4]}`},
+		{"remove", `{"a":1,"b":2}`, `[{"op":"remove","path":"/a"}]`, `{"b":2}`},
+		{"test then replace", `{"a":null}`, `[{"op":"test","path":"/a","value":null},{"op":"replace","path":"/a","value":"x"}]`,
+			`{"a":"x"}`},
+		{"escaped pointer", `{"a/b":1,"c~d":2}`, `[{"op":"copy","from":"/a~1b","path":"/c~0d"}]`, `{"a/b":1,"c~d":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p structutil.Patch
+			if err := json.Unmarshal([]byte(tt.patch), &p); err != nil {
+				t.Fatal(err)
+			}
+			got, err := structutil.ApplyPatchJSON([]byte(tt.doc), p)
+			if err != nil {
+				t.Fatalf("ApplyPatchJSON: %v", err)
+			}
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  error
+	}{
+		{"missing from", `[{"op":"copy","from":"/nope","path":"/b"}]`, structutil.ErrPathNotFound},
+		{"failed test", `[{"op":"test","path":"/a","value":1}]`, structutil.ErrTestFailed},
+		{"unknown op", `[{"op":"swap","path":"/a"}]`, structutil.ErrInvalidPatch},
+		{"move into itself", `[{"op":"move","from":"/a","path":"/a/b"}]`, structutil.ErrInvalidPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p structutil.Patch
+			if err := json.Unmarshal([]byte(tt.patch), &p); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := structutil.ApplyPatchJSON([]byte(`{"a":null}`), p); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("decoding %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("decoding %s: %v", b, err)
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return string(ja) == string(jb)
+}
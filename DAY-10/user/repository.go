@@ -0,0 +1,127 @@
+// user/repository.go
+package user
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"usermanagement/model"
+)
+
+// Repository stores users. The Service only talks to this interface, so a
+// database-backed implementation can replace MemoryRepository without the
+// service noticing.
+//
+// Implementations must:
+//   - assign a new, unique ID in Create and return the stored user;
+//   - reject two users that are not soft-deleted with the same email,
+//     compared with EmailKey, by returning ErrEmailTaken;
+//   - return ErrNotFound for IDs they don't know. Soft-deleted users are
+//     still returned by Get and List; hiding them is the service's job.
+type Repository interface {
+	Create(ctx context.Context, u model.User) (model.User, error)
+	Get(ctx context.Context, id int) (model.User, error)
+	GetByEmail(ctx context.Context, email string) (model.User, error)
+	Update(ctx context.Context, u model.User) error
+	List(ctx context.Context) ([]model.User, error)
+}
+
+// EmailKey returns the form of an email address used to compare addresses:
+// trimmed and lower-cased, so "Alice@Example.com" and "alice@example.com"
+// are the same user.
+func EmailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// MemoryRepository is a Repository that keeps users in a map. It is safe for
+// concurrent use and forgets everything when the program exits.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	users   map[int]model.User
+	byEmail map[string]int // EmailKey -> ID, only for users not deleted.
+	lastID  int
+}
+
+// NewMemoryRepository returns an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users:   make(map[int]model.User),
+		byEmail: make(map[string]int),
+	}
+}
+
+func (r *MemoryRepository) Create(ctx context.Context, u model.User) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := EmailKey(u.Email)
+	if _, taken := r.byEmail[key]; taken && !u.Deleted() {
+		return model.User{}, ErrEmailTaken
+	}
+	r.lastID++
+	u.ID = r.lastID
+	r.users[u.ID] = u
+	if !u.Deleted() {
+		r.byEmail[key] = u.ID
+	}
+	return u, nil
+}
+
+func (r *MemoryRepository) Get(ctx context.Context, id int) (model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return model.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *MemoryRepository) GetByEmail(ctx context.Context, email string) (model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byEmail[EmailKey(email)]
+	if !ok {
+		return model.User{}, ErrNotFound
+	}
+	return r.users[id], nil
+}
+
+func (r *MemoryRepository) Update(ctx context.Context, u model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.users[u.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	key := EmailKey(u.Email)
+	if id, taken := r.byEmail[key]; taken && id != u.ID && !u.Deleted() {
+		return ErrEmailTaken
+	}
+	if !old.Deleted() {
+		delete(r.byEmail, EmailKey(old.Email))
+	}
+	if !u.Deleted() {
+		r.byEmail[key] = u.ID
+	}
+	r.users[u.ID] = u
+	return nil
+}
+
+// List returns every stored user, deleted ones included, sorted by ID.
+func (r *MemoryRepository) List(ctx context.Context) ([]model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]model.User, 0, len(r.users))
+	for _, u := range r.users {
+		out = append(out, u)
+	}
+	slices.SortFunc(out, func(a, b model.User) int { return a.ID - b.ID })
+	return out, nil
+}
@@ -0,0 +1,131 @@
+// user/repository_test.go
+package user_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"usermanagement/model"
+	"usermanagement/user"
+)
+
+func TestEmailKey(t *testing.T) {
+	for in, want := range map[string]string{
+		"alice@example.com":    "alice@example.com",
+		" Alice@Example.COM\t": "alice@example.com",
+		"":                     "",
+	} {
+		if got := user.EmailKey(in); got != want {
+			t.Errorf("EmailKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := user.NewMemoryRepository()
+
+	alice, err := repo.Create(ctx, model.User{ID: 99, Name: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.ID != 1 {
+		t.Errorf("Create assigned ID %d, want 1 regardless of the ID passed in", alice.ID)
+	}
+	if _, err := repo.Create(ctx, model.User{Name: "Alias", Email: "ALICE@example.com"}); !errors.Is(err, user.ErrEmailTaken) {
+		t.Errorf("Create with a taken email = %v, want ErrEmailTaken", err)
+	}
+	bob, err := repo.Create(ctx, model.User{Name: "Bob", Email: "bob@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Get(ctx, 3); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("Get(3) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Update(ctx, model.User{ID: 3}); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("Update(3) = %v, want ErrNotFound", err)
+	}
+
+	// Moving bob to a new address frees the old one.
+	bob.Email = "robert@example.com"
+	if err := repo.Update(ctx, bob); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetByEmail(ctx, "bob@example.com"); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("GetByEmail(old address) = %v, want ErrNotFound", err)
+	}
+	if got, err := repo.GetByEmail(ctx, "Robert@example.com"); err != nil || got != bob {
+		t.Errorf("GetByEmail(new address) = %+v, %v, want %+v", got, err, bob)
+	}
+	bob.Email = "alice@example.com"
+	if err := repo.Update(ctx, bob); !errors.Is(err, user.ErrEmailTaken) {
+		t.Errorf("Update to a taken email = %v, want ErrEmailTaken", err)
+	}
+
+	// Soft-deleted users stay in Get and List but release their email.
+	alice.DeletedAt = deletedAt
+	if err := repo.Update(ctx, alice); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.Get(ctx, alice.ID); err != nil || !got.Deleted() {
+		t.Errorf("Get(deleted) = %+v, %v, want the deleted record", got, err)
+	}
+	if _, err := repo.GetByEmail(ctx, "alice@example.com"); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("GetByEmail(deleted) = %v, want ErrNotFound", err)
+	}
+	carol, err := repo.Create(ctx, model.User{Name: "Carol", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("Create with a deleted user's email = %v", err)
+	}
+	users, err := repo.List(ctx)
+	if err != nil || !slices.Equal(ids(users), []int{alice.ID, bob.ID, carol.ID}) {
+		t.Errorf("List = %v, %v", ids(users), err)
+	}
+}
+
+func TestMemoryRepositoryConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	repo := user.NewMemoryRepository()
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every even worker races for the same address.
+			email := fmt.Sprintf("user%d@example.com", i)
+			if i%2 == 0 {
+				email = "shared@example.com"
+			}
+			_, errs[i] = repo.Create(ctx, model.User{Name: "U", Email: email})
+		}()
+	}
+	wg.Wait()
+
+	taken := 0
+	for _, err := range errs {
+		if errors.Is(err, user.ErrEmailTaken) {
+			taken++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if taken != workers/2-1 {
+		t.Errorf("%d creates failed with ErrEmailTaken, want %d", taken, workers/2-1)
+	}
+	users, _ := repo.List(ctx)
+	want := make([]int, workers-taken)
+	for i := range want {
+		want[i] = i + 1
+	}
+	if !slices.Equal(ids(users), want) {
+		t.Errorf("List IDs = %v, want %v", ids(users), want)
+	}
+}
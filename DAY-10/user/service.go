@@ -0,0 +1,160 @@
+// user/service.go
+package user // Create, read, update and soft-delete users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"usermanagement/model"
+)
+
+var (
+	ErrNotFound   = errors.New("user: not found")
+	ErrEmailTaken = errors.New("user: email already in use")
+	ErrInvalid    = errors.New("user: invalid user")
+)
+
+// NewUser holds what is needed to register a user. A nil IsActive
+// registers an active user.
+type NewUser struct {
+	Name     string
+	Email    string
+	IsActive *bool
+	Address  model.Address
+}
+
+// Changes lists the fields to update. Nil fields are left alone, so a
+// Changes{Email: &email} only touches the email address.
+type Changes struct {
+	Name     *string
+	Email    *string
+	IsActive *bool
+	Address  *model.Address
+}
+
+// ListOptions filters List. The zero value lists every user that has not
+// been deleted.
+type ListOptions struct {
+	IncludeDeleted bool
+}
+
+// Service implements the user management rules on top of a Repository.
+type Service struct {
+	repo Repository
+
+	// Now returns the time recorded when a user is deleted. It defaults to
+	// time.Now.
+	Now func() time.Time
+}
+
+// NewService returns a Service storing its users in repo.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, Now: time.Now}
+}
+
+// Create registers a new user and returns it with its assigned ID.
+func (s *Service) Create(ctx context.Context, nu NewUser) (model.User, error) {
+	u := model.User{
+		Name:     strings.TrimSpace(nu.Name),
+		Email:    strings.TrimSpace(nu.Email),
+		IsActive: nu.IsActive == nil || *nu.IsActive,
+		Address:  nu.Address,
+	}
+	if err := check(u); err != nil {
+		return model.User{}, err
+	}
+	return s.repo.Create(ctx, u)
+}
+
+// Get returns the user with the given ID. Deleted users are not found.
+func (s *Service) Get(ctx context.Context, id int) (model.User, error) {
+	u, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if u.Deleted() {
+		return model.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+// GetByEmail looks a user up by email address, ignoring case.
+func (s *Service) GetByEmail(ctx context.Context, email string) (model.User, error) {
+	return s.repo.GetByEmail(ctx, email)
+}
+
+// Update applies changes to a user and returns the result.
+func (s *Service) Update(ctx context.Context, id int, c Changes) (model.User, error) {
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if c.Name != nil {
+		u.Name = strings.TrimSpace(*c.Name)
+	}
+	if c.Email != nil {
+		u.Email = strings.TrimSpace(*c.Email)
+	}
+	if c.IsActive != nil {
+		u.IsActive = *c.IsActive
+	}
+	if c.Address != nil {
+		u.Address = *c.Address
+	}
+	if err := check(u); err != nil {
+		return model.User{}, err
+	}
+	if err := s.repo.Update(ctx, u); err != nil {
+		return model.User{}, err
+	}
+	return u, nil
+}
+
+// Deactivate marks a user inactive. The user can still be found and
+// reactivated with Update.
+func (s *Service) Deactivate(ctx context.Context, id int) (model.User, error) {
+	inactive := false
+	return s.Update(ctx, id, Changes{IsActive: &inactive})
+}
+
+// Delete soft-deletes a user: the record is kept with DeletedAt set, but Get
+// and List no longer return it and its email address becomes free again.
+func (s *Service) Delete(ctx context.Context, id int) error {
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.IsActive = false
+	u.DeletedAt = s.Now()
+	return s.repo.Update(ctx, u)
+}
+
+// List returns users sorted by ID.
+func (s *Service) List(ctx context.Context, opts ListOptions) ([]model.User, error) {
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opts.IncludeDeleted {
+		return all, nil
+	}
+	users := all[:0]
+	for _, u := range all {
+		if !u.Deleted() {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// check runs the model's validation rules. The returned error matches both
+// ErrInvalid and, with errors.As, validate.Errors.
+func check(u model.User) error {
+	if err := u.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+// user/service_test.go
+package user_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"usermanagement/model"
+	"usermanagement/user"
+	"usermanagement/validate"
+)
+
+var deletedAt = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+func newService() *user.Service {
+	s := user.NewService(user.NewMemoryRepository())
+	s.Now = func() time.Time { return deletedAt }
+	return s
+}
+
+func mustCreate(t *testing.T, s *user.Service, name, email string) model.User {
+	t.Helper()
+	u, err := s.Create(context.Background(), user.NewUser{Name: name, Email: email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func ids(users []model.User) []int {
+	var out []int
+	for _, u := range users {
+		out = append(out, u.ID)
+	}
+	return out
+}
+
+func TestCreate(t *testing.T) {
+	s := newService()
+	u, err := s.Create(context.Background(), user.NewUser{
+		Name:    "  Alice  ",
+		Email:   " alice@example.com ",
+		Address: model.Address{City: "Wonderland", ZipCode: "90210", Country: "US"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.User{ID: 1, Name: "Alice", Email: "alice@example.com", IsActive: true,
+		Address: model.Address{City: "Wonderland", ZipCode: "90210", Country: "US"}}
+	if u != want {
+		t.Errorf("Create = %+v, want %+v", u, want)
+	}
+	if got, err := s.Get(context.Background(), 1); err != nil || got != want {
+		t.Errorf("Get(1) = %+v, %v, want %+v", got, err, want)
+	}
+
+	inactive := false
+	u, err = s.Create(context.Background(), user.NewUser{Name: "Bob", Email: "bob@example.com", IsActive: &inactive})
+	if err != nil || u.IsActive {
+		t.Errorf("Create(IsActive: false) = %+v, %v, want an inactive user", u, err)
+	}
+}
+
+func TestCreateInvalid(t *testing.T) {
+	s := newService()
+	tests := []struct {
+		name   string
+		nu     user.NewUser
+		fields []string
+	}{
+		{"blank name", user.NewUser{Name: "   ", Email: "a@example.com"}, []string{"name"}},
+		{"bad email", user.NewUser{Name: "Alice", Email: "alice"}, []string{"email"}},
+		{"bad zip", user.NewUser{Name: "Alice", Email: "a@example.com",
+			Address: model.Address{ZipCode: "ABC", Country: "US"}}, []string{"address.zip_code"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Create(context.Background(), tt.nu)
+			if !errors.Is(err, user.ErrInvalid) {
+				t.Fatalf("Create = %v, want ErrInvalid", err)
+			}
+			var verrs validate.Errors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Create = %v, want validate.Errors", err)
+			}
+			var fields []string
+			for _, e := range verrs {
+				fields = append(fields, e.Path)
+			}
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("invalid fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+	if users, _ := s.List(context.Background(), user.ListOptions{IncludeDeleted: true}); len(users) != 0 {
+		t.Errorf("invalid users were stored: %+v", users)
+	}
+}
+
+func TestDuplicateEmail(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+	alice := mustCreate(t, s, "Alice", "alice@example.com")
+	bob := mustCreate(t, s, "Bob", "bob@example.com")
+
+	if _, err := s.Create(ctx, user.NewUser{Name: "Alice 2", Email: "ALICE@example.com"}); !errors.Is(err, user.ErrEmailTaken) {
+		t.Errorf("Create with a taken email = %v, want ErrEmailTaken", err)
+	}
+	email := " Alice@Example.com"
+	if _, err := s.Update(ctx, bob.ID, user.Changes{Email: &email}); !errors.Is(err, user.ErrEmailTaken) {
+		t.Errorf("Update to a taken email = %v, want ErrEmailTaken", err)
+	}
+	if got, _ := s.Get(ctx, bob.ID); got.Email != "bob@example.com" {
+		t.Errorf("failed update changed bob's email to %q", got.Email)
+	}
+
+	// Changing the case of your own address is fine.
+	email = "ALICE@example.com"
+	if _, err := s.Update(ctx, alice.ID, user.Changes{Email: &email}); err != nil {
+		t.Errorf("Update of alice's own email = %v", err)
+	}
+	if got, err := s.GetByEmail(ctx, "alice@EXAMPLE.com"); err != nil || got.ID != alice.ID {
+		t.Errorf("GetByEmail = %+v, %v, want alice", got, err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+	name := "Nobody"
+	tests := []struct {
+		name string
+		do   func() error
+	}{
+		{"Get", func() error { _, err := s.Get(ctx, 7); return err }},
+		{"GetByEmail", func() error { _, err := s.GetByEmail(ctx, "nobody@example.com"); return err }},
+		{"Update", func() error { _, err := s.Update(ctx, 7, user.Changes{Name: &name}); return err }},
+		{"Deactivate", func() error { _, err := s.Deactivate(ctx, 7); return err }},
+		{"Delete", func() error { return s.Delete(ctx, 7) }},
+	}
+	for _, tt := range tests {
+		if err := tt.do(); !errors.Is(err, user.ErrNotFound) {
+			t.Errorf("%s = %v, want ErrNotFound", tt.name, err)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+	u := mustCreate(t, s, "Alice", "alice@example.com")
+
+	name := " Alice Liddell "
+	addr := model.Address{City: "Oxford", ZipCode: "OX1 1DP", Country: "GB"}
+	got, err := s.Update(ctx, u.ID, user.Changes{Name: &name, Address: &addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := model.User{ID: u.ID, Name: "Alice Liddell", Email: "alice@example.com", IsActive: true, Address: addr}
+	if got != want {
+		t.Errorf("Update = %+v, want %+v", got, want)
+	}
+
+	empty := ""
+	if _, err := s.Update(ctx, u.ID, user.Changes{Name: &empty}); !errors.Is(err, user.ErrInvalid) {
+		t.Errorf("Update to an empty name = %v, want ErrInvalid", err)
+	}
+	if stored, _ := s.Get(ctx, u.ID); stored != want {
+		t.Errorf("failed update stored %+v", stored)
+	}
+
+	got, err = s.Deactivate(ctx, u.ID)
+	if err != nil || got.IsActive {
+		t.Fatalf("Deactivate = %+v, %v", got, err)
+	}
+	active := true
+	if got, err := s.Update(ctx, u.ID, user.Changes{IsActive: &active}); err != nil || !got.IsActive {
+		t.Errorf("reactivating = %+v, %v", got, err)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	s := newService()
+	alice := mustCreate(t, s, "Alice", "alice@example.com")
+	bob := mustCreate(t, s, "Bob", "bob@example.com")
+
+	if err := s.Delete(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, alice.ID); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("second Delete = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Get(ctx, alice.ID); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("Get of a deleted user = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetByEmail(ctx, alice.Email); !errors.Is(err, user.ErrNotFound) {
+		t.Errorf("GetByEmail of a deleted user = %v, want ErrNotFound", err)
+	}
+
+	users, err := s.List(ctx, user.ListOptions{})
+	if err != nil || !slices.Equal(ids(users), []int{bob.ID}) {
+		t.Errorf("List = %v, %v, want [%d]", ids(users), err, bob.ID)
+	}
+	all, err := s.List(ctx, user.ListOptions{IncludeDeleted: true})
+	if err != nil || !slices.Equal(ids(all), []int{alice.ID, bob.ID}) {
+		t.Fatalf("List(IncludeDeleted) = %v, %v", ids(all), err)
+	}
+	if !all[0].DeletedAt.Equal(deletedAt) || all[0].IsActive {
+		t.Errorf("deleted record = %+v, want inactive with DeletedAt %v", all[0], deletedAt)
+	}
+
+	// The deleted user's email is free again, and the record is kept.
+	again := mustCreate(t, s, "Alice again", "alice@example.com")
+	if again.ID == alice.ID {
+		t.Errorf("re-registered user reused ID %d", again.ID)
+	}
+	if got, err := s.GetByEmail(ctx, "alice@example.com"); err != nil || got.ID != again.ID {
+		t.Errorf("GetByEmail = %+v, %v, want the new user", got, err)
+	}
+}
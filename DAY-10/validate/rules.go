@@ -0,0 +1,110 @@
+// validate/rules.go
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// builtins are the rules every Validator starts with.
+var builtins = map[string]Func{
+	"required": required,
+	"email":    email,
+	"min":      minRule,
+	"max":      maxRule,
+	"regexp":   regexpRule,
+	"oneof":    oneof,
+}
+
+// required fails on the zero value of the field's type.
+func required(f Field) error {
+	if !f.Value.IsValid() || f.Value.IsZero() {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+// email accepts a bare address such as "alice@example.com". Display names
+// ("Alice <alice@example.com>") are rejected: we want the address only.
+func email(f Field) error {
+	s := f.Value.String()
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || !strings.Contains(addr.Address[strings.LastIndex(addr.Address, "@"):], ".") {
+		return fmt.Errorf("%q is not a valid email address", s)
+	}
+	return nil
+}
+
+// minRule checks numbers against their value and strings, slices and maps
+// against their length.
+func minRule(f Field) error {
+	limit, err := parseLimit(f.Param)
+	if err != nil {
+		return err
+	}
+	n, isLen, ok := number(f.Value)
+	switch {
+	case !ok:
+		return fmt.Errorf("min does not apply to %s", f.Value.Kind())
+	case n < limit && isLen:
+		return fmt.Errorf("must have at least %s elements", f.Param)
+	case n < limit:
+		return fmt.Errorf("must be at least %s", f.Param)
+	}
+	return nil
+}
+
+// maxRule is the counterpart of minRule.
+func maxRule(f Field) error {
+	limit, err := parseLimit(f.Param)
+	if err != nil {
+		return err
+	}
+	n, isLen, ok := number(f.Value)
+	switch {
+	case !ok:
+		return fmt.Errorf("max does not apply to %s", f.Value.Kind())
+	case n > limit && isLen:
+		return fmt.Errorf("must have at most %s elements", f.Param)
+	case n > limit:
+		return fmt.Errorf("must be at most %s", f.Param)
+	}
+	return nil
+}
+
+// patterns caches compiled regexp rules; the same tag is checked for every
+// value of a type, so compiling once pays off.
+var patterns sync.Map // string -> *regexp.Regexp
+
+func regexpRule(f Field) error {
+	re, ok := patterns.Load(f.Param)
+	if !ok {
+		compiled, err := regexp.Compile(f.Param)
+		if err != nil {
+			return fmt.Errorf("bad pattern %q: %v", f.Param, err)
+		}
+		re, _ = patterns.LoadOrStore(f.Param, compiled)
+	}
+	if f.Value.Kind() != reflect.String {
+		return fmt.Errorf("regexp does not apply to %s", f.Value.Kind())
+	}
+	if !re.(*regexp.Regexp).MatchString(f.Value.String()) {
+		return fmt.Errorf("%q does not match %s", f.Value.String(), f.Param)
+	}
+	return nil
+}
+
+// oneof accepts a space-separated list of allowed values: oneof=red green.
+func oneof(f Field) error {
+	allowed := strings.Fields(f.Param)
+	if slices.Contains(allowed, fmt.Sprint(f.Value.Interface())) {
+		return nil
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+}
@@ -0,0 +1,317 @@
+// validate/validate.go
+package validate // Struct validation driven by `validate:"..."` tags
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Field is what a validation func gets to look at.
+type Field struct {
+	Path   string        // JSON path of the field, e.g. "address.zip_code".
+	Name   string        // Go name of the field, e.g. "ZipCode".
+	Value  reflect.Value // The field's value, with pointers dereferenced.
+	Param  string        // Whatever followed "=" in the rule, e.g. "0" in min=0.
+	Parent reflect.Value // The struct holding the field, for cross-field rules.
+}
+
+// Func checks one field. It returns nil when the field is fine and an error
+// whose message explains the problem otherwise.
+type Func func(f Field) error
+
+// Validator holds the rules that tags can refer to. The zero value is not
+// usable; create one with New, or use the package-level functions, which
+// share a default Validator.
+type Validator struct {
+	mu    sync.RWMutex
+	rules map[string]Func
+}
+
+// New returns a Validator that knows the built-in rules: required, email,
+// min, max, regexp and oneof.
+func New() *Validator {
+	v := &Validator{rules: make(map[string]Func)}
+	for name, fn := range builtins {
+		v.rules[name] = fn
+	}
+	return v
+}
+
+// Register adds a custom rule, or replaces an existing one, under name.
+func (v *Validator) Register(name string, fn Func) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = fn
+}
+
+// Struct validates s, which must be a struct or a pointer to one, and every
+// struct nested inside it (through fields, pointers, slices and maps). It
+// returns nil or an Errors value listing every failing field, not just the
+// first one.
+//
+// Rules are given in the `validate` tag, separated by commas:
+//
+//	Email string  `json:"email" validate:"required,email"`
+//	Price float64 `json:"price" validate:"min=0"`
+//	Code  string  `json:"code" validate:"regexp=^[A-Z]{2,3}$"`
+//
+// Because regular expressions often contain commas, regexp must be the
+// last rule of a tag: everything after "regexp=" is the pattern. Except for
+// required, rules skip empty strings, nil pointers, empty slices and empty
+// maps, so optional fields only need to be valid when set.
+//
+// A pointer, slice or map reached more than once, as in a linked list whose
+// last node points back to the first, is only validated the first time, and
+// its errors are reported under that first path.
+func (v *Validator) Struct(s any) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: Struct needs a struct, got %T", s)
+	}
+	var errs Errors
+	seen := make(visited)
+	if p := reflect.ValueOf(s); p.Kind() == reflect.Pointer {
+		seen.first(p)
+	}
+	v.walk(rv, "", &errs, seen)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// walk visits the fields of the struct value rv, whose JSON path is prefix.
+func (v *Validator) walk(rv reflect.Value, prefix string, errs *Errors, seen visited) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		path := joinPath(prefix, sf)
+		fv := rv.Field(i)
+		if tag, ok := sf.Tag.Lookup("validate"); ok && tag != "-" {
+			v.check(Field{Path: path, Name: sf.Name, Value: fv, Parent: rv}, tag, errs)
+		}
+		v.descend(fv, path, errs, seen)
+	}
+}
+
+// descend looks for structs to validate inside fv. Pointers, slices and
+// maps already entered elsewhere in the value are skipped, so a value that
+// refers back to itself is validated once instead of forever.
+func (v *Validator) descend(fv reflect.Value, path string, errs *Errors, seen visited) {
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() || !seen.first(fv) {
+			return
+		}
+		fv = fv.Elem()
+	}
+	switch fv.Kind() {
+	case reflect.Struct:
+		v.walk(fv, path, errs, seen)
+	case reflect.Slice, reflect.Array:
+		if !seen.first(fv) {
+			return
+		}
+		for i := 0; i < fv.Len(); i++ {
+			v.descend(fv.Index(i), fmt.Sprintf("%s[%d]", path, i), errs, seen)
+		}
+	case reflect.Map:
+		if !seen.first(fv) {
+			return
+		}
+		iter := fv.MapRange()
+		for iter.Next() {
+			v.descend(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), errs, seen)
+		}
+	}
+}
+
+// visit identifies a pointer, slice or map by its type and address.
+type visit struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// visited records what one Struct call has entered so far.
+type visited map[visit]bool
+
+// first reports whether rv is seen for the first time, and records it.
+// Interfaces, arrays and empty slices have no address of their own and are
+// always new.
+func (s visited) first(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map:
+	case reflect.Slice:
+		if rv.Len() == 0 {
+			return true
+		}
+	default:
+		return true
+	}
+	k := visit{rv.Type(), rv.Pointer()}
+	if s[k] {
+		return false
+	}
+	s[k] = true
+	return true
+}
+
+// check runs the rules of one tag against a field.
+func (v *Validator) check(f Field, tag string, errs *Errors) {
+	value := f.Value
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	f.Value = value
+
+	for _, rule := range splitRules(tag) {
+		name, param, _ := strings.Cut(rule, "=")
+		v.mu.RLock()
+		fn, ok := v.rules[name]
+		v.mu.RUnlock()
+		if !ok {
+			*errs = append(*errs, &FieldError{Path: f.Path, Rule: name, Message: fmt.Sprintf("unknown validation rule %q", name)})
+			continue
+		}
+		if name != "required" && empty(value) {
+			continue
+		}
+		f.Param = param
+		if err := fn(f); err != nil {
+			*errs = append(*errs, &FieldError{Path: f.Path, Rule: name, Param: param, Message: err.Error()})
+		}
+	}
+}
+
+// splitRules splits a tag on commas, keeping everything after "regexp=" as
+// a single rule.
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, "regexp=") {
+			return append(rules, tag)
+		}
+		rule, rest, _ := strings.Cut(tag, ",")
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+		tag = strings.TrimSpace(rest)
+	}
+	return rules
+}
+
+// joinPath appends the JSON name of sf to prefix. Fields without a json tag
+// use their Go name, like encoding/json does.
+func joinPath(prefix string, sf reflect.StructField) string {
+	name := sf.Name
+	if tag, ok := sf.Tag.Lookup("json"); ok {
+		if n, _, _ := strings.Cut(tag, ","); n != "" && n != "-" {
+			name = n
+		}
+	}
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// empty reports whether v is a nil pointer, or a string, slice or map with
+// no elements. Numbers and booleans are never empty: min=1 must fail on 0.
+func empty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// FieldError describes one failed rule on one field.
+type FieldError struct {
+	Path    string // JSON path, e.g. "address.zip_code".
+	Rule    string // The failing rule, e.g. "email".
+	Param   string // The rule's parameter, if any.
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Errors collects every FieldError found by Struct.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "validate: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.As find the individual *FieldError values.
+func (e Errors) Unwrap() []error {
+	out := make([]error, len(e))
+	for i, fe := range e {
+		out[i] = fe
+	}
+	return out
+}
+
+// ByPath returns the errors reported for the field at path.
+func (e Errors) ByPath(path string) []*FieldError {
+	var out []*FieldError
+	for _, fe := range e {
+		if fe.Path == path {
+			out = append(out, fe)
+		}
+	}
+	return out
+}
+
+var std = New()
+
+// Struct validates s with the default Validator.
+func Struct(s any) error {
+	return std.Struct(s)
+}
+
+// Register adds a custom rule to the default Validator.
+func Register(name string, fn Func) {
+	std.Register(name, fn)
+}
+
+// number returns v as a float64 for min and max; ok is false when v is
+// neither a number nor something with a length.
+func number(v reflect.Value) (n float64, isLen bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String:
+		return float64(len([]rune(v.String()))), true, true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true, true
+	}
+	return 0, false, false
+}
+
+func parseLimit(param string) (float64, error) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad limit %q", param)
+	}
+	return limit, nil
+}
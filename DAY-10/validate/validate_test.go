@@ -0,0 +1,251 @@
+// validate/validate_test.go
+package validate_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"usermanagement/validate"
+)
+
+// paths returns the path and rule of every error in err, "path:rule".
+func paths(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v is not a validate.Errors", err)
+	}
+	var out []string
+	for _, fe := range errs {
+		out = append(out, fe.Path+":"+fe.Rule)
+	}
+	return out
+}
+
+func TestRules(t *testing.T) {
+	type (
+		required struct {
+			S string         `validate:"required"`
+			N int            `validate:"required"`
+			P *int           `validate:"required"`
+			M map[string]int `validate:"required"`
+		}
+		email struct {
+			E string `validate:"email"`
+		}
+		minmax struct {
+			N int      `validate:"min=1,max=3"`
+			F float64  `validate:"min=-1.5"`
+			U uint8    `validate:"max=9"`
+			S string   `validate:"min=2,max=4"`
+			L []string `validate:"max=2"`
+			P *int     `validate:"min=5"`
+		}
+		re struct {
+			Code string `validate:"regexp=^[A-Z]{2,3}$"`
+		}
+		oneof struct {
+			Color string `validate:"oneof=red green"`
+			Size  int    `validate:"oneof=1 2 3"`
+		}
+		misuse struct {
+			B bool   `validate:"min=1"`
+			N int    `validate:"regexp=^1$"`
+			S string `validate:"min=x"`
+			X string `validate:"nosuchrule"`
+		}
+	)
+	one, nine := 1, 9
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{"required zero", required{}, []string{"S:required", "N:required", "P:required", "M:required"}},
+		{"required set", required{S: "x", N: 1, P: &one, M: map[string]int{}}, nil},
+
+		{"email ok", email{"alice@example.com"}, nil},
+		{"email empty is skipped", email{""}, nil},
+		{"email no at", email{"alice"}, []string{"E:email"}},
+		{"email no dot in domain", email{"alice@localhost"}, []string{"E:email"}},
+		{"email display name", email{"Alice <alice@example.com>"}, []string{"E:email"}},
+
+		{"minmax ok", minmax{N: 2, F: -1.5, U: 9, S: "abcd", L: []string{"a", "b"}, P: &nine}, nil},
+		// Numbers are never empty: N=0 fails min=1 even though it's the
+		// zero value. Strings count runes, not bytes.
+		{"minmax low", minmax{N: 0, F: -2, S: "é", P: &one}, []string{"N:min", "F:min", "S:min", "P:min"}},
+		{"minmax high", minmax{N: 4, U: 10, S: "ééééé", L: []string{"a", "b", "c"}, P: &nine},
+			[]string{"N:max", "U:max", "S:max", "L:max"}},
+
+		{"regexp ok", re{"GB"}, nil},
+		{"regexp bad", re{"gbr"}, []string{"Code:regexp"}},
+		{"regexp too long", re{"ABCD"}, []string{"Code:regexp"}},
+
+		{"oneof ok", oneof{"green", 2}, nil},
+		{"oneof bad", oneof{"blue", 4}, []string{"Color:oneof", "Size:oneof"}},
+
+		{"misused rules", misuse{N: 1, S: "s", X: "x"}, []string{"B:min", "N:regexp", "S:min", "X:nosuchrule"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paths(t, validate.Struct(tt.v)); !slices.Equal(got, tt.want) {
+				t.Errorf("Struct(%+v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexpIsLast(t *testing.T) {
+	// The pattern holds commas, which would otherwise split it into rules.
+	type code struct {
+		Code string `validate:"required, max=5, regexp=^[a-z]{1,2}(,[a-z]{1,2})*$"`
+	}
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{"ab,cd", nil},
+		{"a,b,c", nil},
+		{"ab;cd", []string{"Code:regexp"}},
+		{"ab,cd,ef", []string{"Code:max"}},
+		{"", []string{"Code:required"}},
+	}
+	for _, tt := range tests {
+		if got := paths(t, validate.Struct(code{tt.code})); !slices.Equal(got, tt.want) {
+			t.Errorf("Code %q: %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	var errs validate.Errors
+	errors.As(validate.Struct(code{"ab;cd"}), &errs)
+	if want := "^[a-z]{1,2}(,[a-z]{1,2})*$"; errs[0].Param != want {
+		t.Errorf("regexp Param = %q, want %q", errs[0].Param, want)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	type (
+		line struct {
+			SKU string `json:"sku" validate:"required"`
+		}
+		order struct {
+			ID      string           `json:"id,omitempty" validate:"required"`
+			Lines   []line           `json:"lines"`
+			ByShop  map[string]*line `json:"by_shop"`
+			Nested  [][]line         `json:"nested"`
+			Ignored line             `json:"-"`
+			NoTag   line
+			Any     any `json:"any"`
+			private line
+		}
+	)
+	o := order{
+		Lines:   []line{{"A"}, {}, {"C"}},
+		ByShop:  map[string]*line{"north": {}, "south": nil},
+		Nested:  [][]line{{{"x"}}, {{"y"}, {}}},
+		Ignored: line{},
+		NoTag:   line{},
+		Any:     &line{},
+		private: line{},
+	}
+	got := paths(t, validate.Struct(&o))
+	want := []string{
+		"id:required",
+		"lines[1].sku:required",
+		"by_shop[north].sku:required",
+		"nested[1][1].sku:required",
+		"Ignored.sku:required",
+		"NoTag.sku:required",
+		"any.sku:required",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("paths = %v\nwant %v", got, want)
+	}
+
+	var errs validate.Errors
+	errors.As(validate.Struct(&o), &errs)
+	if fes := errs.ByPath("lines[1].sku"); len(fes) != 1 || fes[0].Message != "is required" {
+		t.Errorf("ByPath(lines[1].sku) = %v", fes)
+	}
+}
+
+type node struct {
+	Name string  `json:"name" validate:"required"`
+	Next *node   `json:"next"`
+	Kids []*node `json:"kids"`
+}
+
+func TestCycles(t *testing.T) {
+	// A ring of two nodes, one of them invalid.
+	a := &node{Name: "a"}
+	b := &node{Next: a}
+	a.Next = b
+	if got, want := paths(t, validate.Struct(a)), []string{"next.name:required"}; !slices.Equal(got, want) {
+		t.Errorf("ring: %v, want %v", got, want)
+	}
+
+	// A node that lists itself among its children.
+	self := &node{}
+	self.Next = self
+	self.Kids = []*node{self, {Name: "kid"}}
+	if got, want := paths(t, validate.Struct(self)), []string{"name:required"}; !slices.Equal(got, want) {
+		t.Errorf("self: %v, want %v", got, want)
+	}
+
+	// A map that holds itself.
+	type bag struct {
+		M map[string]any `json:"m"`
+	}
+	m := map[string]any{"bad": &node{}}
+	m["self"] = m
+	if got, want := paths(t, validate.Struct(bag{m})), []string{"m[bad].name:required"}; !slices.Equal(got, want) {
+		t.Errorf("map: %v, want %v", got, want)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	type pair struct {
+		Low  int `validate:"min=0"`
+		High int `validate:"gte=Low"`
+	}
+	v := validate.New()
+	v.Register("gte", func(f validate.Field) error {
+		other := f.Parent.FieldByName(f.Param).Int()
+		if f.Value.Int() < other {
+			return fmt.Errorf("must be at least %s (%d)", f.Param, other)
+		}
+		return nil
+	})
+	if err := v.Struct(pair{1, 2}); err != nil {
+		t.Errorf("Struct(1, 2) = %v", err)
+	}
+	err := v.Struct(pair{3, 2})
+	if got := paths(t, err); !slices.Equal(got, []string{"High:gte"}) {
+		t.Errorf("Struct(3, 2) = %v", got)
+	}
+	if !strings.Contains(err.Error(), "High: must be at least Low (3)") {
+		t.Errorf("Error() = %q", err)
+	}
+
+	// The rule is only known to v.
+	err = validate.Struct(pair{1, 2})
+	if err == nil || !strings.Contains(err.Error(), `unknown validation rule "gte"`) {
+		t.Errorf("default Validator = %v, want an unknown rule error", err)
+	}
+}
+
+func TestStructNeedsStruct(t *testing.T) {
+	for _, v := range []any{nil, 3, "s", (*node)(nil), []node{}} {
+		err := validate.Struct(v)
+		var errs validate.Errors
+		if err == nil || errors.As(err, &errs) {
+			t.Errorf("Struct(%#v) = %v, want a non-field error", v, err)
+		}
+	}
+}
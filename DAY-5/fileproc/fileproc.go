@@ -0,0 +1,222 @@
+// fileproc/fileproc.go
+package fileproc // Stream files through pluggable stages, closing everything on the way out
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+)
+
+// Record is one piece of input, usually a line, on its way through a
+// Pipeline.
+type Record struct {
+	Source string // The file it came from.
+	Line   int    // Its position in that file, from 1.
+	Text   string
+}
+
+// Stage processes one record at a time and passes what it produces on with
+// emit: a filter calls emit zero or one times, a transformer once with a
+// changed record, a splitter once per piece. A Stage that also implements
+// io.Closer is closed when the pipeline finishes.
+type Stage interface {
+	Process(r Record, emit func(Record) error) error
+}
+
+// StageFunc turns a function into a Stage.
+type StageFunc func(r Record, emit func(Record) error) error
+
+func (f StageFunc) Process(r Record, emit func(Record) error) error {
+	return f(r, emit)
+}
+
+// Sink receives the records that make it through every stage. Close is
+// always called once the pipeline is done, whether or not it succeeded.
+type Sink interface {
+	Write(r Record) error
+	Close() error
+}
+
+// Pipeline reads files, cuts them into records with Split, pushes each
+// record through Stages in order and hands the survivors to Sink.
+type Pipeline struct {
+	// Split cuts the input into records. It defaults to bufio.ScanLines.
+	Split bufio.SplitFunc
+
+	// MaxRecordSize is the longest record Split may return, in bytes. It
+	// defaults to 1 MiB.
+	MaxRecordSize int
+
+	Stages []Stage
+	Sink   Sink
+
+	// KeepGoing moves on to the next file when one fails, instead of
+	// stopping. The errors of every failed file are returned together.
+	KeepGoing bool
+}
+
+// FileResult says how far the pipeline got with one file.
+type FileResult struct {
+	Path    string
+	Read    int // Records read from the file.
+	Written int // Records that reached the sink.
+	Err     error
+}
+
+// Result is what Run managed to do, even when it also returns an error.
+type Result struct {
+	Files   []FileResult
+	Read    int
+	Written int
+}
+
+// PanicError is a panic in a stage or sink, turned into an error so the
+// pipeline can still close its files and report what it had done.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Run processes the files in order. Every file is closed as soon as it has
+// been read, and the sink and any stages that are io.Closers are closed
+// before Run returns, even if a stage panics.
+//
+// The error joins, with errors.Join, everything that went wrong: the
+// processing error of each failed file, a *PanicError for a panic, and the
+// error of every Close that failed. The Result says how far each file got.
+func (p *Pipeline) Run(ctx context.Context, paths ...string) (res Result, err error) {
+	defer func() {
+		err = errors.Join(err, p.close())
+	}()
+	if p.Sink == nil {
+		return res, errors.New("fileproc: pipeline has no sink")
+	}
+
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return res, errors.Join(err, res.err())
+		}
+		fr := p.runFile(ctx, path)
+		res.Files = append(res.Files, fr)
+		res.Read += fr.Read
+		res.Written += fr.Written
+		if fr.Err != nil && !p.KeepGoing {
+			break
+		}
+	}
+	return res, res.err()
+}
+
+func (r Result) err() error {
+	var errs []error
+	for _, f := range r.Files {
+		errs = append(errs, f.Err)
+	}
+	return errors.Join(errs...)
+}
+
+// runFile sends one file through the pipeline. A panic is recovered here
+// so the file is closed and counted like any other failure.
+func (p *Pipeline) runFile(ctx context.Context, path string) (fr FileResult) {
+	fr.Path = path
+	f, err := os.Open(path)
+	if err != nil {
+		fr.Err = fmt.Errorf("fileproc: %w", err) // Already names the file.
+		return fr
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			fr.Err = errors.Join(fr.Err, &PanicError{Value: r, Stack: debug.Stack()})
+		}
+		if err := f.Close(); err != nil {
+			fr.Err = errors.Join(fr.Err, fmt.Errorf("closing: %w", err))
+		}
+		if fr.Err != nil {
+			fr.Err = fmt.Errorf("fileproc: %s: %w", path, fr.Err)
+		}
+	}()
+
+	sc := bufio.NewScanner(f)
+	if p.Split != nil {
+		sc.Split(p.Split)
+	}
+	size := p.MaxRecordSize
+	if size <= 0 {
+		size = 1 << 20
+	}
+	sc.Buffer(make([]byte, 0, min(size, 64<<10)), size)
+
+	sink := func(r Record) error {
+		if err := p.Sink.Write(r); err != nil {
+			return err
+		}
+		fr.Written++
+		return nil
+	}
+	emit := p.chain(0, sink)
+	for sc.Scan() {
+		if err := ctx.Err(); err != nil {
+			fr.Err = err
+			return fr
+		}
+		fr.Read++
+		if err := emit(Record{Source: path, Line: fr.Read, Text: sc.Text()}); err != nil {
+			fr.Err = fmt.Errorf("record %d: %w", fr.Read, err)
+			return fr
+		}
+	}
+	fr.Err = sc.Err()
+	return fr
+}
+
+// chain returns the function that feeds a record to stage i, which in
+// turn emits into stage i+1, and the last stage into sink.
+func (p *Pipeline) chain(i int, sink func(Record) error) func(Record) error {
+	if i == len(p.Stages) {
+		return sink
+	}
+	next := p.chain(i+1, sink)
+	stage := p.Stages[i]
+	return func(r Record) error {
+		return stage.Process(r, next)
+	}
+}
+
+// close closes the stages that need it, last stage first, then the sink.
+// A panicking Close is reported like a failing one.
+func (p *Pipeline) close() error {
+	var closers []io.Closer
+	if p.Sink != nil {
+		closers = append(closers, p.Sink)
+	}
+	for _, s := range p.Stages {
+		if c, ok := s.(io.Closer); ok {
+			closers = append(closers, c)
+		}
+	}
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		errs = append(errs, safeClose(closers[i]))
+	}
+	return errors.Join(errs...)
+}
+
+func safeClose(c io.Closer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("fileproc: closing %T: %w", c, &PanicError{Value: r, Stack: debug.Stack()})
+		}
+	}()
+	if err := c.Close(); err != nil {
+		return fmt.Errorf("fileproc: closing %T: %w", c, err)
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+// fileproc/fileproc_test.go
+package fileproc_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"functions/fileproc"
+)
+
+// writeFiles creates one file per text in a temporary directory and
+// returns their paths, named a.txt, b.txt and so on.
+func writeFiles(t *testing.T, texts ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i, text := range texts {
+		path := filepath.Join(dir, string(rune('a'+i))+".txt")
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// isOpen reports whether this process still has path open. It needs
+// /proc, so tests calling it are skipped elsewhere.
+func isOpen(t *testing.T, path string) bool {
+	t.Helper()
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("needs /proc/self/fd:", err)
+	}
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && target == path {
+			return true
+		}
+	}
+	return false
+}
+
+// closeLog records the order in which stages and sinks are closed.
+type closeLog []string
+
+// closer is a stage that passes records on and logs its Close.
+type closer struct {
+	name string
+	log  *closeLog
+}
+
+func (c closer) Process(r fileproc.Record, emit func(fileproc.Record) error) error { return emit(r) }
+func (c closer) Close() error                                                      { *c.log = append(*c.log, c.name); return nil }
+
+// sink collects texts, logs its Close and returns closeErr from it.
+type sink struct {
+	texts    []string
+	log      *closeLog
+	closeErr error
+}
+
+func (s *sink) Write(r fileproc.Record) error { s.texts = append(s.texts, r.Text); return nil }
+
+func (s *sink) Close() error {
+	if s.log != nil {
+		*s.log = append(*s.log, "sink")
+	}
+	return s.closeErr
+}
+
+func TestRun(t *testing.T) {
+	paths := writeFiles(t, "# header\nalpha\n\nbeta,gamma\n", "delta")
+	var got []fileproc.Record
+	p := fileproc.Pipeline{
+		Stages: []fileproc.Stage{
+			fileproc.Filter(func(r fileproc.Record) bool { return r.Text != "" && !strings.HasPrefix(r.Text, "#") }),
+			fileproc.SplitOn(","),
+			fileproc.Transform(strings.ToUpper),
+		},
+		Sink: fileproc.Collect(&got),
+	}
+	res, err := p.Run(context.Background(), paths...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fileproc.Record{
+		{Source: paths[0], Line: 2, Text: "ALPHA"},
+		{Source: paths[0], Line: 4, Text: "BETA"},
+		{Source: paths[0], Line: 4, Text: "GAMMA"},
+		{Source: paths[1], Line: 1, Text: "DELTA"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("records = %+v, want %+v", got, want)
+	}
+	if res.Read != 5 || res.Written != 4 || len(res.Files) != 2 || res.Files[0].Read != 4 || res.Files[0].Written != 3 {
+		t.Errorf("Result = %+v", res)
+	}
+}
+
+func TestDelimited(t *testing.T) {
+	paths := writeFiles(t, "a b\x00c\x00\x00d")
+	s := &sink{}
+	p := fileproc.Pipeline{Split: fileproc.Delimited(0), Sink: s}
+	if _, err := p.Run(context.Background(), paths...); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a b", "c", "", "d"}; !slices.Equal(s.texts, want) {
+		t.Errorf("records = %q, want %q", s.texts, want)
+	}
+}
+
+func TestPanicInStage(t *testing.T) {
+	paths := writeFiles(t, "one\ntwo\nthree\n", "four\n")
+	var log closeLog
+	s := &sink{log: &log}
+	p := fileproc.Pipeline{
+		Stages: []fileproc.Stage{
+			closer{"first", &log},
+			fileproc.Map(func(r fileproc.Record) (fileproc.Record, error) {
+				if r.Text == "two" {
+					panic("bad record")
+				}
+				return r, nil
+			}),
+			closer{"last", &log},
+		},
+		Sink: s,
+	}
+	res, err := p.Run(context.Background(), paths...)
+
+	var pe *fileproc.PanicError
+	if !errors.As(err, &pe) || pe.Value != "bad record" || len(pe.Stack) == 0 {
+		t.Fatalf("Run = %v, want a *PanicError with its stack", err)
+	}
+	if !strings.Contains(err.Error(), paths[0]) {
+		t.Errorf("Run = %v, want it to name the file", err)
+	}
+	if isOpen(t, paths[0]) {
+		t.Error("the file being read when the stage panicked is still open")
+	}
+	if want := (closeLog{"last", "first", "sink"}); !slices.Equal(log, want) {
+		t.Errorf("closed %v, want %v", log, want)
+	}
+	// The first file stops at the panic, and the second is not read.
+	if len(res.Files) != 1 || res.Files[0].Read != 2 || res.Files[0].Written != 1 || !slices.Equal(s.texts, []string{"one"}) {
+		t.Errorf("Result = %+v, sink got %q", res, s.texts)
+	}
+}
+
+func TestSinkCloseFails(t *testing.T) {
+	paths := writeFiles(t, "one\ntwo\n")
+	closeErr := errors.New("disk full")
+	res, err := (&fileproc.Pipeline{Sink: &sink{closeErr: closeErr}}).Run(context.Background(), paths...)
+	if !errors.Is(err, closeErr) || !strings.Contains(err.Error(), "closing *fileproc_test.sink") {
+		t.Errorf("Run = %v, want the failed Close", err)
+	}
+	if res.Written != 2 {
+		t.Errorf("Written = %d, want 2: the records got through before Close failed", res.Written)
+	}
+
+	// A panicking Close is reported, and the other closers still run.
+	var log closeLog
+	p := fileproc.Pipeline{
+		Stages: []fileproc.Stage{closer{"stage", &log}, panicCloser{}},
+		Sink:   &sink{log: &log},
+	}
+	_, err = p.Run(context.Background(), paths...)
+	var pe *fileproc.PanicError
+	if !errors.As(err, &pe) || pe.Value != "close" {
+		t.Errorf("Run = %v, want a *PanicError from Close", err)
+	}
+	if want := (closeLog{"stage", "sink"}); !slices.Equal(log, want) {
+		t.Errorf("closed %v, want %v", log, want)
+	}
+}
+
+type panicCloser struct{}
+
+func (panicCloser) Process(r fileproc.Record, emit func(fileproc.Record) error) error { return emit(r) }
+func (panicCloser) Close() error                                                      { panic("close") }
+
+func TestKeepGoing(t *testing.T) {
+	paths := writeFiles(t, "a1\na2\n", "b1\nbad\nb3\n", "c1\n")
+	missing := filepath.Join(filepath.Dir(paths[0]), "missing.txt")
+	paths = slices.Insert(paths, 1, missing)
+	errBad := errors.New("bad line")
+	stage := fileproc.Map(func(r fileproc.Record) (fileproc.Record, error) {
+		if r.Text == "bad" {
+			return r, errBad
+		}
+		return r, nil
+	})
+
+	s := &sink{}
+	p := fileproc.Pipeline{Stages: []fileproc.Stage{stage}, Sink: s, KeepGoing: true}
+	res, err := p.Run(context.Background(), paths...)
+	if !errors.Is(err, fs.ErrNotExist) || !errors.Is(err, errBad) {
+		t.Errorf("Run = %v, want both the missing file and the bad line", err)
+	}
+	if !strings.Contains(err.Error(), paths[2]+": record 2: bad line") {
+		t.Errorf("Run = %v, want the failing file and record named", err)
+	}
+	if want := []string{"a1", "a2", "b1", "c1"}; !slices.Equal(s.texts, want) {
+		t.Errorf("records = %q, want %q", s.texts, want)
+	}
+	var failed []string
+	for _, f := range res.Files {
+		if f.Err != nil {
+			failed = append(failed, f.Path)
+		}
+	}
+	if len(res.Files) != 4 || !slices.Equal(failed, paths[1:3]) {
+		t.Errorf("Result = %+v, want all four files and the middle two failed", res)
+	}
+
+	// Without KeepGoing the first failure stops the run.
+	s = &sink{}
+	p = fileproc.Pipeline{Stages: []fileproc.Stage{stage}, Sink: s}
+	res, err = p.Run(context.Background(), paths...)
+	if !errors.Is(err, fs.ErrNotExist) || len(res.Files) != 2 || len(s.texts) != 2 {
+		t.Errorf("Run without KeepGoing = %+v, %v, want it to stop at the missing file", res, err)
+	}
+}
+
+func TestToFile(t *testing.T) {
+	paths := writeFiles(t, "one\ntwo\nthree\n")
+	dir := t.TempDir()
+	stopAt := func(text string) fileproc.Stage {
+		return fileproc.Map(func(r fileproc.Record) (fileproc.Record, error) {
+			if r.Text == text {
+				return r, errors.New("stop")
+			}
+			return r, nil
+		})
+	}
+	tests := []struct {
+		name    string
+		stages  []fileproc.Stage
+		want    string
+		wantErr bool
+	}{
+		{"success", nil, "one\ntwo\nthree\n", false},
+		{"failed pipeline keeps the partial result", []fileproc.Stage{stopAt("three")}, "one\ntwo\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".out")
+			sink, err := fileproc.ToFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := fileproc.Pipeline{Stages: tt.stages, Sink: sink}
+			if _, err := p.Run(context.Background(), paths...); (err != nil) != tt.wantErr {
+				t.Fatalf("Run = %v, want an error: %v", err, tt.wantErr)
+			}
+			data, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("%s = %q, want %q", out, data, tt.want)
+			}
+		})
+	}
+
+	// A path that can't be replaced fails the rename: path is left as it
+	// was and the temporary file is removed.
+	out := filepath.Join(dir, "taken")
+	if err := os.MkdirAll(filepath.Join(out, "child"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sink, err := fileproc.ToFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&fileproc.Pipeline{Sink: sink}).Run(context.Background(), paths...); err == nil {
+		t.Error("Run succeeded writing over a directory")
+	}
+	if info, err := os.Stat(out); err != nil || !info.IsDir() {
+		t.Errorf("%s was replaced", out)
+	}
+	if tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmps) != 0 {
+		t.Errorf("temporary files left behind: %q", tmps)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	paths := writeFiles(t, "one\ntwo\nthree\n", "four\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var log closeLog
+	s := &sink{log: &log}
+	p := fileproc.Pipeline{
+		Stages: []fileproc.Stage{fileproc.Map(func(r fileproc.Record) (fileproc.Record, error) {
+			if r.Text == "two" {
+				cancel()
+			}
+			return r, nil
+		})},
+		Sink: s,
+	}
+	res, err := p.Run(ctx, paths...)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+	// The record being processed when the context was cancelled still
+	// gets through; nothing after it is read.
+	if len(res.Files) != 1 || res.Files[0].Read != 2 || !slices.Equal(s.texts, []string{"one", "two"}) {
+		t.Errorf("Result = %+v, sink got %q", res, s.texts)
+	}
+	if !slices.Equal(log, closeLog{"sink"}) {
+		t.Errorf("closed %v, want the sink closed", log)
+	}
+
+	// An already cancelled context reads nothing, but still closes.
+	log = nil
+	res, err = p.Run(ctx, paths...)
+	if !errors.Is(err, context.Canceled) || len(res.Files) != 0 || !slices.Equal(log, closeLog{"sink"}) {
+		t.Errorf("Run with a done context = %+v, %v; closed %v", res, err, log)
+	}
+}
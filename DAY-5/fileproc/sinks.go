@@ -0,0 +1,89 @@
+// fileproc/sinks.go
+package fileproc
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type writerSink struct {
+	w *bufio.Writer
+}
+
+// ToWriter writes each record's text and a newline to w. Closing the sink
+// flushes it but does not close w.
+func ToWriter(w io.Writer) Sink {
+	return &writerSink{w: bufio.NewWriter(w)}
+}
+
+func (s *writerSink) Write(r Record) error {
+	if _, err := s.w.WriteString(r.Text); err != nil {
+		return err
+	}
+	return s.w.WriteByte('\n')
+}
+
+func (s *writerSink) Close() error {
+	return s.w.Flush()
+}
+
+type fileSink struct {
+	writerSink
+	f    *os.File
+	path string
+	err  error // First write error; the output is then discarded.
+}
+
+// ToFile writes the records to a file at path, one per line. The data goes
+// to a temporary file next to it that is renamed to path when the sink is
+// closed, so a crash never leaves a half-written path behind. If the
+// pipeline fails, the records that got through are still kept: they are
+// the partial result. If writing or renaming the file failed, Close
+// removes the temporary file and path is left untouched.
+func ToFile(path string) (Sink, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{writerSink: writerSink{w: bufio.NewWriter(f)}, f: f, path: path}, nil
+}
+
+func (s *fileSink) Write(r Record) error {
+	if err := s.writerSink.Write(r); err != nil {
+		s.err = err
+		return err
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	err := s.err
+	if err == nil {
+		err = s.w.Flush()
+	}
+	if err == nil {
+		err = s.f.Sync()
+	}
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(s.f.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(s.f.Name())
+	}
+	return err
+}
+
+// Collect appends every record to *dst, for tests and small inputs.
+func Collect(dst *[]Record) Sink {
+	return collectSink{dst}
+}
+
+type collectSink struct{ dst *[]Record }
+
+func (s collectSink) Write(r Record) error { *s.dst = append(*s.dst, r); return nil }
+func (s collectSink) Close() error         { return nil }
@@ -0,0 +1,69 @@
+// fileproc/stages.go
+package fileproc
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// Filter passes on only the records keep returns true for.
+func Filter(keep func(Record) bool) Stage {
+	return StageFunc(func(r Record, emit func(Record) error) error {
+		if keep(r) {
+			return emit(r)
+		}
+		return nil
+	})
+}
+
+// Transform replaces the text of every record with fn(text).
+func Transform(fn func(string) string) Stage {
+	return StageFunc(func(r Record, emit func(Record) error) error {
+		r.Text = fn(r.Text)
+		return emit(r)
+	})
+}
+
+// Map is Transform for functions that can fail or need the whole record.
+// An error stops the file.
+func Map(fn func(Record) (Record, error)) Stage {
+	return StageFunc(func(r Record, emit func(Record) error) error {
+		r, err := fn(r)
+		if err != nil {
+			return err
+		}
+		return emit(r)
+	})
+}
+
+// SplitOn turns each record into one record per piece of its text between
+// sep, e.g. the fields of a CSV line. Empty pieces are dropped.
+func SplitOn(sep string) Stage {
+	return StageFunc(func(r Record, emit func(Record) error) error {
+		for piece := range strings.SplitSeq(r.Text, sep) {
+			if piece == "" {
+				continue
+			}
+			r.Text = piece
+			if err := emit(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delimited is a bufio.SplitFunc for Pipeline.Split that cuts the input at
+// every sep byte, e.g. '\x00' for the output of find -print0.
+func Delimited(sep byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
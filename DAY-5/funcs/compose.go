@@ -0,0 +1,63 @@
+// funcs/compose.go
+package funcs
+
+// Pipe returns a function that applies f, then g: Pipe(f, g)(x) is
+// g(f(x)). It reads in the order the steps happen.
+func Pipe[A, B, C any](f func(A) B, g func(B) C) func(A) C {
+	return func(a A) C { return g(f(a)) }
+}
+
+// Pipe3 is Pipe with three steps.
+func Pipe3[A, B, C, D any](f func(A) B, g func(B) C, h func(C) D) func(A) D {
+	return func(a A) D { return h(g(f(a))) }
+}
+
+// Compose is Pipe written the mathematical way round: Compose(g, f)(x) is
+// g(f(x)).
+func Compose[A, B, C any](g func(B) C, f func(A) B) func(A) C {
+	return Pipe(f, g)
+}
+
+// Chain pipes any number of functions of the same type, first to last.
+// With none it returns the identity function.
+func Chain[T any](fs ...func(T) T) func(T) T {
+	return func(v T) T {
+		for _, f := range fs {
+			v = f(v)
+		}
+		return v
+	}
+}
+
+// Curry turns a two-argument function into a chain of one-argument ones:
+// Curry(f)(a)(b) is f(a, b).
+func Curry[A, B, C any](f func(A, B) C) func(A) func(B) C {
+	return func(a A) func(B) C {
+		return func(b B) C { return f(a, b) }
+	}
+}
+
+// Curry3 is Curry for three arguments.
+func Curry3[A, B, C, D any](f func(A, B, C) D) func(A) func(B) func(C) D {
+	return func(a A) func(B) func(C) D {
+		return func(b B) func(C) D {
+			return func(c C) D { return f(a, b, c) }
+		}
+	}
+}
+
+// Uncurry undoes Curry.
+func Uncurry[A, B, C any](f func(A) func(B) C) func(A, B) C {
+	return func(a A, b B) C { return f(a)(b) }
+}
+
+// Partial fixes the first argument of f: Partial(f, a)(b) is f(a, b).
+func Partial[A, B, C any](f func(A, B) C, a A) func(B) C {
+	return func(b B) C { return f(a, b) }
+}
+
+// PartialRight fixes the last argument of f: PartialRight(f, b)(a) is
+// f(a, b).
+func PartialRight[A, B, C any](f func(A, B) C, b B) func(A) C {
+	return func(a A) C { return f(a, b) }
+}
@@ -0,0 +1,124 @@
+// funcs/memo.go
+package funcs
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoOptions bound a Memo. The zero value caches every result forever.
+type MemoOptions struct {
+	// MaxEntries is how many results to keep. When it is reached, the
+	// least recently used one is dropped. Zero means no limit.
+	MaxEntries int
+
+	// TTL is how long a result stays valid. Zero means forever.
+	TTL time.Duration
+
+	// Clock is used for TTL. It defaults to Real.
+	Clock Clock
+}
+
+// Memo caches the results of a function by argument. It is safe for
+// concurrent use; two goroutines asking for the same missing key at the
+// same time may both call the function.
+type Memo[K comparable, V any] struct {
+	fn   func(K) (V, error)
+	opts MemoOptions
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List // Most recently used at the front.
+}
+
+type memoEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time // Zero without a TTL.
+}
+
+// Memoize returns a Memo for fn, which must always return the same result
+// for the same argument.
+func Memoize[K comparable, V any](fn func(K) V, opts MemoOptions) *Memo[K, V] {
+	return MemoizeErr(func(k K) (V, error) { return fn(k), nil }, opts)
+}
+
+// MemoizeErr is Memoize for functions that can fail. Errors are returned
+// but not cached, so the next call for that key tries again.
+func MemoizeErr[K comparable, V any](fn func(K) (V, error), opts MemoOptions) *Memo[K, V] {
+	if opts.Clock == nil {
+		opts.Clock = Real
+	}
+	return &Memo[K, V]{fn: fn, opts: opts, entries: make(map[K]*list.Element), order: list.New()}
+}
+
+// Get returns the cached result for k, calling the function on a miss. It
+// drops the error of a function made with Memoize, which has none.
+func (m *Memo[K, V]) Get(k K) V {
+	v, _ := m.Lookup(k)
+	return v
+}
+
+// Lookup is Get for a function made with MemoizeErr.
+func (m *Memo[K, V]) Lookup(k K) (V, error) {
+	m.mu.Lock()
+	if el, ok := m.entries[k]; ok {
+		e := el.Value.(*memoEntry[K, V])
+		if e.expires.IsZero() || m.opts.Clock.Now().Before(e.expires) {
+			m.order.MoveToFront(el)
+			m.mu.Unlock()
+			return e.value, nil
+		}
+		m.remove(el)
+	}
+	m.mu.Unlock()
+
+	v, err := m.fn(k) // Not under the lock, so slow calls don't block hits.
+	if err != nil {
+		return v, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[k]; ok {
+		m.remove(el) // Another goroutine filled it meanwhile; keep the newer result.
+	}
+	e := &memoEntry[K, V]{key: k, value: v}
+	if m.opts.TTL > 0 {
+		e.expires = m.opts.Clock.Now().Add(m.opts.TTL)
+	}
+	m.entries[k] = m.order.PushFront(e)
+	if m.opts.MaxEntries > 0 && m.order.Len() > m.opts.MaxEntries {
+		m.remove(m.order.Back())
+	}
+	return v, nil
+}
+
+// Func returns Get as a plain function, a drop-in replacement for the
+// function that was memoized.
+func (m *Memo[K, V]) Func() func(K) V {
+	return m.Get
+}
+
+// Forget drops the cached result for k.
+func (m *Memo[K, V]) Forget(k K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[k]; ok {
+		m.remove(el)
+	}
+}
+
+// Len returns how many results are cached, expired ones included until
+// they are next looked up.
+func (m *Memo[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *Memo[K, V]) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*memoEntry[K, V]).key)
+}
@@ -0,0 +1,110 @@
+// funcs/memo_test.go
+package funcs_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"functions/funcs"
+)
+
+// counter is a function that counts how often it runs per argument.
+type counter map[int]int
+
+func (c counter) square(n int) int {
+	c[n]++
+	return n * n
+}
+
+func TestMemoCaches(t *testing.T) {
+	c := counter{}
+	m := funcs.Memoize(c.square, funcs.MemoOptions{})
+	sq := m.Func()
+
+	for range 3 {
+		if got := sq(4); got != 16 {
+			t.Fatalf("sq(4) = %d, want 16", got)
+		}
+	}
+	if c[4] != 1 {
+		t.Errorf("square ran %d times for 4, want 1", c[4])
+	}
+	m.Forget(4)
+	sq(4)
+	if c[4] != 2 {
+		t.Errorf("square ran %d times after Forget, want 2", c[4])
+	}
+}
+
+func TestMemoTTL(t *testing.T) {
+	clock := funcs.NewFakeClock(epoch)
+	c := counter{}
+	m := funcs.Memoize(c.square, funcs.MemoOptions{TTL: time.Minute, Clock: clock})
+
+	m.Get(3)
+	clock.Advance(59 * time.Second)
+	m.Get(3)
+	if c[3] != 1 {
+		t.Fatalf("square ran %d times within the TTL, want 1", c[3])
+	}
+
+	clock.Advance(time.Second)
+	m.Get(3)
+	if c[3] != 2 {
+		t.Fatalf("square ran %d times after the TTL, want 2", c[3])
+	}
+
+	// The refreshed entry gets a new TTL from the time it was stored.
+	clock.Advance(59 * time.Second)
+	m.Get(3)
+	if c[3] != 2 {
+		t.Errorf("square ran %d times within the renewed TTL, want 2", c[3])
+	}
+}
+
+func TestMemoLRU(t *testing.T) {
+	c := counter{}
+	m := funcs.Memoize(c.square, funcs.MemoOptions{MaxEntries: 2})
+
+	m.Get(1)
+	m.Get(2)
+	m.Get(1) // 1 is now the most recently used, so 2 goes next.
+	m.Get(3)
+	if m.Len() != 2 {
+		t.Errorf("Len = %d, want 2", m.Len())
+	}
+
+	m.Get(1)
+	if c[1] != 1 {
+		t.Errorf("1 was evicted although it was used recently")
+	}
+	m.Get(2)
+	if c[2] != 2 {
+		t.Errorf("2 was not evicted although it was the least recently used")
+	}
+}
+
+func TestMemoErrNotCached(t *testing.T) {
+	fail := true
+	calls := 0
+	m := funcs.MemoizeErr(func(k string) (int, error) {
+		calls++
+		if fail {
+			return 0, errors.New("offline")
+		}
+		return len(k), nil
+	}, funcs.MemoOptions{})
+
+	if _, err := m.Lookup("abc"); err == nil {
+		t.Fatal("Lookup returned no error")
+	}
+	fail = false
+	if v, err := m.Lookup("abc"); err != nil || v != 3 {
+		t.Fatalf("Lookup = %d, %v; want 3", v, err)
+	}
+	m.Lookup("abc")
+	if calls != 2 {
+		t.Errorf("function ran %d times, want 2: once failing, once succeeding", calls)
+	}
+}
@@ -0,0 +1,48 @@
+// funcs/once.go
+package funcs
+
+import "sync"
+
+// Once runs a function that produces a value until it first succeeds,
+// then returns that value forever. Unlike sync.OnceValues, which also
+// caches a failure, an error is returned to the caller and the next Do
+// tries again, which suits things like opening a connection at first use.
+//
+// The zero Once is ready to use. A Once must not be copied after first use.
+type Once[T any] struct {
+	mu    sync.Mutex
+	done  bool
+	value T
+}
+
+// Do returns the cached value, or calls fn if no call has succeeded yet.
+// Concurrent callers wait for the call in progress rather than making
+// their own.
+func (o *Once[T]) Do(fn func() (T, error)) (T, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done {
+		return o.value, nil
+	}
+	v, err := fn()
+	if err != nil {
+		return v, err
+	}
+	o.value, o.done = v, true
+	return v, nil
+}
+
+// Done reports whether a call has succeeded.
+func (o *Once[T]) Done() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.done
+}
+
+// Reset forgets the cached value, so the next Do calls its function again.
+func (o *Once[T]) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var zero T
+	o.value, o.done = zero, false
+}
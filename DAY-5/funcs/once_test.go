@@ -0,0 +1,72 @@
+// funcs/once_test.go
+package funcs_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"functions/funcs"
+)
+
+func TestOnceRetriesAfterError(t *testing.T) {
+	var o funcs.Once[string]
+	attempts := 0
+	connect := func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("connection refused")
+		}
+		return "conn", nil
+	}
+
+	for range 2 {
+		if _, err := o.Do(connect); err == nil {
+			t.Fatal("Do succeeded before connect did")
+		}
+	}
+	if o.Done() {
+		t.Fatal("Done after only failures")
+	}
+	for range 2 {
+		if v, err := o.Do(connect); err != nil || v != "conn" {
+			t.Fatalf("Do = %q, %v; want conn", v, err)
+		}
+	}
+	if attempts != 3 {
+		t.Errorf("connect ran %d times, want 3", attempts)
+	}
+
+	o.Reset()
+	if o.Done() {
+		t.Fatal("Done after Reset")
+	}
+	o.Do(connect)
+	if attempts != 4 {
+		t.Errorf("connect ran %d times after Reset, want 4", attempts)
+	}
+}
+
+func TestOnceConcurrent(t *testing.T) {
+	var o funcs.Once[int]
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := o.Do(func() (int, error) {
+				calls.Add(1)
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("Do = %d, %v; want 42", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("function ran %d times, want 1", n)
+	}
+}
@@ -0,0 +1,164 @@
+// funcs/timing.go
+package funcs
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer delays a function until calls to it stop for a while, such as
+// saving a document once the user stops typing. Each Call restarts the
+// wait; when it runs out, the function runs once with the last argument.
+type Debouncer[T any] struct {
+	clock Clock
+	wait  time.Duration
+	fn    func(T)
+
+	mu      sync.Mutex
+	timer   Timer
+	arg     T
+	pending bool
+	gen     int // Bumped on every change, so a stale timer does nothing.
+}
+
+// Debounce returns a Debouncer that runs fn once calls have stopped for
+// wait. A nil clock means Real. fn runs on the clock's timer goroutine.
+func Debounce[T any](clock Clock, wait time.Duration, fn func(T)) *Debouncer[T] {
+	if clock == nil {
+		clock = Real
+	}
+	return &Debouncer[T]{clock: clock, wait: wait, fn: fn}
+}
+
+// Call schedules fn(arg), replacing any call still waiting.
+func (d *Debouncer[T]) Call(arg T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopLocked()
+	d.arg, d.pending = arg, true
+	gen := d.gen
+	d.timer = d.clock.AfterFunc(d.wait, func() { d.fire(gen) })
+}
+
+// Flush runs the waiting call now, if there is one.
+func (d *Debouncer[T]) Flush() {
+	d.mu.Lock()
+	d.fireLocked()
+}
+
+// Cancel drops the waiting call, if there is one.
+func (d *Debouncer[T]) Cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopLocked()
+	d.pending = false
+}
+
+func (d *Debouncer[T]) fire(gen int) {
+	d.mu.Lock()
+	if gen != d.gen {
+		d.mu.Unlock()
+		return
+	}
+	d.fireLocked()
+}
+
+// fireLocked runs the waiting call and unlocks d.mu before calling fn, so
+// fn may call the Debouncer again.
+func (d *Debouncer[T]) fireLocked() {
+	d.stopLocked()
+	arg, pending := d.arg, d.pending
+	var zero T
+	d.arg, d.pending = zero, false
+	d.mu.Unlock()
+	if pending {
+		d.fn(arg)
+	}
+}
+
+func (d *Debouncer[T]) stopLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.gen++
+}
+
+// Throttler limits a function to one run per interval, such as handling a
+// scroll or resize event. The first Call runs at once. Calls during the
+// interval that follows are collapsed into one run, with the last
+// argument, at the end of it, which starts the next interval.
+type Throttler[T any] struct {
+	clock    Clock
+	interval time.Duration
+	fn       func(T)
+
+	mu      sync.Mutex
+	timer   Timer // Non-nil while an interval is running.
+	arg     T
+	pending bool
+	gen     int
+}
+
+// Throttle returns a Throttler that runs fn at most once per interval. A
+// nil clock means Real.
+func Throttle[T any](clock Clock, interval time.Duration, fn func(T)) *Throttler[T] {
+	if clock == nil {
+		clock = Real
+	}
+	return &Throttler[T]{clock: clock, interval: interval, fn: fn}
+}
+
+// Call runs fn(arg) now if no interval is running, and otherwise keeps arg
+// for the run at the end of the interval.
+func (t *Throttler[T]) Call(arg T) {
+	t.mu.Lock()
+	if t.timer != nil {
+		t.arg, t.pending = arg, true
+		t.mu.Unlock()
+		return
+	}
+	t.startLocked()
+	t.mu.Unlock()
+	t.fn(arg)
+}
+
+// Stop ends the current interval and drops the call waiting for its end.
+func (t *Throttler[T]) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	var zero T
+	t.arg, t.pending = zero, false
+	t.gen++
+}
+
+func (t *Throttler[T]) startLocked() {
+	t.gen++
+	gen := t.gen
+	t.timer = t.clock.AfterFunc(t.interval, func() { t.tick(gen) })
+}
+
+// tick ends an interval. If calls came in during it, the last one runs
+// and starts a new interval.
+func (t *Throttler[T]) tick(gen int) {
+	t.mu.Lock()
+	if gen != t.gen {
+		t.mu.Unlock()
+		return
+	}
+	if !t.pending {
+		t.timer = nil
+		t.mu.Unlock()
+		return
+	}
+	arg := t.arg
+	var zero T
+	t.arg, t.pending = zero, false
+	t.startLocked()
+	t.mu.Unlock()
+	t.fn(arg)
+}
@@ -0,0 +1,138 @@
+// funcs/timing_test.go
+package funcs_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"functions/funcs"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// calls records the arguments fn was called with.
+type calls[T comparable] struct {
+	got []T
+}
+
+func (c *calls[T]) fn(v T) { c.got = append(c.got, v) }
+
+func (c *calls[T]) want(t *testing.T, step string, want ...T) {
+	t.Helper()
+	if !slices.Equal(c.got, want) {
+		t.Fatalf("%s: calls = %v, want %v", step, c.got, want)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	clock := funcs.NewFakeClock(epoch)
+	var c calls[string]
+	d := funcs.Debounce(clock, 100*time.Millisecond, c.fn)
+
+	d.Call("h")
+	clock.Advance(60 * time.Millisecond)
+	d.Call("he")
+	clock.Advance(60 * time.Millisecond) // 120ms since "h", but only 60 since "he".
+	d.Call("hello")
+	clock.Advance(99 * time.Millisecond)
+	c.want(t, "while typing")
+
+	clock.Advance(time.Millisecond)
+	c.want(t, "after the pause", "hello")
+	if n := clock.Pending(); n != 0 {
+		t.Errorf("%d timers pending after firing, want 0", n)
+	}
+
+	clock.Advance(time.Second)
+	c.want(t, "long after", "hello")
+}
+
+func TestDebounceFlushAndCancel(t *testing.T) {
+	clock := funcs.NewFakeClock(epoch)
+	var c calls[int]
+	d := funcs.Debounce(clock, time.Second, c.fn)
+
+	d.Call(1)
+	d.Flush()
+	c.want(t, "Flush", 1)
+	clock.Advance(2 * time.Second)
+	c.want(t, "after Flush", 1) // The flushed call doesn't run twice.
+
+	d.Call(2)
+	d.Cancel()
+	clock.Advance(2 * time.Second)
+	c.want(t, "after Cancel", 1)
+
+	d.Flush() // Nothing waiting.
+	c.want(t, "empty Flush", 1)
+}
+
+func TestThrottle(t *testing.T) {
+	clock := funcs.NewFakeClock(epoch)
+	var c calls[int]
+	th := funcs.Throttle(clock, 100*time.Millisecond, c.fn)
+
+	th.Call(1)
+	c.want(t, "first call", 1)
+
+	th.Call(2)
+	clock.Advance(50 * time.Millisecond)
+	th.Call(3)
+	c.want(t, "during the interval", 1)
+
+	clock.Advance(50 * time.Millisecond)
+	c.want(t, "end of the interval", 1, 3) // Only the last argument.
+
+	// The trailing run started a new interval.
+	th.Call(4)
+	c.want(t, "second interval", 1, 3)
+	clock.Advance(100 * time.Millisecond)
+	c.want(t, "end of second interval", 1, 3, 4)
+
+	// A quiet interval ends the throttling, so the next call runs at once.
+	clock.Advance(100 * time.Millisecond)
+	th.Call(5)
+	c.want(t, "after a quiet interval", 1, 3, 4, 5)
+}
+
+func TestThrottleStop(t *testing.T) {
+	clock := funcs.NewFakeClock(epoch)
+	var c calls[int]
+	th := funcs.Throttle(clock, time.Second, c.fn)
+
+	th.Call(1)
+	th.Call(2)
+	th.Stop()
+	clock.Advance(2 * time.Second)
+	c.want(t, "after Stop", 1)
+
+	th.Call(3)
+	c.want(t, "call after Stop", 1, 3)
+}
+
+func TestFakeClockOrder(t *testing.T) {
+	clock := funcs.NewFakeClock(epoch)
+	var order []string
+	clock.AfterFunc(2*time.Second, func() { order = append(order, "b") })
+	clock.AfterFunc(time.Second, func() {
+		order = append(order, "a")
+		// Timers set by a callback fire within the same Advance.
+		clock.AfterFunc(500*time.Millisecond, func() { order = append(order, "a2") })
+	})
+	stopped := clock.AfterFunc(time.Second, func() { order = append(order, "never") })
+	if !stopped.Stop() {
+		t.Fatal("Stop on a pending timer returned false")
+	}
+
+	clock.Advance(3 * time.Second)
+	if want := []string{"a", "a2", "b"}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if got := clock.Now(); !got.Equal(epoch.Add(3 * time.Second)) {
+		t.Errorf("Now = %v, want %v", got, epoch.Add(3*time.Second))
+	}
+	if stopped.Stop() {
+		t.Error("second Stop returned true")
+	}
+}
@@ -0,0 +1,46 @@
+// geometry/circle.go
+package geometry
+
+import "math"
+
+// Circle is the set of points at most Radius away from Center.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// NewCircle returns a circle, or a *calculator.DomainError wrapping
+// calculator.ErrDomain if the radius is negative (ErrNaN or ErrInf if it
+// is not a finite number).
+func NewCircle(center Point, radius float64) (Circle, error) {
+	if err := checkPoints("NewCircle", center); err != nil {
+		return Circle{}, err
+	}
+	if err := checkLength("NewCircle", radius, false); err != nil {
+		return Circle{}, err
+	}
+	return Circle{Center: center, Radius: radius}, nil
+}
+
+// Area returns πr², using math.Pi.
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+// Perimeter returns the circumference, 2πr.
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+func (c Circle) BoundingBox() Box {
+	r := Point{c.Radius, c.Radius}
+	return Box{
+		Min: Point{c.Center.X - r.X, c.Center.Y - r.Y},
+		Max: Point{c.Center.X + r.X, c.Center.Y + r.Y},
+	}
+}
+
+// Contains reports whether p is inside the circle or on its edge.
+func (c Circle) Contains(p Point) bool {
+	return c.Center.Dist(p) <= c.Radius
+}
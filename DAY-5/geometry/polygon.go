@@ -0,0 +1,221 @@
+// geometry/polygon.go
+package geometry
+
+import (
+	"math"
+
+	"calculator/calculator"
+)
+
+// Polygon is a closed shape through Vertices in order; the last vertex
+// connects back to the first. The edges should not cross each other: for a
+// self-intersecting polygon, Area returns the difference between the areas
+// wound clockwise and counter-clockwise.
+type Polygon struct {
+	Vertices []Point
+}
+
+// NewPolygon returns a polygon, or a *calculator.DomainError if it has
+// fewer than three vertices, a coordinate that is not a finite number, or
+// no area at all (every vertex on one line).
+func NewPolygon(vertices ...Point) (Polygon, error) {
+	return newPolygon("NewPolygon", vertices)
+}
+
+func newPolygon(op string, vertices []Point) (Polygon, error) {
+	if err := checkPoints(op, vertices...); err != nil {
+		return Polygon{}, err
+	}
+	p := Polygon{Vertices: append([]Point(nil), vertices...)}
+	if len(vertices) < 3 || p.Area() == 0 {
+		return Polygon{}, &calculator.DomainError{Op: op, Operands: []any{vertices}, Err: calculator.ErrDomain}
+	}
+	return p, nil
+}
+
+// Area uses the shoelace formula: half the absolute sum of the cross
+// products of consecutive vertices.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.signedArea())
+}
+
+// signedArea is positive when the vertices run counter-clockwise.
+func (p Polygon) signedArea() float64 {
+	var sum float64
+	for i, a := range p.Vertices {
+		b := p.Vertices[(i+1)%len(p.Vertices)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum / 2
+}
+
+func (p Polygon) Perimeter() float64 {
+	var sum float64
+	for i, a := range p.Vertices {
+		sum += a.Dist(p.Vertices[(i+1)%len(p.Vertices)])
+	}
+	return sum
+}
+
+func (p Polygon) BoundingBox() Box {
+	return boxOf(p.Vertices)
+}
+
+// Contains reports whether pt is inside the polygon or on its boundary.
+func (p Polygon) Contains(pt Point) bool {
+	return PointInPolygon(pt, p.Vertices)
+}
+
+// PointInPolygon reports whether p is inside the polygon through vertices
+// or on its boundary. It casts a ray from p to the right and counts how many
+// edges it crosses: an odd count means inside.
+func PointInPolygon(p Point, vertices []Point) bool {
+	inside := false
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		if onSegment(p, a, b) {
+			return true
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) // Where the edge meets the ray's line.
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// onSegment reports whether p lies on the segment from a to b, allowing for
+// floating-point rounding.
+func onSegment(p, a, b Point) bool {
+	cross := (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+	scale := max(1, a.Dist(b))
+	if math.Abs(cross) > 1e-9*scale*scale {
+		return false
+	}
+	return p.X >= min(a.X, b.X)-1e-9 && p.X <= max(a.X, b.X)+1e-9 &&
+		p.Y >= min(a.Y, b.Y)-1e-9 && p.Y <= max(a.Y, b.Y)+1e-9
+}
+
+// Rectangle is an axis-aligned rectangle with its lower-left corner at
+// Origin.
+type Rectangle struct {
+	Origin        Point
+	Width, Height float64
+}
+
+// NewRectangle returns a rectangle, or a *calculator.DomainError if the
+// width or height is negative or not a finite number.
+func NewRectangle(origin Point, width, height float64) (Rectangle, error) {
+	if err := checkPoints("NewRectangle", origin); err != nil {
+		return Rectangle{}, err
+	}
+	for _, x := range []float64{width, height} {
+		if err := checkLength("NewRectangle", x, false); err != nil {
+			return Rectangle{}, err
+		}
+	}
+	return Rectangle{Origin: origin, Width: width, Height: height}, nil
+}
+
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+
+func (r Rectangle) BoundingBox() Box {
+	return Box{Min: r.Origin, Max: Point{r.Origin.X + r.Width, r.Origin.Y + r.Height}}
+}
+
+// Contains reports whether p is inside the rectangle or on its edge.
+func (r Rectangle) Contains(p Point) bool {
+	return r.BoundingBox().Contains(p)
+}
+
+// Polygon returns the rectangle's corners, counter-clockwise.
+func (r Rectangle) Polygon() Polygon {
+	b := r.BoundingBox()
+	return Polygon{Vertices: []Point{b.Min, {b.Max.X, b.Min.Y}, b.Max, {b.Min.X, b.Max.Y}}}
+}
+
+// Triangle is the triangle with corners A, B and C.
+type Triangle struct {
+	A, B, C Point
+}
+
+// NewTriangle returns a triangle, or a *calculator.DomainError if its
+// corners are on one line or not finite.
+func NewTriangle(a, b, c Point) (Triangle, error) {
+	if _, err := newPolygon("NewTriangle", []Point{a, b, c}); err != nil {
+		return Triangle{}, err
+	}
+	return Triangle{A: a, B: b, C: c}, nil
+}
+
+func (t Triangle) Area() float64         { return t.Polygon().Area() }
+func (t Triangle) Perimeter() float64    { return t.Polygon().Perimeter() }
+func (t Triangle) BoundingBox() Box      { return t.Polygon().BoundingBox() }
+func (t Triangle) Contains(p Point) bool { return t.Polygon().Contains(p) }
+func (t Triangle) Polygon() Polygon      { return Polygon{Vertices: []Point{t.A, t.B, t.C}} }
+
+// RegularPolygon has Sides equal sides with its corners on a circle of
+// radius Radius around Center. Rotation turns it counter-clockwise, in
+// radians; at 0 the first corner points right.
+//
+// A RegularPolygon with fewer than three sides, such as the zero value, is
+// empty: it has no area, perimeter or corners and contains no point.
+type RegularPolygon struct {
+	Center   Point
+	Sides    int
+	Radius   float64
+	Rotation float64
+}
+
+// NewRegularPolygon returns a regular polygon, or a
+// *calculator.DomainError if it has fewer than three sides or a radius
+// that is not positive.
+func NewRegularPolygon(center Point, sides int, radius float64) (RegularPolygon, error) {
+	if err := checkPoints("NewRegularPolygon", center); err != nil {
+		return RegularPolygon{}, err
+	}
+	if sides < 3 {
+		return RegularPolygon{}, &calculator.DomainError{Op: "NewRegularPolygon", Operands: []any{sides, radius}, Err: calculator.ErrDomain}
+	}
+	if err := checkLength("NewRegularPolygon", radius, true); err != nil {
+		return RegularPolygon{}, err
+	}
+	return RegularPolygon{Center: center, Sides: sides, Radius: radius}, nil
+}
+
+// Area returns ½·n·r²·sin(2π/n).
+func (r RegularPolygon) Area() float64 {
+	if r.Sides < 3 {
+		return 0
+	}
+	n := float64(r.Sides)
+	return n * r.Radius * r.Radius * math.Sin(2*math.Pi/n) / 2
+}
+
+// Perimeter returns n times the side length 2r·sin(π/n).
+func (r RegularPolygon) Perimeter() float64 {
+	if r.Sides < 3 {
+		return 0
+	}
+	n := float64(r.Sides)
+	return n * 2 * r.Radius * math.Sin(math.Pi/n)
+}
+
+func (r RegularPolygon) BoundingBox() Box      { return r.Polygon().BoundingBox() }
+func (r RegularPolygon) Contains(p Point) bool { return r.Polygon().Contains(p) }
+
+// Polygon returns the corners, counter-clockwise.
+func (r RegularPolygon) Polygon() Polygon {
+	if r.Sides < 3 {
+		return Polygon{}
+	}
+	vs := make([]Point, r.Sides)
+	for i := range vs {
+		angle := r.Rotation + 2*math.Pi*float64(i)/float64(r.Sides)
+		vs[i] = Point{r.Center.X + r.Radius*math.Cos(angle), r.Center.Y + r.Radius*math.Sin(angle)}
+	}
+	return Polygon{Vertices: vs}
+}
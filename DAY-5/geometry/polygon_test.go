@@ -0,0 +1,129 @@
+// geometry/polygon_test.go
+package geometry_test
+
+import (
+	"math"
+	"testing"
+
+	"functions/geometry"
+)
+
+const epsilon = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= epsilon*max(1, math.Abs(b))
+}
+
+type pt = geometry.Point
+
+// lShape is concave: a 2×2 square without its upper-right quarter.
+var lShape = []pt{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+
+func TestPolygonArea(t *testing.T) {
+	tests := []struct {
+		name        string
+		vertices    []pt
+		area, perim float64
+	}{
+		{"rectangle", []pt{{0, 0}, {4, 0}, {4, 3}, {0, 3}}, 12, 14},
+		{"clockwise rectangle", []pt{{0, 3}, {4, 3}, {4, 0}, {0, 0}}, 12, 14},
+		{"right triangle", []pt{{0, 0}, {4, 0}, {0, 3}}, 6, 12},
+		{"L shape", lShape, 3, 8},
+		{"off the origin", []pt{{-10, -10}, {-8, -10}, {-8, -7}}, 3, 5 + math.Sqrt(13)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := geometry.NewPolygon(tt.vertices...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Area(); !near(got, tt.area) {
+				t.Errorf("Area = %g, want %g", got, tt.area)
+			}
+			if got := p.Perimeter(); !near(got, tt.perim) {
+				t.Errorf("Perimeter = %g, want %g", got, tt.perim)
+			}
+		})
+	}
+}
+
+func TestPointInPolygon(t *testing.T) {
+	tests := []struct {
+		name string
+		p    pt
+		want bool
+	}{
+		{"inside the bottom", pt{1.5, 0.5}, true},
+		{"inside the left", pt{0.5, 1.5}, true},
+		{"in the missing quarter", pt{1.5, 1.5}, false},
+		{"outside", pt{3, 0.5}, false},
+		{"below", pt{1, -0.1}, false},
+		{"vertex", pt{2, 0}, true},
+		{"inner corner", pt{1, 1}, true},
+		{"on an edge", pt{2, 0.5}, true},
+		{"on an inner edge", pt{1, 1.5}, true},
+		{"just off an edge", pt{2 + 1e-6, 0.5}, false},
+		{"on an edge's line past its end", pt{3, 0}, false},
+		{"ray through a vertex, outside", pt{-1, 1}, false},
+		{"ray through a vertex, inside", pt{0.5, 1}, true},
+		{"ray along an edge", pt{-1, 0}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := geometry.PointInPolygon(tt.p, lShape); got != tt.want {
+				t.Errorf("PointInPolygon(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegularPolygon(t *testing.T) {
+	tests := []struct {
+		sides       int
+		radius      float64
+		area, perim float64
+	}{
+		{3, 2, 3 * math.Sqrt(3), 6 * math.Sqrt(3)},
+		{4, math.Sqrt2, 4, 8},
+		{6, 1, 3 * math.Sqrt(3) / 2, 6},
+	}
+	for _, tt := range tests {
+		r, err := geometry.NewRegularPolygon(pt{5, -3}, tt.sides, tt.radius)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Area(); !near(got, tt.area) {
+			t.Errorf("%d sides: Area = %g, want %g", tt.sides, got, tt.area)
+		}
+		if got := r.Perimeter(); !near(got, tt.perim) {
+			t.Errorf("%d sides: Perimeter = %g, want %g", tt.sides, got, tt.perim)
+		}
+		// The formulas agree with the corners, however the polygon is turned.
+		r.Rotation = 0.3
+		if p := r.Polygon(); !near(p.Area(), tt.area) || !near(p.Perimeter(), tt.perim) || len(p.Vertices) != tt.sides {
+			t.Errorf("%d sides: Polygon() = %v with area %g and perimeter %g", tt.sides, p.Vertices, p.Area(), p.Perimeter())
+		}
+		if !r.Contains(r.Center) {
+			t.Errorf("%d sides: does not contain its center", tt.sides)
+		}
+	}
+
+	circle, _ := geometry.NewRegularPolygon(pt{}, 10000, 1)
+	if got := circle.Area(); math.Abs(got-math.Pi) > 1e-6 {
+		t.Errorf("Area with 10000 sides = %g, want about π", got)
+	}
+}
+
+func TestRegularPolygonTooFewSides(t *testing.T) {
+	for _, r := range []geometry.RegularPolygon{{}, {Sides: 2, Radius: 1}, {Sides: -1, Radius: 1}} {
+		if a, p := r.Area(), r.Perimeter(); a != 0 || p != 0 {
+			t.Errorf("%+v: Area, Perimeter = %g, %g, want 0, 0", r, a, p)
+		}
+		if vs := r.Polygon().Vertices; len(vs) != 0 {
+			t.Errorf("%+v: Polygon() = %v, want no corners", r, vs)
+		}
+		if r.Contains(r.Center) || r.BoundingBox() != (geometry.Box{}) {
+			t.Errorf("%+v: not empty", r)
+		}
+	}
+}
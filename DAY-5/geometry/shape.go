@@ -0,0 +1,84 @@
+// geometry/shape.go
+package geometry // Circles, rectangles, triangles and polygons behind one Shape interface
+
+import (
+	"math"
+
+	"calculator/calculator"
+)
+
+// Point is a position in the plane.
+type Point struct {
+	X, Y float64
+}
+
+// Dist returns the distance between p and q.
+func (p Point) Dist(q Point) float64 {
+	return math.Hypot(q.X-p.X, q.Y-p.Y)
+}
+
+// Box is an axis-aligned rectangle given by its lower-left and upper-right
+// corners.
+type Box struct {
+	Min, Max Point
+}
+
+// Width returns the horizontal size of the box.
+func (b Box) Width() float64 { return b.Max.X - b.Min.X }
+
+// Height returns the vertical size of the box.
+func (b Box) Height() float64 { return b.Max.Y - b.Min.Y }
+
+// Contains reports whether p is inside the box or on its edge.
+func (b Box) Contains(p Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// Shape is a closed figure in the plane.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+	// BoundingBox is the smallest axis-aligned box containing the shape.
+	BoundingBox() Box
+}
+
+var (
+	_ Shape = Circle{}
+	_ Shape = Rectangle{}
+	_ Shape = Triangle{}
+	_ Shape = RegularPolygon{}
+	_ Shape = Polygon{}
+)
+
+// checkLength returns a *calculator.DomainError unless x is a finite
+// number that is at least 0, or above 0 if positive is set.
+func checkLength(op string, x float64, positive bool) error {
+	if err := calculator.CheckOperands(op, x); err != nil {
+		return err
+	}
+	if x < 0 || (positive && x == 0) {
+		return &calculator.DomainError{Op: op, Operands: []any{x}, Err: calculator.ErrDomain}
+	}
+	return nil
+}
+
+func checkPoints(op string, points ...Point) error {
+	for _, p := range points {
+		if err := calculator.CheckOperands(op, p.X, p.Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func boxOf(points []Point) Box {
+	if len(points) == 0 {
+		return Box{}
+	}
+	b := Box{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b.Min.X, b.Min.Y = min(b.Min.X, p.X), min(b.Min.Y, p.Y)
+		b.Max.X, b.Max.Y = max(b.Max.X, p.X), max(b.Max.Y, p.Y)
+	}
+	return b
+}
@@ -0,0 +1,107 @@
+// geometry/shape_test.go
+package geometry_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"calculator/calculator"
+	"functions/geometry"
+)
+
+func TestConstructors(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	tests := []struct {
+		name    string
+		make    func() error
+		wantErr error
+	}{
+		{"circle", func() error { _, err := geometry.NewCircle(pt{1, 1}, 2); return err }, nil},
+		{"point circle", func() error { _, err := geometry.NewCircle(pt{}, 0); return err }, nil},
+		{"negative radius", func() error { _, err := geometry.NewCircle(pt{}, -1); return err }, calculator.ErrDomain},
+		{"infinite radius", func() error { _, err := geometry.NewCircle(pt{}, inf); return err }, calculator.ErrInf},
+		{"NaN center", func() error { _, err := geometry.NewCircle(pt{nan, 0}, 1); return err }, calculator.ErrNaN},
+
+		{"rectangle", func() error { _, err := geometry.NewRectangle(pt{}, 2, 3); return err }, nil},
+		{"negative height", func() error { _, err := geometry.NewRectangle(pt{}, 2, -3); return err }, calculator.ErrDomain},
+		{"NaN width", func() error { _, err := geometry.NewRectangle(pt{}, nan, 3); return err }, calculator.ErrNaN},
+
+		{"triangle", func() error { _, err := geometry.NewTriangle(pt{0, 0}, pt{1, 0}, pt{0, 1}); return err }, nil},
+		{"flat triangle", func() error { _, err := geometry.NewTriangle(pt{0, 0}, pt{1, 1}, pt{2, 2}); return err }, calculator.ErrDomain},
+
+		{"polygon", func() error { _, err := geometry.NewPolygon(lShape...); return err }, nil},
+		{"two vertices", func() error { _, err := geometry.NewPolygon(pt{0, 0}, pt{1, 1}); return err }, calculator.ErrDomain},
+		{"flat polygon", func() error { _, err := geometry.NewPolygon(pt{0, 0}, pt{1, 0}, pt{2, 0}, pt{3, 0}); return err }, calculator.ErrDomain},
+		{"infinite vertex", func() error { _, err := geometry.NewPolygon(pt{0, 0}, pt{inf, 0}, pt{0, 1}); return err }, calculator.ErrInf},
+
+		{"regular polygon", func() error { _, err := geometry.NewRegularPolygon(pt{}, 5, 1); return err }, nil},
+		{"two sides", func() error { _, err := geometry.NewRegularPolygon(pt{}, 2, 1); return err }, calculator.ErrDomain},
+		{"zero radius", func() error { _, err := geometry.NewRegularPolygon(pt{}, 5, 0); return err }, calculator.ErrDomain},
+		{"NaN radius", func() error { _, err := geometry.NewRegularPolygon(pt{}, 5, nan); return err }, calculator.ErrNaN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.make()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			var de *calculator.DomainError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &de) {
+				t.Errorf("err = %v, want a *calculator.DomainError wrapping %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPolygonCopies(t *testing.T) {
+	vs := []pt{{0, 0}, {1, 0}, {0, 1}}
+	p, err := geometry.NewPolygon(vs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs[1] = pt{100, 0}
+	if p.Vertices[1] != (pt{1, 0}) {
+		t.Errorf("changing the caller's slice moved a vertex to %v", p.Vertices[1])
+	}
+}
+
+func TestShapes(t *testing.T) {
+	circle, _ := geometry.NewCircle(pt{1, 1}, 1)
+	rect, _ := geometry.NewRectangle(pt{-1, 0}, 2, 3)
+	tri, _ := geometry.NewTriangle(pt{0, 0}, pt{4, 0}, pt{0, 3})
+	tests := []struct {
+		name        string
+		shape       geometry.Shape
+		area, perim float64
+		box         geometry.Box
+	}{
+		{"circle", circle, math.Pi, 2 * math.Pi, geometry.Box{Min: pt{0, 0}, Max: pt{2, 2}}},
+		{"rectangle", rect, 6, 10, geometry.Box{Min: pt{-1, 0}, Max: pt{1, 3}}},
+		{"rectangle polygon", rect.Polygon(), 6, 10, geometry.Box{Min: pt{-1, 0}, Max: pt{1, 3}}},
+		{"triangle", tri, 6, 12, geometry.Box{Min: pt{0, 0}, Max: pt{4, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shape.Area(); !near(got, tt.area) {
+				t.Errorf("Area = %g, want %g", got, tt.area)
+			}
+			if got := tt.shape.Perimeter(); !near(got, tt.perim) {
+				t.Errorf("Perimeter = %g, want %g", got, tt.perim)
+			}
+			if got := tt.shape.BoundingBox(); got != tt.box {
+				t.Errorf("BoundingBox = %v, want %v", got, tt.box)
+			}
+		})
+	}
+
+	if !circle.Contains(pt{2, 1}) || circle.Contains(pt{2, 2}) {
+		t.Error("Circle.Contains is wrong on or just outside the edge")
+	}
+	if !tri.Contains(pt{2, 1.5}) || tri.Contains(pt{3, 3}) {
+		t.Error("Triangle.Contains is wrong on the hypotenuse or outside it")
+	}
+}
@@ -0,0 +1,7 @@
+module functions
+
+go 1.24.3
+
+require calculator v0.0.0
+
+replace calculator => ../DAY-6
@@ -1,8 +1,16 @@
 package main
 
 import (
-	"errors" // Import the errors package for creating custom errors
+	"context"
+	"errors" // Import the errors package to inspect the errors we get back
 	"fmt"
+	"os"
+	"strings"
+
+	"calculator/calculator" // Typed math errors, shared with the Day 6 calculator
+	"functions/fileproc"    // Streams files through filters and transformers
+	"functions/geometry"    // Circles, rectangles and other shapes
+	"functions/safe"        // Turns panics into errors
 )
 
 // Function 1: No parameters, no return values
@@ -15,46 +23,73 @@ func add(a, b int) int {
     return a + b
 }
 
-// Function 3: Two float64 parameters, two float64 return values (area and circumference)
-// Uses named return values
-func calculateCircleMetrics(radius float64) (area float64, circumference float64) {
-    const pi = 3.14159
-    area = pi * radius * radius
-    circumference = 2 * pi * radius
-    return // Naked return (returns current values of area and circumference)
+// Function 3: One float64 parameter, two float64 return values (area and circumference) and an error
+// Uses named return values. The math lives in the geometry package, which uses math.Pi
+func calculateCircleMetrics(radius float64) (area float64, circumference float64, err error) {
+    circle, err := geometry.NewCircle(geometry.Point{}, radius)
+    if err != nil {
+        return // Negative, NaN or infinite radius
+    }
+    if area, err = calculator.CheckResult("calculateCircleMetrics", circle.Area(), radius); err != nil {
+        return // The radius is so large the area overflows float64
+    }
+    circumference = circle.Perimeter()
+    return // Naked return (returns current values of area, circumference and err)
 }
 
 // Function 4: Division with multiple return values (result and an error)
 func divide(numerator, denominator float64) (float64, error) {
-    if denominator == 0 {
-        // Return zero value for float64 and a new error
-        return 0, errors.New("cannot divide by zero")
-    }
-    // Return the result and nil (meaning no error)
-    return numerator / denominator, nil
+    // On failure Divide returns the zero value for float64 and a typed error that
+    // callers can test with errors.Is: dividing by zero, a NaN or infinite
+    // operand, or a result that overflowed float64
+    return calculator.Divide(numerator, denominator)
 }
 
-// Function 5: Demonstrating 'defer' for cleanup
-func simulateFileProcessing(filename string) {
+// Function 5: Demonstrating 'defer' for cleanup, on a real file
+func processFile(filename string) {
     fmt.Printf("Attempting to open file: %s\n", filename)
 
-    // Simulate opening a file. In a real scenario, this would be os.Open(filename)
-    // For demonstration, let's just print a message.
-    // Assume file opens successfully.
-    fmt.Println("File opened successfully.")
-
-    // Defer this call to ensure it runs just before the function returns
-    defer fmt.Println("File closed.")
-    defer fmt.Println("Performing final cleanup...") // This will run before "File closed." (LIFO)
+    // Create the file so the example has something to read. A real program
+    // would process a file that already exists. CreateTemp picks a new,
+    // unused name (ending in filename) so we never overwrite someone's file.
+    file, err := os.CreateTemp("", "*-"+filename)
+    if err != nil {
+        fmt.Println("Error:", err)
+        return
+    }
+    path := file.Name()
+    _, err = file.WriteString("# notes\nbuy milk\n\ncall bob\n")
+    if closeErr := file.Close(); err == nil {
+        err = closeErr
+    }
+    if err != nil {
+        os.Remove(path)
+        fmt.Println("Error:", err)
+        return
+    }
 
-    // Simulate some work with the file
+    // Defer these calls to ensure they run just before the function returns
+    defer fmt.Println("File removed.")
+    defer os.Remove(path)
+    defer fmt.Println("Performing final cleanup...") // This will run before "File removed." (LIFO)
+
+    // The pipeline opens the file, streams it line by line through the
+    // stages and closes it again, even if a stage panics.
+    pipeline := fileproc.Pipeline{
+        Stages: []fileproc.Stage{
+            fileproc.Filter(func(r fileproc.Record) bool { return r.Text != "" && !strings.HasPrefix(r.Text, "#") }),
+            fileproc.Transform(strings.ToUpper),
+        },
+        Sink: fileproc.ToWriter(os.Stdout),
+    }
     fmt.Println("Reading data from file...")
-    // ... some complex operations ...
-    fmt.Println("Data processing complete.")
-
-    // No explicit close call here; defer handles it.
-    // What if an error occurs here? defer still runs!
+    result, err := pipeline.Run(context.Background(), path)
     // fmt.Println(1 / 0) // Uncommenting this would cause a panic, but defer would still run!
+    if err != nil {
+        // err joins every failure, including errors from closing files
+        fmt.Println("Error:", err)
+    }
+    fmt.Printf("Data processing complete: %d lines read, %d written.\n", result.Read, result.Written)
 }
 
 
@@ -69,8 +104,15 @@ func main() {
     fmt.Printf("10 + 5 = %d\n", sumResult)
 
     // Calling Function 3
-    circleArea, circleCircumference := calculateCircleMetrics(7.0)
-    fmt.Printf("Circle with radius 7.0: Area = %.2f, Circumference = %.2f\n", circleArea, circleCircumference)
+    circleArea, circleCircumference, err := calculateCircleMetrics(7.0)
+    if err != nil {
+        fmt.Println("Error:", err)
+    } else {
+        fmt.Printf("Circle with radius 7.0: Area = %.2f, Circumference = %.2f\n", circleArea, circleCircumference)
+    }
+    if _, _, err := calculateCircleMetrics(-1); err != nil {
+        fmt.Println("Error:", err) // NewCircle(-1): argument outside the domain
+    }
 
     // Calling Function 4: Handling multiple return values, including error
     fmt.Println("\n--- Division Examples ---")
@@ -82,14 +124,35 @@ func main() {
     }
 
     result2, err2 := divide(10.0, 0.0) // This will cause an error
-    if err2 != nil {
-        fmt.Println("Error:", err2)
+    if errors.Is(err2, calculator.ErrDivideByZero) {
+        fmt.Println("Error:", err2) // divide(10, 0): division by zero
+    } else if err2 != nil {
+        fmt.Println("Unexpected error:", err2)
     } else {
         fmt.Printf("10.0 / 0.0 = %.2f\n", result2)
     }
 
+    _, err3 := divide(1e308, 1e-308) // Too large for a float64
+    var rangeErr *calculator.RangeError
+    if errors.As(err3, &rangeErr) {
+        fmt.Printf("Error: %v (operation %q)\n", rangeErr, rangeErr.Op)
+    }
+
     // Calling Function 5: Demonstrating defer
-    fmt.Println("\n--- Defer Example (Simulated File Processing) ---")
-    simulateFileProcessing("my_document.txt")
-    fmt.Println("Program continues after simulateFileProcessing.")
+    fmt.Println("\n--- Defer Example (File Processing) ---")
+    processFile("my_document.txt")
+    fmt.Println("Program continues after processFile.")
+
+    // Recovering from a panic: safe.Do runs a function with a deferred
+    // recover and hands the panic back as an error
+    fmt.Println("\n--- Recovering from a Panic ---")
+    numbers := []int{10, 20, 30}
+    err = safe.Do(func() error {
+        fmt.Println(numbers[5]) // Panics: index out of range [5] with length 3
+        return nil
+    })
+    if panicErr, ok := safe.AsPanic(err); ok {
+        fmt.Printf("Recovered: %v (kind: %s)\n", panicErr, panicErr.Kind)
+    }
+    fmt.Println("Program continues after the panic.")
 }
\ No newline at end of file
@@ -0,0 +1,76 @@
+// safe/kind.go
+package safe
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+)
+
+// Kind classifies a panic.
+type Kind int
+
+const (
+	KindPanic           Kind = iota // An explicit call to panic.
+	KindNilDereference              // Using a nil pointer, or calling a method on a nil interface.
+	KindIndexOutOfRange             // An index or slice expression outside the bounds, like numbers[5] on a slice of five numbers.
+	KindDivideByZero                // Integer division or modulo by zero.
+	KindNilMap                      // Writing to a nil map.
+	KindTypeAssertion               // A failed x.(T) without the ", ok" form.
+	KindClosedChannel               // Sending on or closing a closed channel.
+	KindRuntime                     // Any other runtime error.
+)
+
+var kindNames = [...]string{
+	KindPanic:           "panic",
+	KindNilDereference:  "nil dereference",
+	KindIndexOutOfRange: "index out of range",
+	KindDivideByZero:    "divide by zero",
+	KindNilMap:          "nil map",
+	KindTypeAssertion:   "type assertion",
+	KindClosedChannel:   "closed channel",
+	KindRuntime:         "runtime error",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "unknown"
+	}
+	return kindNames[k]
+}
+
+// IsBug reports whether the panic came from the runtime catching a
+// programming mistake. Running the same code again will fail the same way,
+// so Retry doesn't retry these by default.
+func (k Kind) IsBug() bool {
+	return k != KindPanic
+}
+
+// classify works out the Kind of a recovered value. The runtime doesn't
+// export its error types, so apart from type assertions the only way to
+// tell them apart is their message.
+func classify(r any) Kind {
+	var rerr runtime.Error
+	err, _ := r.(error)
+	if !errors.As(err, &rerr) {
+		return KindPanic
+	}
+	var assertErr *runtime.TypeAssertionError
+	if errors.As(err, &assertErr) {
+		return KindTypeAssertion
+	}
+	msg := rerr.Error()
+	switch {
+	case strings.Contains(msg, "nil pointer dereference"):
+		return KindNilDereference
+	case strings.Contains(msg, "index out of range"), strings.Contains(msg, "slice bounds out of range"):
+		return KindIndexOutOfRange
+	case strings.Contains(msg, "divide by zero"):
+		return KindDivideByZero
+	case strings.Contains(msg, "nil map"):
+		return KindNilMap
+	case strings.Contains(msg, "closed channel"):
+		return KindClosedChannel
+	}
+	return KindRuntime
+}
@@ -0,0 +1,93 @@
+// safe/retry.go
+package safe
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy says how often and how fast Retry tries again. The zero Policy
+// tries once.
+type Policy struct {
+	// Attempts is the total number of tries, the first one included.
+	Attempts int
+
+	// Delay is the wait before the second try. Each later wait is
+	// Multiplier times the one before, up to MaxDelay if that is set.
+	Delay      time.Duration
+	Multiplier float64 // Defaults to 1, a constant delay.
+	MaxDelay   time.Duration
+
+	// Jitter waits a random time between zero and the computed delay, so
+	// many workers retrying at once don't all hit a service together.
+	Jitter bool
+
+	// RetryIf decides whether an error is worth another try. By default
+	// every error is, except panics whose Kind is a bug.
+	RetryIf func(error) bool
+}
+
+// Backoff is a Policy for calls to other services: five tries, waiting
+// up to 100ms, 200ms, 400ms and 800ms between them.
+var Backoff = Policy{Attempts: 5, Delay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: 5 * time.Second, Jitter: true}
+
+// retryable is the default RetryIf.
+func retryable(err error) bool {
+	if pe, ok := AsPanic(err); ok {
+		return !pe.Kind.IsBug()
+	}
+	return true
+}
+
+// delay returns the wait after the given failed attempt, counting from 1.
+func (p Policy) delay(attempt int) time.Duration {
+	d := float64(p.Delay)
+	if p.Multiplier > 0 {
+		for range attempt - 1 {
+			d *= p.Multiplier
+		}
+	}
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter && d > 0 {
+		d = rand.Float64() * d
+	}
+	return time.Duration(d)
+}
+
+// Retry calls fn through Do until it succeeds, the policy runs out of
+// attempts, RetryIf rejects the error, or ctx is done. It returns nil or
+// the last error, which still unwraps to a *PanicError if fn panicked.
+func Retry(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	retryIf := p.RetryIf
+	if retryIf == nil {
+		retryIf = retryable
+	}
+	attempts := max(p.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := Do(func() error { return fn(ctx) })
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			if attempts == 1 {
+				return err
+			}
+			return fmt.Errorf("safe: giving up after %d attempts: %w", attempt, err)
+		}
+		if !retryIf(err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("safe: stopped retrying: %w; last error: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// safe/retry_test.go
+package safe_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"functions/safe"
+)
+
+// quick retries without waiting.
+var quick = safe.Policy{Attempts: 3}
+
+func TestRetry(t *testing.T) {
+	errFlaky := errors.New("flaky")
+	tests := []struct {
+		name      string
+		policy    safe.Policy
+		fn        func(calls int) error
+		wantCalls int
+		wantErr   error
+		wantPanic bool
+		kind      safe.Kind // The panic's Kind, if wantPanic.
+	}{
+		{"succeeds at once", quick, func(int) error { return nil }, 1, nil, false, 0},
+		{"succeeds on the third try", quick, func(calls int) error {
+			if calls < 3 {
+				return errFlaky
+			}
+			return nil
+		}, 3, nil, false, 0},
+		{"gives up", quick, func(int) error { return errFlaky }, 3, errFlaky, false, 0},
+		{"one attempt by default", safe.Policy{}, func(int) error { return errFlaky }, 1, errFlaky, false, 0},
+		{"retries an explicit panic", quick, func(int) error { return explicitPanic() }, 3, nil, true, safe.KindPanic},
+		{"does not retry a nil dereference", quick, func(int) error { return nilDereference() }, 1, nil, true, safe.KindNilDereference},
+		{"does not retry an index out of range", quick, func(int) error { return indexOutOfRange() }, 1, nil, true, safe.KindIndexOutOfRange},
+		{"custom RetryIf", safe.Policy{Attempts: 3, RetryIf: func(err error) bool { return !errors.Is(err, errFlaky) }},
+			func(int) error { return errFlaky }, 1, errFlaky, false, 0},
+		{"custom RetryIf retries bugs", safe.Policy{Attempts: 2, RetryIf: func(error) bool { return true }},
+			func(int) error { return divideByZero() }, 2, nil, true, safe.KindDivideByZero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := safe.Retry(context.Background(), tt.policy, func(context.Context) error {
+				calls++
+				return tt.fn(calls)
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("fn called %d times, want %d", calls, tt.wantCalls)
+			}
+			pe, isPanic := safe.AsPanic(err)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Retry = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantPanic:
+				if !isPanic || pe.Kind != tt.kind {
+					t.Errorf("Retry = %v, want a %v panic", err, tt.kind)
+				}
+			case err != nil:
+				t.Errorf("Retry = %v, want nil", err)
+			}
+			if err != nil && tt.wantCalls > 1 && !strings.Contains(err.Error(), "giving up after") {
+				t.Errorf("Retry = %v, want it to say it gave up", err)
+			}
+		})
+	}
+}
+
+func TestRetryWaits(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	p := safe.Policy{Attempts: 3, Delay: 10 * time.Millisecond, Multiplier: 2}
+	safe.Retry(context.Background(), p, func(context.Context) error {
+		calls++
+		return errors.New("flaky")
+	})
+	// 10ms, then 20ms.
+	if elapsed := time.Since(start); calls != 3 || elapsed < 30*time.Millisecond {
+		t.Errorf("%d calls in %v, want 3 calls over at least 30ms", calls, elapsed)
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errFlaky := errors.New("flaky")
+	calls := 0
+	p := safe.Policy{Attempts: 5, Delay: time.Hour}
+
+	start := time.Now()
+	err := safe.Retry(ctx, p, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errFlaky
+	})
+	if time.Since(start) > time.Second {
+		t.Error("Retry waited out the delay after the context was cancelled")
+	}
+	if calls != 1 || !errors.Is(err, context.Canceled) || !errors.Is(err, errFlaky) {
+		t.Errorf("Retry = %v after %d calls, want the cancellation and the last error after 1", err, calls)
+	}
+
+	// fn sees the context, so it can stop early itself.
+	err = safe.Retry(ctx, quick, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Retry with a done context = %v, want context.Canceled", err)
+	}
+}
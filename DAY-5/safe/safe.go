@@ -0,0 +1,109 @@
+// safe/safe.go
+package safe // Run functions with recover, so a panic becomes an error you can inspect
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Frame is one function call on the stack of a panic.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+// PanicError is a recovered panic. Kind says what went wrong, Origin
+// where, and Stack is the full trace of the panicking goroutine as
+// debug.Stack prints it.
+type PanicError struct {
+	Kind   Kind
+	Value  any     // What was passed to panic.
+	Origin Frame   // The function that panicked, not counting the runtime.
+	Frames []Frame // The stack, innermost call first.
+	Stack  []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and
+// errors.As see through the panic.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Do calls fn and returns its error, or a *PanicError if it panicked.
+//
+// A panic that runtime.Goexit causes, as t.FailNow does in tests, is not
+// recovered and still ends the goroutine.
+func Do(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return fn()
+}
+
+// Call is Do for functions that return a value. After a panic it returns
+// the zero T.
+func Call[T any](fn func() (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			v, err = zero, newPanicError(r)
+		}
+	}()
+	return fn()
+}
+
+// Go runs fn in a new goroutine and passes its error, panics included, to
+// report. A nil error is not reported. Use it instead of a bare go
+// statement in worker pools: an unrecovered panic in any goroutine kills
+// the whole program.
+func Go(fn func() error, report func(error)) {
+	go func() {
+		if err := Do(fn); err != nil {
+			report(err)
+		}
+	}()
+}
+
+// AsPanic returns the *PanicError in err's chain, if there is one.
+func AsPanic(err error) (*PanicError, bool) {
+	var pe *PanicError
+	ok := errors.As(err, &pe)
+	return pe, ok
+}
+
+func newPanicError(r any) *PanicError {
+	e := &PanicError{Kind: classify(r), Value: r, Stack: debug.Stack()}
+
+	// Skip Callers, newPanicError and the deferred func in Do or Call.
+	// What's left starts inside the runtime's panic machinery.
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		fr := Frame{Function: f.Function, File: f.File, Line: f.Line}
+		e.Frames = append(e.Frames, fr)
+		if e.Origin == (Frame{}) && !strings.HasPrefix(f.Function, "runtime.") {
+			e.Origin = fr
+		}
+		if !more {
+			break
+		}
+	}
+	return e
+}
@@ -0,0 +1,168 @@
+// safe/safe_test.go
+package safe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"functions/safe"
+)
+
+// Each of these panics in its own way. They are named functions so the
+// tests can check that Origin points at them.
+
+func explicitPanic() error {
+	panic("giving up")
+}
+
+func nilDereference() error {
+	var p *struct{ n int }
+	p.n = 1
+	return nil
+}
+
+func indexOutOfRange() error {
+	numbers := make([]int, 5)
+	i := len(numbers)
+	numbers[i] = 1
+	return nil
+}
+
+func sliceOutOfRange() error {
+	numbers := make([]int, 3)
+	n := 10
+	_ = numbers[2:n]
+	return nil
+}
+
+func divideByZero() error {
+	a, b := 1, 0
+	_ = a / b
+	return nil
+}
+
+func nilMapWrite() error {
+	var m map[string]int
+	m["x"] = 1
+	return nil
+}
+
+func typeAssertion() error {
+	var x any = "text"
+	_ = x.(int)
+	return nil
+}
+
+func closeClosedChannel() error {
+	ch := make(chan int)
+	close(ch)
+	close(ch)
+	return nil
+}
+
+func sendOnClosedChannel() error {
+	ch := make(chan int, 1)
+	close(ch)
+	ch <- 1
+	return nil
+}
+
+func negativeLength() error {
+	n := -1
+	_ = make([]int, n)
+	return nil
+}
+
+func TestDoClassifies(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() error
+		kind safe.Kind
+	}{
+		{"explicitPanic", explicitPanic, safe.KindPanic},
+		{"nilDereference", nilDereference, safe.KindNilDereference},
+		{"indexOutOfRange", indexOutOfRange, safe.KindIndexOutOfRange},
+		{"sliceOutOfRange", sliceOutOfRange, safe.KindIndexOutOfRange},
+		{"divideByZero", divideByZero, safe.KindDivideByZero},
+		{"nilMapWrite", nilMapWrite, safe.KindNilMap},
+		{"typeAssertion", typeAssertion, safe.KindTypeAssertion},
+		{"closeClosedChannel", closeClosedChannel, safe.KindClosedChannel},
+		{"sendOnClosedChannel", sendOnClosedChannel, safe.KindClosedChannel},
+		{"negativeLength", negativeLength, safe.KindRuntime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := safe.Do(tt.fn)
+			pe, ok := safe.AsPanic(err)
+			if !ok {
+				t.Fatalf("Do = %v, want a *PanicError", err)
+			}
+			if pe.Kind != tt.kind {
+				t.Errorf("Kind = %v, want %v (panic: %v)", pe.Kind, tt.kind, pe.Value)
+			}
+			if pe.Kind.IsBug() != (tt.kind != safe.KindPanic) {
+				t.Errorf("%v.IsBug() = %v", pe.Kind, pe.Kind.IsBug())
+			}
+			if want := "safe_test." + tt.name; !strings.HasSuffix(pe.Origin.Function, want) {
+				t.Errorf("Origin = %v, want %s", pe.Origin, want)
+			}
+			if !strings.HasSuffix(pe.Origin.File, "safe_test.go") || pe.Origin.Line == 0 {
+				t.Errorf("Origin = %v, want a line in safe_test.go", pe.Origin)
+			}
+			if len(pe.Frames) == 0 || len(pe.Stack) == 0 {
+				t.Error("PanicError has no stack")
+			}
+		})
+	}
+}
+
+func TestPanicErrorUnwraps(t *testing.T) {
+	cause := errors.New("cause")
+	err := safe.Do(func() error { panic(cause) })
+	if !errors.Is(err, cause) {
+		t.Errorf("Do = %v, want it to unwrap to the panic value", err)
+	}
+	if pe, _ := safe.AsPanic(err); pe == nil || pe.Kind != safe.KindPanic {
+		t.Errorf("an error passed to panic is Kind %v, want KindPanic", pe)
+	}
+
+	if err := safe.Do(func() error { return cause }); err != cause {
+		t.Errorf("Do = %v, want fn's own error", err)
+	}
+	if _, ok := safe.AsPanic(cause); ok {
+		t.Error("AsPanic found a panic in a plain error")
+	}
+}
+
+func TestCall(t *testing.T) {
+	v, err := safe.Call(func() (int, error) { return 42, nil })
+	if v != 42 || err != nil {
+		t.Errorf("Call = %d, %v, want 42, nil", v, err)
+	}
+	v, err = safe.Call(func() (int, error) {
+		var m map[int]int
+		m[1] = 1
+		return 1, nil
+	})
+	if pe, ok := safe.AsPanic(err); v != 0 || !ok || pe.Kind != safe.KindNilMap {
+		t.Errorf("Call = %d, %v, want 0 and a nil map panic", v, err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	errs := make(chan error, 1)
+	safe.Go(divideByZero, func(err error) { errs <- err })
+	if pe, ok := safe.AsPanic(<-errs); !ok || pe.Kind != safe.KindDivideByZero {
+		t.Errorf("reported %v, want a divide by zero panic", pe)
+	}
+}
+
+func TestKindString(t *testing.T) {
+	if got := safe.KindNilMap.String(); got != "nil map" {
+		t.Errorf("KindNilMap.String() = %q", got)
+	}
+	if got := safe.Kind(99).String(); got != "unknown" {
+		t.Errorf("Kind(99).String() = %q, want unknown", got)
+	}
+}
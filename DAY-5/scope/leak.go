@@ -0,0 +1,132 @@
+// scope/leak.go
+package scope
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Leak tracking is for tests and off by default, so production code pays
+// nothing for it. While at least one Mark is active, every new Scope is
+// tracked until it is closed, with the stack that created it, so tests can
+// ask whether they left any behind.
+
+var (
+	tracking atomic.Int64 // Active Marks.
+
+	leakMu sync.Mutex
+	open   = make(map[uint64]Leak)
+	lastID uint64
+)
+
+// Leak is a scope that was created but not closed.
+type Leak struct {
+	ID      uint64
+	Name    string
+	Created string // The stack of the New call, innermost frame first.
+}
+
+func (l Leak) String() string {
+	return fmt.Sprintf("scope %q was never closed; created at:\n%s", l.Name, l.Created)
+}
+
+// track records a new scope and returns its ID, or 0 when tracking is off.
+func track(name string) uint64 {
+	if tracking.Load() == 0 {
+		return 0
+	}
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:]) // Skip Callers, track and New.
+	frames := runtime.CallersFrames(pcs[:n])
+	var stack strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&stack, "\t%s\n\t\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+
+	leakMu.Lock()
+	defer leakMu.Unlock()
+	lastID++
+	open[lastID] = Leak{ID: lastID, Name: name, Created: stack.String()}
+	return lastID
+}
+
+func untrack(id uint64) {
+	if id == 0 {
+		return
+	}
+	leakMu.Lock()
+	delete(open, id)
+	leakMu.Unlock()
+}
+
+// Mark turns leak tracking on and returns a marker for LeaksSince: only
+// scopes created after this call count, so scopes left open by earlier
+// tests are not blamed on this one. Tracking is global, not per test:
+// scopes that tests running in parallel create after the mark are counted
+// too, so don't combine VerifyClosed with t.Parallel.
+//
+// Each Mark keeps tracking on until it is passed to VerifyClosed or
+// Unmark.
+func Mark() uint64 {
+	leakMu.Lock()
+	defer leakMu.Unlock()
+	tracking.Add(1)
+	return lastID
+}
+
+// Unmark ends a Mark without checking for leaks. Once no Mark is active,
+// tracking stops and the record of open scopes is dropped.
+func Unmark() {
+	leakMu.Lock()
+	defer leakMu.Unlock()
+	if tracking.Add(-1) <= 0 {
+		tracking.Store(0)
+		clear(open)
+	}
+}
+
+// LeaksSince returns the scopes created after mark that are still open,
+// oldest first. LeaksSince(0) returns every open scope.
+func LeaksSince(mark uint64) []Leak {
+	leakMu.Lock()
+	defer leakMu.Unlock()
+	var out []Leak
+	for id, l := range open {
+		if id > mark {
+			out = append(out, l)
+		}
+	}
+	slices.SortFunc(out, func(a, b Leak) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+// TB is the part of testing.TB that VerifyClosed needs, so this package
+// doesn't have to import testing.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// VerifyClosed reports every scope created after mark that is still open,
+// then ends the Mark. Defer it at the top of a test:
+//
+//	func TestHandler(t *testing.T) {
+//		defer scope.VerifyClosed(t, scope.Mark())
+//		...
+//	}
+func VerifyClosed(t TB, mark uint64) {
+	t.Helper()
+	for _, l := range LeaksSince(mark) {
+		t.Errorf("%s", l)
+	}
+	Unmark()
+}
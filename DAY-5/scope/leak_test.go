@@ -0,0 +1,75 @@
+// scope/leak_test.go
+package scope_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"functions/scope"
+)
+
+// recorder is a scope.TB that keeps the errors instead of failing the test.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestVerifyClosedReportsLeak(t *testing.T) {
+	var r recorder
+	mark := scope.Mark()
+	closed := scope.New("closed")
+	leaked := scope.New("leaked")
+	closed.Close()
+	scope.VerifyClosed(&r, mark)
+
+	if len(r.errors) != 1 {
+		t.Fatalf("got %d reports, want 1: %q", len(r.errors), r.errors)
+	}
+	if msg := r.errors[0]; !strings.Contains(msg, `scope "leaked" was never closed`) ||
+		!strings.Contains(msg, "TestVerifyClosedReportsLeak") {
+		t.Errorf("report does not name the scope and where it was created:\n%s", msg)
+	}
+	leaked.Close()
+}
+
+func TestVerifyClosedPassesWhenClosed(t *testing.T) {
+	defer scope.VerifyClosed(t, scope.Mark())
+
+	s := scope.New("request")
+	s.Defer("noop", func(context.Context) error { return nil })
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMarkIgnoresOlderScopes(t *testing.T) {
+	outer := scope.Mark()
+	old := scope.New("old")
+	inner := scope.Mark()
+
+	var r recorder
+	scope.VerifyClosed(&r, inner)
+	if len(r.errors) != 0 {
+		t.Errorf("scope from before the mark reported: %q", r.errors)
+	}
+	if leaks := scope.LeaksSince(outer); len(leaks) != 1 || leaks[0].Name != "old" {
+		t.Errorf("LeaksSince(outer) = %v, want the old scope", leaks)
+	}
+	old.Close()
+	scope.Unmark()
+}
+
+func TestNoTrackingWithoutMark(t *testing.T) {
+	s := scope.New("untracked")
+	defer s.Close()
+	if leaks := scope.LeaksSince(0); len(leaks) != 0 {
+		t.Errorf("scopes tracked without a Mark: %v", leaks)
+	}
+}
@@ -0,0 +1,196 @@
+// scope/scope.go
+package scope // defer, as a value: collect cleanups and run them in reverse order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTimeout = errors.New("scope: cleanup timed out")
+	ErrClosed  = errors.New("scope: already closed")
+)
+
+// CleanupError says which cleanup failed. Err is what it returned,
+// ErrTimeout, or a *PanicError.
+type CleanupError struct {
+	Name string
+	Err  error
+}
+
+func (e *CleanupError) Error() string {
+	return fmt.Sprintf("scope: cleanup %q: %v", e.Name, e.Err)
+}
+
+func (e *CleanupError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is a panic in a cleanup, turned into an error so the other
+// cleanups still run.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// CleanupFunc releases a resource. The context is cancelled when the
+// cleanup's timeout expires.
+type CleanupFunc func(ctx context.Context) error
+
+type cleanup struct {
+	name    string
+	fn      CleanupFunc
+	timeout time.Duration
+}
+
+// Option configures one cleanup.
+type Option func(*cleanup)
+
+// Timeout limits how long a cleanup may take. A cleanup that overruns is
+// reported as ErrTimeout and left running in the background while the
+// remaining cleanups go ahead, so one stuck Close can't hang shutdown.
+func Timeout(d time.Duration) Option {
+	return func(c *cleanup) { c.timeout = d }
+}
+
+// Scope collects cleanups, like defer statements that can be passed
+// around, and runs them in reverse order when it is closed:
+//
+//	s := scope.New("request")
+//	defer s.Close()
+//	f, err := os.Open(name)
+//	if err != nil {
+//		return err
+//	}
+//	s.DeferClose("input", f)
+//
+// Unlike defer, Close returns the errors of every cleanup, and a cleanup
+// that panics doesn't stop the others. A Scope is safe for concurrent use.
+type Scope struct {
+	name string
+	id   uint64
+
+	mu       sync.Mutex
+	cleanups []cleanup
+	closed   bool
+	timeout  time.Duration
+}
+
+// New returns an open Scope. The name shows up in leak reports, which are
+// only kept while a test has called Mark.
+func New(name string) *Scope {
+	s := &Scope{name: name}
+	s.id = track(s.name)
+	return s
+}
+
+// SetTimeout sets the timeout for cleanups added without their own.
+// Zero, the default, means no limit.
+func (s *Scope) SetTimeout(d time.Duration) {
+	s.mu.Lock()
+	s.timeout = d
+	s.mu.Unlock()
+}
+
+// Defer registers a cleanup. If the scope is already closed, fn runs right
+// away and its error is returned, so the resource isn't leaked.
+func (s *Scope) Defer(name string, fn CleanupFunc, opts ...Option) error {
+	c := cleanup{name: name, fn: fn}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	s.mu.Lock()
+	if c.timeout == 0 {
+		c.timeout = s.timeout
+	}
+	if s.closed {
+		s.mu.Unlock()
+		return errors.Join(ErrClosed, run(context.Background(), c))
+	}
+	s.cleanups = append(s.cleanups, c)
+	s.mu.Unlock()
+	return nil
+}
+
+// DeferClose registers c.Close as a cleanup.
+func (s *Scope) DeferClose(name string, c io.Closer, opts ...Option) error {
+	return s.Defer(name, func(context.Context) error { return c.Close() }, opts...)
+}
+
+// Close runs every cleanup, last registered first, and returns their
+// errors joined with errors.Join, each as a *CleanupError. Closing a
+// closed scope does nothing.
+func (s *Scope) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext is Close with a deadline for all cleanups together. Once
+// ctx is done, the cleanups that haven't started yet still run, but with
+// an already-cancelled context, so they should release what they can
+// without waiting.
+func (s *Scope) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	cleanups := s.cleanups
+	s.cleanups = nil
+	s.mu.Unlock()
+	untrack(s.id)
+
+	var errs []error
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		errs = append(errs, run(ctx, cleanups[i]))
+	}
+	return errors.Join(errs...)
+}
+
+// run calls one cleanup, turning a panic or timeout into a *CleanupError.
+func run(ctx context.Context, c cleanup) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	done := make(chan error, 1) // Buffered, so an abandoned cleanup can still finish.
+	call := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		done <- c.fn(ctx)
+	}
+
+	var err error
+	if c.timeout <= 0 {
+		call()
+		err = <-done
+	} else {
+		go call()
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = ErrTimeout
+			} else {
+				err = ctx.Err()
+			}
+		}
+	}
+	if err != nil {
+		return &CleanupError{Name: c.name, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// scope/scope_test.go
+package scope_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"functions/scope"
+)
+
+// logged returns a cleanup that appends name to *log and returns err.
+func logged(log *[]string, name string, err error) scope.CleanupFunc {
+	return func(context.Context) error {
+		*log = append(*log, name)
+		return err
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestCloseRunsLastFirst(t *testing.T) {
+	var log []string
+	s := scope.New("lifo")
+	s.Defer("first", logged(&log, "first", nil))
+	s.DeferClose("second", closerFunc(func() error { log = append(log, "second"); return nil }))
+	s.Defer("third", logged(&log, "third", nil))
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"third", "second", "first"}; !slices.Equal(log, want) {
+		t.Errorf("ran %v, want %v", log, want)
+	}
+	// Closing again runs nothing.
+	if err := s.Close(); err != nil || len(log) != 3 {
+		t.Errorf("second Close = %v, ran %v", err, log)
+	}
+}
+
+func TestCloseJoinsErrors(t *testing.T) {
+	var log []string
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	s := scope.New("errors")
+	s.Defer("a", logged(&log, "a", errA))
+	s.Defer("ok", logged(&log, "ok", nil))
+	s.Defer("b", logged(&log, "b", errB))
+
+	err := s.Close()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("Close = %v, want both errors", err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("Close = %#v, want two joined errors", err)
+	}
+	var names []string
+	for _, e := range joined.Unwrap() {
+		var ce *scope.CleanupError
+		if !errors.As(e, &ce) {
+			t.Fatalf("%v is not a *CleanupError", e)
+		}
+		names = append(names, ce.Name)
+	}
+	if !slices.Equal(names, []string{"b", "a"}) || len(log) != 3 {
+		t.Errorf("errors from %v, ran %v; want b then a, and every cleanup run", names, log)
+	}
+}
+
+func TestCleanupPanics(t *testing.T) {
+	var log []string
+	s := scope.New("panics")
+	s.Defer("after", logged(&log, "after", nil))
+	s.Defer("boom", func(context.Context) error { panic("boom") })
+	s.Defer("before", logged(&log, "before", nil))
+
+	err := s.Close()
+	var ce *scope.CleanupError
+	if !errors.As(err, &ce) || ce.Name != "boom" {
+		t.Fatalf("Close = %v, want a *CleanupError for boom", err)
+	}
+	var pe *scope.PanicError
+	if !errors.As(ce.Err, &pe) || pe.Value != "boom" || !strings.Contains(string(pe.Stack), "TestCleanupPanics") {
+		t.Errorf("CleanupError.Err = %#v, want a *PanicError with the stack", ce.Err)
+	}
+	if want := []string{"before", "after"}; !slices.Equal(log, want) {
+		t.Errorf("ran %v, want %v", log, want)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	var log []string
+	s := scope.New("timeout")
+	s.Defer("later", logged(&log, "later", nil))
+	s.Defer("stuck", func(context.Context) error {
+		<-release // Ignores its context.
+		return nil
+	}, scope.Timeout(10*time.Millisecond))
+
+	start := time.Now()
+	err := s.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close took %v: the stuck cleanup held it up", elapsed)
+	}
+	var ce *scope.CleanupError
+	if !errors.Is(err, scope.ErrTimeout) || !errors.As(err, &ce) || ce.Name != "stuck" {
+		t.Errorf("Close = %v, want ErrTimeout from stuck", err)
+	}
+	if !slices.Equal(log, []string{"later"}) {
+		t.Errorf("ran %v, want the cleanup after the stuck one to run", log)
+	}
+}
+
+func TestSetTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	s := scope.New("default timeout")
+	s.SetTimeout(10 * time.Millisecond)
+	s.Defer("stuck", func(context.Context) error { <-release; return nil })
+	// A cleanup's own timeout wins over the default.
+	s.Defer("own", func(ctx context.Context) error {
+		if d, ok := ctx.Deadline(); !ok || time.Until(d) < time.Minute {
+			return errors.New("got the default timeout")
+		}
+		return nil
+	}, scope.Timeout(time.Hour))
+
+	err := s.Close()
+	var ce *scope.CleanupError
+	if !errors.As(err, &ce) || ce.Name != "stuck" || !errors.Is(err, scope.ErrTimeout) {
+		t.Errorf("Close = %v, want only stuck to time out", err)
+	}
+}
+
+func TestDeferAfterClose(t *testing.T) {
+	s := scope.New("closed")
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var log []string
+	errLate := errors.New("late cleanup failed")
+	err := s.Defer("late", logged(&log, "late", errLate))
+	if !errors.Is(err, scope.ErrClosed) || !errors.Is(err, errLate) {
+		t.Errorf("Defer after Close = %v, want ErrClosed and the cleanup's error", err)
+	}
+	if !slices.Equal(log, []string{"late"}) {
+		t.Errorf("ran %v: the late cleanup should run right away", log)
+	}
+	if err := s.Close(); err != nil || len(log) != 1 {
+		t.Errorf("Close = %v, ran %v: the late cleanup ran twice", err, log)
+	}
+}
+
+func TestCloseContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var seen []error
+	s := scope.New("cancelled")
+	for _, name := range []string{"a", "b"} {
+		s.Defer(name, func(ctx context.Context) error {
+			seen = append(seen, ctx.Err())
+			return nil
+		})
+	}
+	s.Defer("waits", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, scope.Timeout(time.Hour))
+
+	err := s.CloseContext(ctx)
+	var ce *scope.CleanupError
+	if !errors.As(err, &ce) || ce.Name != "waits" || !errors.Is(err, context.Canceled) || errors.Is(err, scope.ErrTimeout) {
+		t.Errorf("CloseContext = %v, want waits cancelled, not timed out", err)
+	}
+	// Every cleanup still ran, with the cancelled context.
+	if len(seen) != 2 || !errors.Is(seen[0], context.Canceled) || !errors.Is(seen[1], context.Canceled) {
+		t.Errorf("cleanups saw %v, want two cancelled contexts", seen)
+	}
+}
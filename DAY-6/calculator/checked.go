@@ -0,0 +1,72 @@
+// calculator/checked.go
+package calculator // Arithmetic that reports errors instead of returning wrong numbers
+
+import "math"
+
+// Divide returns a / b. Dividing by zero is a *DomainError wrapping
+// ErrDivideByZero, and a result too large for float64 is a *RangeError
+// wrapping ErrOverflow.
+func Divide(a, b float64) (float64, error) {
+	if err := CheckOperands("divide", a, b); err != nil {
+		return 0, err
+	}
+	if b == 0 {
+		return 0, &DomainError{Op: "divide", Operands: []any{a, b}, Err: ErrDivideByZero}
+	}
+	return CheckResult("divide", a/b, a, b)
+}
+
+// AddChecked is Add that reports overflow: Add(math.MaxInt, 1) silently
+// wraps around to math.MinInt, AddChecked returns a *RangeError.
+func AddChecked(a, b int) (int, error) {
+	sum := a + b
+	if (a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0) {
+		return 0, &RangeError{Op: "add", Operands: []any{a, b}, Err: ErrOverflow}
+	}
+	return sum, nil
+}
+
+// MultiplyChecked is Multiply that reports overflow.
+func MultiplyChecked(a, b int) (int, error) {
+	product := a * b
+	if a != 0 && (product/a != b || (a == -1 && b == math.MinInt)) {
+		return 0, &RangeError{Op: "multiply", Operands: []any{a, b}, Err: ErrOverflow}
+	}
+	return product, nil
+}
+
+// CheckOperands returns a *DomainError if any operand of op is NaN or
+// infinite, wrapping ErrNaN or ErrInf.
+func CheckOperands(op string, operands ...float64) error {
+	for _, x := range operands {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			err := ErrNaN
+			if math.IsInf(x, 0) {
+				err = ErrInf
+			}
+			return &DomainError{Op: op, Operands: floats(operands), Err: err}
+		}
+	}
+	return nil
+}
+
+// CheckResult returns result unless it is NaN or infinite, which with
+// finite operands means the calculation overflowed float64. The error is a
+// *RangeError wrapping ErrNaN or ErrOverflow.
+func CheckResult(op string, result float64, operands ...float64) (float64, error) {
+	switch {
+	case math.IsNaN(result):
+		return 0, &RangeError{Op: op, Operands: floats(operands), Err: ErrNaN}
+	case math.IsInf(result, 0):
+		return 0, &RangeError{Op: op, Operands: floats(operands), Err: ErrOverflow}
+	}
+	return result, nil
+}
+
+func floats(xs []float64) []any {
+	out := make([]any, len(xs))
+	for i, x := range xs {
+		out[i] = x
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+// calculator/checked_test.go
+package calculator_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"calculator/calculator"
+)
+
+// checkErr checks that err wraps want, a sentinel, and is of the
+// error type named by kind: "range", "domain", or "" for no error.
+func checkErr(t *testing.T, err, want error, kind string) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Errorf("err = %v, want nil", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Errorf("err = %v, want it to wrap %v", err, want)
+	}
+	var re *calculator.RangeError
+	var de *calculator.DomainError
+	switch kind {
+	case "range":
+		if !errors.As(err, &re) {
+			t.Errorf("err = %#v, want a *RangeError", err)
+		}
+	case "domain":
+		if !errors.As(err, &de) {
+			t.Errorf("err = %#v, want a *DomainError", err)
+		}
+	}
+}
+
+func TestAddChecked(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    int
+		want    int
+		wantErr error
+	}{
+		{"small", 2, 3, 5, nil},
+		{"mixed signs", math.MaxInt, math.MinInt, -1, nil},
+		{"to the max", math.MaxInt - 1, 1, math.MaxInt, nil},
+		{"to the min", math.MinInt + 1, -1, math.MinInt, nil},
+		{"past the max", math.MaxInt, 1, 0, calculator.ErrOverflow},
+		{"past the min", math.MinInt, -1, 0, calculator.ErrOverflow},
+		{"two mins", math.MinInt, math.MinInt, 0, calculator.ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calculator.AddChecked(tt.a, tt.b)
+			checkErr(t, err, tt.wantErr, "range")
+			if got != tt.want {
+				t.Errorf("AddChecked(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplyChecked(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    int
+		want    int
+		wantErr error
+	}{
+		{"small", 6, 7, 42, nil},
+		{"zero", 0, math.MinInt, 0, nil},
+		{"zero second", math.MaxInt, 0, 0, nil},
+		{"negate max", -1, math.MaxInt, -math.MaxInt, nil},
+		{"min times one", math.MinInt, 1, math.MinInt, nil},
+		{"negate min", -1, math.MinInt, 0, calculator.ErrOverflow},
+		{"min times minus one", math.MinInt, -1, 0, calculator.ErrOverflow},
+		{"max times two", math.MaxInt, 2, 0, calculator.ErrOverflow},
+		{"2^32 squared", 1 << 32, 1 << 32, 0, calculator.ErrOverflow},
+		{"just fits", 1 << 31, 1 << 31, 1 << 62, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calculator.MultiplyChecked(tt.a, tt.b)
+			checkErr(t, err, tt.wantErr, "range")
+			if got != tt.want {
+				t.Errorf("MultiplyChecked(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivide(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    float64
+		want    float64
+		wantErr error
+		kind    string
+	}{
+		{"exact", 10, 4, 2.5, nil, ""},
+		{"zero numerator", 0, 3, 0, nil, ""},
+		{"by zero", 10, 0, 0, calculator.ErrDivideByZero, "domain"},
+		{"zero by zero", 0, 0, 0, calculator.ErrDivideByZero, "domain"},
+		{"NaN operand", math.NaN(), 1, 0, calculator.ErrNaN, "domain"},
+		{"infinite operand", 1, math.Inf(-1), 0, calculator.ErrInf, "domain"},
+		{"overflow", math.MaxFloat64, 0.5, 0, calculator.ErrOverflow, "range"},
+		{"underflow is fine", math.SmallestNonzeroFloat64, 2, 0, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calculator.Divide(tt.a, tt.b)
+			checkErr(t, err, tt.wantErr, tt.kind)
+			if got != tt.want {
+				t.Errorf("Divide(%g, %g) = %g, want %g", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	_, err := calculator.Divide(10, 0)
+	if want := "divide(10, 0): division by zero"; err == nil || err.Error() != want {
+		t.Errorf("Divide(10, 0) = %v, want %q", err, want)
+	}
+}
+
+func TestCheckOperands(t *testing.T) {
+	tests := []struct {
+		name     string
+		operands []float64
+		wantErr  error
+	}{
+		{"none", nil, nil},
+		{"finite", []float64{1, -2, math.MaxFloat64}, nil},
+		{"NaN", []float64{1, math.NaN()}, calculator.ErrNaN},
+		{"+Inf", []float64{math.Inf(1)}, calculator.ErrInf},
+		{"first bad one wins", []float64{math.Inf(-1), math.NaN()}, calculator.ErrInf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := calculator.CheckOperands("op", tt.operands...)
+			checkErr(t, err, tt.wantErr, "domain")
+			var de *calculator.DomainError
+			if errors.As(err, &de) && (de.Op != "op" || len(de.Operands) != len(tt.operands)) {
+				t.Errorf("DomainError = %+v, want op and all operands", de)
+			}
+		})
+	}
+}
+
+func TestCheckResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  float64
+		wantErr error
+	}{
+		{"finite", 1.5, nil},
+		{"NaN", math.NaN(), calculator.ErrNaN},
+		{"+Inf", math.Inf(1), calculator.ErrOverflow},
+		{"-Inf", math.Inf(-1), calculator.ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calculator.CheckResult("power", tt.result, 10, 400)
+			checkErr(t, err, tt.wantErr, "range")
+			if err != nil && got != 0 {
+				t.Errorf("CheckResult = %g with an error, want 0", got)
+			}
+			if err == nil && got != tt.result {
+				t.Errorf("CheckResult = %g, want %g", got, tt.result)
+			}
+		})
+	}
+}
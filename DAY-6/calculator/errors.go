@@ -0,0 +1,63 @@
+// calculator/errors.go
+package calculator // The errors our calculations can return, for errors.Is and errors.As
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors. Check for them with errors.Is, which also looks inside
+// the DomainError and RangeError values that wrap them:
+//
+//	if errors.Is(err, calculator.ErrDivideByZero) { ... }
+var (
+	ErrDivideByZero = errors.New("division by zero")
+	ErrDomain       = errors.New("argument outside the domain")
+	ErrOverflow     = errors.New("result overflows")
+	ErrNaN          = errors.New("not a number")
+	ErrInf          = errors.New("infinite value")
+)
+
+// DomainError reports an operation called with arguments it is not defined
+// for, such as a division by zero or a negative radius. Err is the sentinel
+// saying why: ErrDivideByZero, ErrDomain, ErrNaN or ErrInf.
+type DomainError struct {
+	Op       string
+	Operands []any
+	Err      error
+}
+
+func (e *DomainError) Error() string {
+	return fmt.Sprintf("%s: %v", call(e.Op, e.Operands), e.Err)
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// RangeError reports valid arguments whose result can't be represented:
+// an int that overflows, or a float64 that became infinite (ErrOverflow)
+// or NaN (ErrNaN).
+type RangeError struct {
+	Op       string
+	Operands []any
+	Err      error
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s: %v", call(e.Op, e.Operands), e.Err)
+}
+
+func (e *RangeError) Unwrap() error {
+	return e.Err
+}
+
+// call formats an operation as "divide(10, 0)".
+func call(op string, operands []any) string {
+	args := make([]string, len(operands))
+	for i, o := range operands {
+		args[i] = fmt.Sprint(o)
+	}
+	return op + "(" + strings.Join(args, ", ") + ")"
+}
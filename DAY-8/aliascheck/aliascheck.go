@@ -0,0 +1,610 @@
+// aliascheck/aliascheck.go
+package aliascheck // A go/analysis pass that flags risky append aliasing
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `aliascheck reports appends whose result may share memory with another live slice
+
+DAY-8 prints "May be [p q s] or [p q r]" because append writes into the
+spare capacity of a backing array that other slices still look at. The
+analyzer reports three patterns that lead to that kind of surprise:
+
+  reslice-append:   appending to a slice made by reslicing another slice
+                    (b := a[:2]; b = append(b, x)) while a, or another
+                    view of a, is still used afterwards.
+  append-to-other:  storing append's result in a different variable
+                    (y := append(x, v)) while x is still used afterwards.
+  loop-goroutine:   a goroutine started inside a loop captures a slice
+                    variable that the loop keeps reassigning or writing to.
+
+"Still used afterwards" is judged by source position within the enclosing
+function, so the check is a heuristic: it favours catching the bug over
+proving it. The one thing it does prove is an append that cannot fit: when a
+view's length and capacity are known from a composite literal or a make call
+with constant sizes, an append that overflows the capacity must reallocate,
+so it is not reported and the result no longer counts as a view. Every
+report carries a suggested fix that forces a copy.`
+
+// Analyzer is the aliascheck pass. Run it standalone with cmd/aliascheck or
+// through go vet -vettool.
+var Analyzer = &analysis.Analyzer{
+	Name:     "aliascheck",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	filter := []ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}
+	inspect.Preorder(filter, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body == nil {
+			return
+		}
+		c := &checker{pass: pass, body: body}
+		c.collect()
+		c.checkAppends()
+		c.checkLoops()
+	})
+	return nil, nil
+}
+
+// view records that a variable was assigned a two-index reslice of another
+// variable, e.g. sub := list[1:].
+type view struct {
+	pos   token.Pos      // Where the assignment takes effect.
+	base  *types.Var     // The variable that was resliced.
+	slice *ast.SliceExpr // The reslice expression itself.
+	size                 // Length and capacity of the view, if known.
+}
+
+// size is the length and capacity of a slice, when they are known
+// statically.
+type size struct {
+	known    bool
+	len, cap int64
+}
+
+// fits reports whether appending n elements can reuse the backing array.
+// Unknown sizes might always fit.
+func (sz size) fits(n int64) bool {
+	return !sz.known || sz.len+n <= sz.cap
+}
+
+// sized records the size a variable was assigned at pos.
+type sized struct {
+	pos token.Pos
+	size
+}
+
+// checker holds the facts gathered for one function body. Nested function
+// literals are visited separately by run, so uses inside them are attributed
+// to both bodies; that only makes "still used afterwards" more conservative.
+type checker struct {
+	pass  *analysis.Pass
+	body  *ast.BlockStmt
+	uses  map[*types.Var][]token.Pos
+	views map[*types.Var][]view
+	sizes map[*types.Var][]sized
+	seen  map[*ast.GoStmt]map[*types.Var]bool
+}
+
+// collect records every use of a local slice variable and every assignment
+// that makes one variable a view of another.
+func (c *checker) collect() {
+	c.uses = make(map[*types.Var][]token.Pos)
+	c.views = make(map[*types.Var][]view)
+	c.sizes = make(map[*types.Var][]sized)
+
+	ast.Inspect(c.body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if v := c.sliceVar(n); v != nil {
+				c.uses[v] = append(c.uses[v], n.Pos())
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				break
+			}
+			for i, lhs := range n.Lhs {
+				c.recordSize(lhs, n.Rhs[i], n.End())
+				c.recordView(lhs, n.Rhs[i], n.End())
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) != len(n.Values) {
+				break
+			}
+			for i, name := range n.Names {
+				c.recordSize(name, n.Values[i], n.End())
+				c.recordView(name, n.Values[i], n.End())
+			}
+		}
+		return true
+	})
+}
+
+func (c *checker) recordView(lhs, rhs ast.Expr, pos token.Pos) {
+	id, ok := lhs.(*ast.Ident)
+	if !ok {
+		return
+	}
+	v := c.sliceVar(id)
+	if v == nil {
+		return
+	}
+	// b = append(b, x) keeps b a view of whatever it was a view of: the
+	// append may well have fitted into the shared capacity. Only an append
+	// known to overflow the capacity gives b an array of its own.
+	if call, ok := ast.Unparen(rhs).(*ast.CallExpr); ok && c.isAppend(call) && len(call.Args) > 0 {
+		if src, ok := ast.Unparen(call.Args[0]).(*ast.Ident); ok {
+			if sv := c.sliceVar(src); sv != nil {
+				if vw, ok := c.viewAt(sv, call.Pos()); ok {
+					n, counted := appended(call)
+					switch {
+					case counted && !vw.fits(n):
+						c.views[v] = append(c.views[v], view{pos: pos})
+					case counted && vw.known:
+						vw.len += n
+						c.views[v] = append(c.views[v], view{pos: pos, base: vw.base, slice: vw.slice, size: vw.size})
+					default:
+						c.views[v] = append(c.views[v], view{pos: pos, base: vw.base, slice: vw.slice})
+					}
+					return
+				}
+			}
+		}
+	}
+
+	se, ok := ast.Unparen(rhs).(*ast.SliceExpr)
+	if !ok || se.Slice3 {
+		// Anything else (including a three-index slice, whose capacity is
+		// clipped) ends the view relationship.
+		c.views[v] = append(c.views[v], view{pos: pos})
+		return
+	}
+	base, ok := ast.Unparen(se.X).(*ast.Ident)
+	if !ok {
+		c.views[v] = append(c.views[v], view{pos: pos})
+		return
+	}
+	c.views[v] = append(c.views[v], view{pos: pos, base: c.sliceVar(base), slice: se, size: c.sliceSize(se)})
+}
+
+// recordSize remembers the length and capacity of lhs when rhs makes them
+// known: a composite literal without keys, or make with constant sizes.
+// Any other assignment forgets them.
+func (c *checker) recordSize(lhs, rhs ast.Expr, pos token.Pos) {
+	id, ok := lhs.(*ast.Ident)
+	if !ok {
+		return
+	}
+	v := c.sliceVar(id)
+	if v == nil {
+		return
+	}
+	var sz size
+	switch rhs := ast.Unparen(rhs).(type) {
+	case *ast.CompositeLit:
+		sz = size{known: true, len: int64(len(rhs.Elts)), cap: int64(len(rhs.Elts))}
+		for _, elt := range rhs.Elts {
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				sz = size{}
+			}
+		}
+	case *ast.CallExpr:
+		if !c.isBuiltin(rhs, "make") || len(rhs.Args) < 2 {
+			break
+		}
+		n, ok := c.constInt(rhs.Args[1])
+		capacity := n
+		if len(rhs.Args) == 3 {
+			var capOK bool
+			capacity, capOK = c.constInt(rhs.Args[2])
+			ok = ok && capOK
+		}
+		if ok {
+			sz = size{known: true, len: n, cap: capacity}
+		}
+	}
+	c.sizes[v] = append(c.sizes[v], sized{pos: pos, size: sz})
+}
+
+// sizeAt returns the size v was most recently assigned before pos.
+func (c *checker) sizeAt(v *types.Var, pos token.Pos) size {
+	var last size
+	for _, s := range c.sizes[v] {
+		if s.pos < pos {
+			last = s.size
+		}
+	}
+	return last
+}
+
+// sliceSize works out the length and capacity of a two-index reslice of a
+// variable whose size is known, e.g. a[:2] of a := []T{x, y, z}.
+func (c *checker) sliceSize(se *ast.SliceExpr) size {
+	id, ok := ast.Unparen(se.X).(*ast.Ident)
+	if !ok || se.Slice3 {
+		return size{}
+	}
+	v := c.sliceVar(id)
+	if v == nil {
+		return size{}
+	}
+	base := c.sizeAt(v, se.Pos())
+	if !base.known {
+		return size{}
+	}
+	lo, hi := int64(0), base.len
+	if se.Low != nil {
+		if lo, ok = c.constInt(se.Low); !ok {
+			return size{}
+		}
+	}
+	if se.High != nil {
+		if hi, ok = c.constInt(se.High); !ok {
+			return size{}
+		}
+	}
+	if lo < 0 || hi < lo || hi > base.cap {
+		return size{}
+	}
+	return size{known: true, len: hi - lo, cap: base.cap - lo}
+}
+
+// appended returns how many elements call appends, unless it spreads a
+// slice with ... and the count is unknown.
+func appended(call *ast.CallExpr) (int64, bool) {
+	if call.Ellipsis.IsValid() {
+		return 0, false
+	}
+	return int64(len(call.Args) - 1), true
+}
+
+// constInt returns the value of a constant integer expression.
+func (c *checker) constInt(e ast.Expr) (int64, bool) {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Value == nil {
+		return 0, false
+	}
+	return constant.Int64Val(constant.ToInt(tv.Value))
+}
+
+// viewAt returns the reslice that v was most recently assigned before pos.
+func (c *checker) viewAt(v *types.Var, pos token.Pos) (view, bool) {
+	var last view
+	found := false
+	for _, vw := range c.views[v] {
+		if vw.pos < pos {
+			last, found = vw, true
+		}
+	}
+	return last, found && last.base != nil
+}
+
+// usedAfter reports whether v is referenced anywhere after pos.
+func (c *checker) usedAfter(v *types.Var, pos token.Pos) bool {
+	for _, p := range c.uses[v] {
+		if p > pos {
+			return true
+		}
+	}
+	return false
+}
+
+// liveSibling returns base itself or another view of base that is used after
+// pos, skipping self.
+func (c *checker) liveSibling(base, self *types.Var, pos token.Pos) *types.Var {
+	if base != self && c.usedAfter(base, pos) {
+		return base
+	}
+	for other := range c.views {
+		if other == self || other == base {
+			continue
+		}
+		if vw, ok := c.viewAt(other, pos); ok && vw.base == base && c.usedAfter(other, pos) {
+			return other
+		}
+	}
+	return nil
+}
+
+// checkAppends looks at every call to the builtin append.
+func (c *checker) checkAppends() {
+	assigned := make(map[*ast.CallExpr]*types.Var)
+	ast.Inspect(c.body, func(n ast.Node) bool {
+		if as, ok := n.(*ast.AssignStmt); ok && len(as.Lhs) == len(as.Rhs) {
+			for i, rhs := range as.Rhs {
+				call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				if id, ok := as.Lhs[i].(*ast.Ident); ok {
+					assigned[call] = c.sliceVar(id)
+				}
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(c.body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false // Checked with its own body by run.
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !c.isAppend(call) || len(call.Args) == 0 {
+			return true
+		}
+		dst, hasDst := assigned[call]
+		end := call.End()
+
+		switch arg := ast.Unparen(call.Args[0]).(type) {
+		case *ast.SliceExpr:
+			// append(a[:n], ...) writes into a's backing array directly.
+			base, ok := ast.Unparen(arg.X).(*ast.Ident)
+			if !ok || arg.Slice3 {
+				return true
+			}
+			bv := c.sliceVar(base)
+			if bv == nil || (hasDst && dst == bv) {
+				// a = append(a[:i], a[j:]...) is the usual delete idiom.
+				return true
+			}
+			if n, ok := appended(call); ok && !c.sliceSize(arg).fits(n) {
+				return true // Must reallocate, so nothing is overwritten.
+			}
+			if live := c.liveSibling(bv, nil, end); live != nil {
+				c.report(call, "reslice-append", arg, live,
+					fmt.Sprintf("append to reslice of %s may overwrite elements still visible through %s", base.Name, live.Name()))
+			}
+
+		case *ast.Ident:
+			v := c.sliceVar(arg)
+			if v == nil {
+				return true
+			}
+			if vw, ok := c.viewAt(v, call.Pos()); ok {
+				if n, ok := appended(call); ok && !vw.fits(n) {
+					return true // Must reallocate, so nothing is overwritten.
+				}
+				if live := c.liveSibling(vw.base, v, end); live != nil {
+					c.report(call, "reslice-append", vw.slice, live,
+						fmt.Sprintf("append to %s, a reslice of %s, may overwrite elements still visible through %s", arg.Name, vw.base.Name(), live.Name()))
+				}
+				return true
+			}
+			if hasDst && dst != nil && dst != v && c.usedAfter(v, end) {
+				c.reportOther(call, arg, v, dst)
+			}
+		}
+		return true
+	})
+}
+
+// report emits a reslice-append diagnostic whose fix clips the capacity of
+// the reslice with a full slice expression, so append must copy.
+func (c *checker) report(call *ast.CallExpr, category string, se *ast.SliceExpr, live *types.Var, msg string) {
+	diag := analysis.Diagnostic{
+		Pos:      call.Pos(),
+		End:      call.End(),
+		Category: category,
+		Message:  msg,
+	}
+	if fixed := c.clipped(se); fixed != "" {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Use a full slice expression so append always copies",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     se.Pos(),
+				End:     se.End(),
+				NewText: []byte(fixed),
+			}},
+		}}
+	}
+	c.pass.Report(diag)
+}
+
+// reportOther emits an append-to-other diagnostic for y := append(x, ...).
+func (c *checker) reportOther(call *ast.CallExpr, arg *ast.Ident, src, dst *types.Var) {
+	fixed := fmt.Sprintf("%[1]s[:len(%[1]s):len(%[1]s)]", arg.Name)
+	c.pass.Report(analysis.Diagnostic{
+		Pos:      call.Pos(),
+		End:      call.End(),
+		Category: "append-to-other",
+		Message: fmt.Sprintf("%s and %s may share a backing array after this append; a later append to %s can overwrite %s",
+			dst.Name(), src.Name(), src.Name(), dst.Name()),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   fmt.Sprintf("Clip the capacity of %s so append always copies", src.Name()),
+			TextEdits: []analysis.TextEdit{{Pos: arg.Pos(), End: arg.End(), NewText: []byte(fixed)}},
+		}},
+	})
+}
+
+// clipped renders se as a three-index slice expression, e.g. a[1:3] becomes
+// a[1:3:3] and a[1:] becomes a[1:len(a):len(a)].
+func (c *checker) clipped(se *ast.SliceExpr) string {
+	x := c.render(se.X)
+	lo := ""
+	if se.Low != nil {
+		lo = c.render(se.Low)
+	}
+	hi := fmt.Sprintf("len(%s)", x)
+	if se.High != nil {
+		hi = c.render(se.High)
+	}
+	if x == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s[%s:%s:%s]", x, lo, hi, hi)
+}
+
+func (c *checker) render(e ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, c.pass.Fset, e); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// checkLoops reports goroutines started in a loop that capture a slice
+// variable the loop itself keeps modifying.
+func (c *checker) checkLoops() {
+	c.seen = make(map[*ast.GoStmt]map[*types.Var]bool)
+	ast.Inspect(c.body, func(n ast.Node) bool {
+		var loopBody *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.FuncLit:
+			return false // Checked with its own body by run.
+		case *ast.ForStmt:
+			loopBody = loop.Body
+		case *ast.RangeStmt:
+			loopBody = loop.Body
+		default:
+			return true
+		}
+		ast.Inspect(loopBody, func(n ast.Node) bool {
+			gs, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+			if lit, ok := gs.Call.Fun.(*ast.FuncLit); ok {
+				c.checkGo(gs, lit, loopBody)
+			}
+			return false
+		})
+		return true
+	})
+}
+
+func (c *checker) checkGo(gs *ast.GoStmt, lit *ast.FuncLit, loopBody *ast.BlockStmt) {
+	// Nested loops visit the same go statement more than once.
+	reported := c.seen[gs]
+	if reported == nil {
+		reported = make(map[*types.Var]bool)
+		c.seen[gs] = reported
+	}
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		v := c.sliceVar(id)
+		if v == nil || reported[v] {
+			return true
+		}
+		// Only variables declared outside the loop body are shared between
+		// iterations; per-iteration copies are fine.
+		if v.Pos() >= loopBody.Pos() && v.Pos() < loopBody.End() {
+			return true
+		}
+		if !c.modifiedIn(v, loopBody, lit) {
+			return true
+		}
+		reported[v] = true
+		c.pass.Report(analysis.Diagnostic{
+			Pos:      id.Pos(),
+			End:      id.End(),
+			Category: "loop-goroutine",
+			Message: fmt.Sprintf("goroutine captures slice %s, which the enclosing loop keeps modifying; the goroutine may observe later iterations' data",
+				v.Name()),
+			SuggestedFixes: c.passCopy(gs, lit, v),
+		})
+		return true
+	})
+}
+
+// modifiedIn reports whether v is reassigned, or one of its elements written,
+// inside loopBody but outside the goroutine's function literal.
+func (c *checker) modifiedIn(v *types.Var, loopBody *ast.BlockStmt, lit *ast.FuncLit) bool {
+	modified := false
+	ast.Inspect(loopBody, func(n ast.Node) bool {
+		if n == lit || modified {
+			return false
+		}
+		as, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range as.Lhs {
+			if idx, ok := lhs.(*ast.IndexExpr); ok {
+				lhs = idx.X
+			}
+			if id, ok := ast.Unparen(lhs).(*ast.Ident); ok && c.pass.TypesInfo.Uses[id] == v {
+				modified = true
+			}
+		}
+		return true
+	})
+	return modified
+}
+
+// passCopy suggests handing the goroutine its own copy of v as a parameter:
+//
+//	go func(v []T) { ... }(append(v[:0:0], v...))
+func (c *checker) passCopy(gs *ast.GoStmt, lit *ast.FuncLit, v *types.Var) []analysis.SuggestedFix {
+	qual := types.RelativeTo(c.pass.Pkg)
+	param := v.Name() + " " + types.TypeString(v.Type(), qual)
+	arg := fmt.Sprintf("append(%[1]s[:0:0], %[1]s...)", v.Name())
+
+	params := lit.Type.Params
+	if params.NumFields() > 0 {
+		param = ", " + param
+	}
+	if len(gs.Call.Args) > 0 {
+		arg = ", " + arg
+	}
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Pass a copy of %s to the goroutine", v.Name()),
+		TextEdits: []analysis.TextEdit{
+			{Pos: params.Closing, End: params.Closing, NewText: []byte(param)},
+			{Pos: gs.Call.Rparen, End: gs.Call.Rparen, NewText: []byte(arg)},
+		},
+	}}
+}
+
+// isAppend reports whether call invokes the builtin append.
+func (c *checker) isAppend(call *ast.CallExpr) bool {
+	return c.isBuiltin(call, "append")
+}
+
+// isBuiltin reports whether call invokes the named builtin function.
+func (c *checker) isBuiltin(call *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	b, ok := c.pass.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && b.Name() == name
+}
+
+// sliceVar returns the local variable of slice type that id refers to, or nil.
+func (c *checker) sliceVar(id *ast.Ident) *types.Var {
+	obj := c.pass.TypesInfo.ObjectOf(id)
+	v, ok := obj.(*types.Var)
+	if !ok || v.IsField() || v.Parent() == nil || v.Parent() == c.pass.Pkg.Scope() {
+		return nil
+	}
+	if _, ok := v.Type().Underlying().(*types.Slice); !ok {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,16 @@
+// aliascheck/aliascheck_test.go
+package aliascheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"todolist/aliascheck"
+)
+
+// TestAnalyzer checks the diagnostics against the // want comments in
+// testdata/src/a and the suggested fixes against a.go.golden.
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), aliascheck.Analyzer, "a")
+}
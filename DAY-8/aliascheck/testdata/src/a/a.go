@@ -0,0 +1,87 @@
+package a
+
+import "fmt"
+
+func resliceThenAppend() {
+	shared := []string{"p", "q", "r"}
+	sub := shared[:2]
+	sub = append(sub, "s") // want `append to sub, a reslice of shared, may overwrite elements still visible through shared`
+	fmt.Println(shared, sub)
+}
+
+func appendToReslice(list []int) {
+	head := append(list[:1], 9) // want `append to reslice of list may overwrite elements still visible through list`
+	fmt.Println(list, head)
+}
+
+func siblingView(list []int) {
+	left := list[:2]
+	right := list[1:]
+	left = append(left, 7) // want `append to left, a reslice of list, may overwrite elements still visible through right`
+	fmt.Println(left, right)
+}
+
+func appendToOther(x []int) {
+	y := append(x, 1) // want `y and x may share a backing array after this append; a later append to x can overwrite y`
+	fmt.Println(x, y)
+}
+
+func loopGoroutine() {
+	var batch []int
+	for i := range 3 {
+		batch = append(batch, i)
+		go func() {
+			fmt.Println(batch) // want `goroutine captures slice batch, which the enclosing loop keeps modifying`
+		}()
+	}
+}
+
+// DAY-8's second append: sub is already full, so the append must
+// reallocate and cannot touch shared.
+func appendBeyondCapacity() {
+	shared := []string{"p", "q", "r"}
+	sub := shared[:2]
+	sub = append(sub, "s") // want `append to sub, a reslice of shared`
+	sub = append(sub, "t", "u")
+	sub = append(sub, "v")
+	fmt.Println(shared, sub)
+}
+
+func appendBeyondMake() {
+	buf := make([]byte, 4, 8)
+	head := buf[2:]
+	head = append(head, 1, 2, 3, 4, 5, 6, 7)
+	fmt.Println(buf, head)
+}
+
+func deleteIdiom(list []int, i int) []int {
+	list = append(list[:i], list[i+1:]...)
+	return list
+}
+
+func fullSliceExpression(list []int) {
+	sub := list[:2:2]
+	sub = append(sub, 1)
+	fmt.Println(list, sub)
+}
+
+func baseNotUsedAfter(list []int) []int {
+	sub := list[:2]
+	sub = append(sub, 1)
+	return sub
+}
+
+func sourceNotUsedAfter(x []int) []int {
+	y := append(x, 1)
+	return y
+}
+
+func goroutineWithOwnCopy() {
+	var batch []int
+	for i := range 3 {
+		batch = append(batch, i)
+		go func(batch []int) {
+			fmt.Println(batch)
+		}(append(batch[:0:0], batch...))
+	}
+}
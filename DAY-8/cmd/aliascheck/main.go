@@ -0,0 +1,17 @@
+// cmd/aliascheck/main.go
+package main
+
+// aliascheck reports risky append aliasing. Build it and hand it to go vet:
+//
+//	go build -o aliascheck ./cmd/aliascheck
+//	go vet -vettool=$(pwd)/aliascheck ./...
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"todolist/aliascheck"
+)
+
+func main() {
+	unitchecker.Main(aliascheck.Analyzer)
+}
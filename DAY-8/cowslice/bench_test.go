@@ -0,0 +1,115 @@
+// cowslice/bench_test.go
+package cowslice_test
+
+import (
+	"testing"
+
+	"todolist/cowslice"
+)
+
+// The benchmarks compare each operation on a plain slice ("raw") with the
+// same operation on a Slice, with and without debug mode:
+//
+//	go test -bench . ./cowslice
+
+const size = 1024
+
+func newCow(debug bool) *cowslice.Slice[int] {
+	return withDebug(cowslice.Of(make([]int, size)...), debug)
+}
+
+func withDebug(s *cowslice.Slice[int], debug bool) *cowslice.Slice[int] {
+	if debug {
+		s.Debug(nil)
+	}
+	return s
+}
+
+func BenchmarkGet(b *testing.B) {
+	b.Run("raw", func(b *testing.B) {
+		s := make([]int, size)
+		sum := 0
+		for i := 0; b.Loop(); i++ {
+			sum += s[i%size]
+		}
+		_ = sum
+	})
+	for _, debug := range []bool{false, true} {
+		b.Run(cowName(debug), func(b *testing.B) {
+			s := newCow(debug)
+			sum := 0
+			for i := 0; b.Loop(); i++ {
+				sum += s.Get(i % size)
+			}
+			_ = sum
+		})
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	b.Run("raw", func(b *testing.B) {
+		s := make([]int, size)
+		for i := 0; b.Loop(); i++ {
+			s[i%size] = i
+		}
+	})
+	for _, debug := range []bool{false, true} {
+		b.Run(cowName(debug), func(b *testing.B) {
+			s := newCow(debug)
+			for i := 0; b.Loop(); i++ {
+				s.Set(i%size, i)
+			}
+		})
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	b.Run("raw", func(b *testing.B) {
+		var s []int
+		for i := 0; b.Loop(); i++ {
+			if len(s) == size {
+				s = s[:0]
+			}
+			s = append(s, i)
+		}
+	})
+	for _, debug := range []bool{false, true} {
+		b.Run(cowName(debug), func(b *testing.B) {
+			s := withDebug(cowslice.New[int](size), debug)
+			for i := 0; b.Loop(); i++ {
+				if s.Len() == size {
+					s = withDebug(cowslice.New[int](size), debug)
+				}
+				s.Append(i)
+			}
+		})
+	}
+}
+
+// BenchmarkSubThenSet takes a view and writes through it: the raw version
+// shares memory (the bug), the cow version pays for a private copy.
+func BenchmarkSubThenSet(b *testing.B) {
+	b.Run("raw", func(b *testing.B) {
+		s := make([]int, size)
+		for i := 0; b.Loop(); i++ {
+			sub := s[:size/2]
+			sub[0] = i
+		}
+	})
+	for _, debug := range []bool{false, true} {
+		b.Run(cowName(debug), func(b *testing.B) {
+			s := newCow(debug)
+			for i := 0; b.Loop(); i++ {
+				sub := s.Sub(0, size/2)
+				sub.Set(0, i)
+			}
+		})
+	}
+}
+
+func cowName(debug bool) string {
+	if debug {
+		return "debug"
+	}
+	return "cow"
+}
@@ -0,0 +1,73 @@
+// cowslice/debug.go
+package cowslice
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// AliasError describes a write that reached a view without going through it.
+// With copy-on-write this can only happen when someone writes through the
+// slice returned by Raw, so the report points at a real aliasing bug.
+type AliasError struct {
+	Index int    // Index within the view that changed.
+	Want  any    // Value the view last saw at Index.
+	Got   any    // Value now stored at Index.
+	Stack []byte // Stack of the goroutine that noticed the change.
+}
+
+func (e *AliasError) Error() string {
+	return fmt.Sprintf("cowslice: aliased write detected at index %d: was %v, now %v", e.Index, e.Want, e.Got)
+}
+
+// debugState keeps a private copy of what a view last saw so that writes made
+// behind its back can be spotted on the next access.
+type debugState[T any] struct {
+	shadow []T
+	report func(*AliasError)
+}
+
+// Debug turns on alias detection for this view and every view later derived
+// from it with Sub or Clone. Each access compares the view against a private
+// snapshot and calls report when an element changed without a Set or Append
+// on this view. If report is nil, the error is raised as a panic.
+//
+// Debug mode keeps a full copy of every view and compares it on each access,
+// so it is meant for tests and debugging sessions, not production traffic.
+func (s *Slice[T]) Debug(report func(*AliasError)) *Slice[T] {
+	if report == nil {
+		report = func(err *AliasError) { panic(err) }
+	}
+	s.dbg = &debugState[T]{report: report}
+	s.snapshot()
+	return s
+}
+
+// check compares the view with its snapshot and reports the first difference.
+// It is a no-op unless debug mode is on.
+func (s *Slice[T]) check() {
+	if s.dbg == nil {
+		return
+	}
+	current := s.b.data[s.lo:s.hi]
+	for i := range current {
+		if i >= len(s.dbg.shadow) {
+			break
+		}
+		if !reflect.DeepEqual(current[i], s.dbg.shadow[i]) {
+			err := &AliasError{Index: i, Want: s.dbg.shadow[i], Got: current[i], Stack: debug.Stack()}
+			s.snapshot() // Report each aliased write once.
+			s.dbg.report(err)
+			return
+		}
+	}
+}
+
+// snapshot records the current contents of the view as the expected state.
+func (s *Slice[T]) snapshot() {
+	if s.dbg == nil {
+		return
+	}
+	s.dbg.shadow = append(s.dbg.shadow[:0], s.b.data[s.lo:s.hi]...)
+}
@@ -0,0 +1,206 @@
+// cowslice/slice.go
+package cowslice // A copy-on-write slice that never leaks writes between views
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"sync/atomic"
+)
+
+// backing is the array shared by every view created from the same Slice.
+// views counts how many live Slice values currently point at it; as long as
+// it is greater than one, nobody is allowed to write into data directly.
+type backing[T any] struct {
+	data  []T
+	views atomic.Int64 // Also decremented by the GC cleanup of dropped views.
+}
+
+func newBacking[T any](data []T) *backing[T] {
+	b := &backing[T]{data: data}
+	b.views.Store(1)
+	return b
+}
+
+// lease is a view's claim on its backing array. It lives apart from the
+// Slice so the cleanup that runs once the Slice is garbage collected can
+// still give the claim back.
+type lease[T any] struct {
+	b atomic.Pointer[backing[T]]
+}
+
+// release drops the claim. It is safe to call more than once.
+func (l *lease[T]) release() {
+	if b := l.b.Swap(nil); b != nil {
+		b.views.Add(-1)
+	}
+}
+
+// Slice is a view over a shared backing array, just like a regular Go slice.
+// The difference is what happens on a write: if another view still shares the
+// backing array, the writing view first copies its own window into a fresh
+// array. A write through one view therefore never shows up in another one,
+// which is exactly the surprise DAY-8's sharedList/subList1/subList2 example
+// demonstrates with plain slices.
+//
+// A view stops counting as sharing once it is garbage collected, or right
+// away when Release is called, so the remaining view can write in place
+// again. Always pass a *Slice around; copying the struct value bypasses the
+// view counting. A Slice is not safe for concurrent use.
+type Slice[T any] struct {
+	b      *backing[T]
+	lo, hi int
+	dbg    *debugState[T]
+	lease  *lease[T]
+}
+
+// New returns an empty Slice with room for capacity elements.
+func New[T any](capacity int) *Slice[T] {
+	return newView(newBacking(make([]T, 0, capacity)), 0, 0)
+}
+
+// Of returns a Slice holding a copy of items.
+// The caller keeps full ownership of the items slice passed in.
+func Of[T any](items ...T) *Slice[T] {
+	data := make([]T, len(items))
+	copy(data, items)
+	return newView(newBacking(data), 0, len(items))
+}
+
+// newView returns a view of b[lo:hi] that already holds one of b's views.
+func newView[T any](b *backing[T], lo, hi int) *Slice[T] {
+	s := &Slice[T]{b: b, lo: lo, hi: hi, lease: &lease[T]{}}
+	s.lease.b.Store(b)
+	runtime.AddCleanup(s, (*lease[T]).release, s.lease)
+	return s
+}
+
+// Len returns the number of elements visible through this view.
+func (s *Slice[T]) Len() int {
+	return s.hi - s.lo
+}
+
+// Shared reports whether another view currently shares this view's backing
+// array. The next write to a shared view will copy.
+func (s *Slice[T]) Shared() bool {
+	return s.b.views.Load() > 1
+}
+
+// Release tells the other views that this one is no longer used, so they
+// stop copying on write. The view itself becomes empty. Dropped views are
+// released by the garbage collector anyway; Release just does it now.
+func (s *Slice[T]) Release() {
+	s.lease.release()
+	s.b = newBacking[T](nil)
+	s.lease.b.Store(s.b)
+	s.lo, s.hi = 0, 0
+	s.snapshot()
+}
+
+// Get returns the element at index i. It panics if i is out of range,
+// just like indexing a regular slice.
+func (s *Slice[T]) Get(i int) T {
+	s.check()
+	s.bounds(i)
+	return s.b.data[s.lo+i]
+}
+
+// Set replaces the element at index i, copying the view first if its backing
+// array is shared.
+func (s *Slice[T]) Set(i int, v T) {
+	s.check()
+	s.bounds(i)
+	s.own(0)
+	s.b.data[s.lo+i] = v
+	s.snapshot()
+}
+
+// Append adds items to the end of this view. Unlike the builtin append, the
+// view is updated in place and other views are never affected, whether or
+// not the backing array has spare capacity.
+func (s *Slice[T]) Append(items ...T) {
+	s.check()
+	s.own(len(items))
+	// We own the backing array, so anything past hi is dead and can be reused.
+	s.b.data = append(s.b.data[:s.hi], items...)
+	s.hi += len(items)
+	s.snapshot()
+}
+
+// Sub returns a new view of the elements in [lo, hi). No data is copied
+// until either view is written to.
+func (s *Slice[T]) Sub(lo, hi int) *Slice[T] {
+	s.check()
+	if lo < 0 || hi < lo || hi > s.Len() {
+		panic(fmt.Sprintf("cowslice: slice bounds out of range [%d:%d] with length %d", lo, hi, s.Len()))
+	}
+	s.b.views.Add(1)
+	sub := newView(s.b, s.lo+lo, s.lo+hi)
+	if s.dbg != nil {
+		sub.dbg = &debugState[T]{report: s.dbg.report}
+		sub.snapshot()
+	}
+	return sub
+}
+
+// Clone returns a view of all elements. It is as cheap as Sub and the data
+// is only copied once one of the two views is written to.
+func (s *Slice[T]) Clone() *Slice[T] {
+	return s.Sub(0, s.Len())
+}
+
+// Values returns a freshly allocated copy of the visible elements.
+func (s *Slice[T]) Values() []T {
+	s.check()
+	out := make([]T, s.Len())
+	copy(out, s.b.data[s.lo:s.hi])
+	return out
+}
+
+// All iterates over the visible elements with their indexes.
+func (s *Slice[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		s.check()
+		for i := s.lo; i < s.hi; i++ {
+			if !yield(i-s.lo, s.b.data[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Raw returns the visible elements without copying. The returned slice has
+// its capacity clipped to its length, so appending to it always allocates,
+// but assigning to its elements writes straight into the backing array and
+// may be seen by other views. Enable debug mode to catch such writes.
+func (s *Slice[T]) Raw() []T {
+	s.check()
+	return s.b.data[s.lo:s.hi:s.hi]
+}
+
+// String formats the view like fmt formats a regular slice.
+func (s *Slice[T]) String() string {
+	return fmt.Sprint(s.b.data[s.lo:s.hi])
+}
+
+// own makes sure this view is the only one using its backing array before a
+// write. extra is the number of elements about to be appended and is used to
+// size the new array.
+func (s *Slice[T]) own(extra int) {
+	if s.b.views.Load() == 1 {
+		return
+	}
+	data := make([]T, s.Len(), s.Len()+extra)
+	copy(data, s.b.data[s.lo:s.hi])
+	// Detach: the remaining views may become sole owners.
+	s.lease.release()
+	s.b = newBacking(data)
+	s.lease.b.Store(s.b)
+	s.lo, s.hi = 0, len(data)
+}
+
+func (s *Slice[T]) bounds(i int) {
+	if i < 0 || i >= s.Len() {
+		panic(fmt.Sprintf("cowslice: index out of range [%d] with length %d", i, s.Len()))
+	}
+}
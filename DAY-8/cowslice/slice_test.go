@@ -0,0 +1,141 @@
+// cowslice/slice_test.go
+package cowslice_test
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"todolist/cowslice"
+)
+
+func TestWriteDoesNotLeakBetweenViews(t *testing.T) {
+	tests := []struct {
+		name       string
+		write      func(parent, sub *cowslice.Slice[string])
+		wantParent []string
+		wantSub    []string
+	}{
+		{"Set through sub", func(_, sub *cowslice.Slice[string]) { sub.Set(0, "x") },
+			[]string{"p", "q", "r"}, []string{"x", "q"}},
+		{"Set through parent", func(parent, _ *cowslice.Slice[string]) { parent.Set(0, "x") },
+			[]string{"x", "q", "r"}, []string{"p", "q"}},
+		// DAY-8's example: a plain append to the sub slice would overwrite "r".
+		{"Append through sub", func(_, sub *cowslice.Slice[string]) { sub.Append("s") },
+			[]string{"p", "q", "r"}, []string{"p", "q", "s"}},
+		{"Append through parent", func(parent, _ *cowslice.Slice[string]) { parent.Append("s") },
+			[]string{"p", "q", "r", "s"}, []string{"p", "q"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := cowslice.Of("p", "q", "r")
+			sub := parent.Sub(0, 2)
+
+			tt.write(parent, sub)
+
+			if got := parent.Values(); !slices.Equal(got, tt.wantParent) {
+				t.Errorf("parent = %v, want %v", got, tt.wantParent)
+			}
+			if got := sub.Values(); !slices.Equal(got, tt.wantSub) {
+				t.Errorf("sub = %v, want %v", got, tt.wantSub)
+			}
+		})
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	a := cowslice.Of(1, 2, 3)
+	b := a.Clone()
+	c := b.Clone()
+
+	b.Set(1, 20)
+	c.Append(4)
+
+	for _, tc := range []struct {
+		name string
+		s    *cowslice.Slice[int]
+		want []int
+	}{
+		{"a", a, []int{1, 2, 3}},
+		{"b", b, []int{1, 20, 3}},
+		{"c", c, []int{1, 2, 3, 4}},
+	} {
+		if got := tc.s.Values(); !slices.Equal(got, tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReleaseStopsSharing(t *testing.T) {
+	parent := cowslice.Of(1, 2, 3)
+	sub := parent.Sub(1, 3)
+	if !parent.Shared() {
+		t.Fatal("parent not shared after Sub")
+	}
+
+	sub.Release()
+	if parent.Shared() {
+		t.Fatal("parent still shared after sub.Release")
+	}
+	if sub.Len() != 0 {
+		t.Errorf("released sub has length %d, want 0", sub.Len())
+	}
+
+	// With the only other view gone, a write must not copy.
+	before := &parent.Raw()[0]
+	parent.Set(0, 10)
+	if &parent.Raw()[0] != before {
+		t.Error("Set copied the backing array after the other view was released")
+	}
+	sub.Release() // A second Release is harmless.
+}
+
+func TestDroppedViewStopsSharing(t *testing.T) {
+	parent := cowslice.Of(1, 2, 3)
+	func() {
+		sub := parent.Sub(0, 1)
+		sub.Get(0)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for parent.Shared() {
+		if time.Now().After(deadline) {
+			t.Fatal("parent still shared long after its only sub view was dropped")
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDebugReportsRawWrites(t *testing.T) {
+	var reports []*cowslice.AliasError
+	parent := cowslice.Of(1, 2, 3).Debug(func(err *cowslice.AliasError) { reports = append(reports, err) })
+	sub := parent.Sub(0, 2)
+
+	parent.Raw()[1] = 99 // Bypasses copy-on-write.
+	sub.Get(0)
+	sub.Get(0)
+
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want exactly 1", len(reports))
+	}
+	if err := reports[0]; err.Index != 1 || err.Want != 2 || err.Got != 99 {
+		t.Errorf("report = %v, want index 1 changed from 2 to 99", err)
+	}
+}
+
+func TestDebugPanicsWithoutReporter(t *testing.T) {
+	s := cowslice.Of(1).Debug(nil)
+	s.Raw()[0] = 2
+
+	defer func() {
+		err, _ := recover().(error)
+		var alias *cowslice.AliasError
+		if !errors.As(err, &alias) {
+			t.Errorf("recovered %v, want an *AliasError", err)
+		}
+	}()
+	s.Get(0)
+}
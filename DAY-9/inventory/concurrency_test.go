@@ -0,0 +1,237 @@
+// inventory/concurrency_test.go
+package inventory_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"inventory/inventory"
+)
+
+// These tests are meant to run under the race detector:
+//
+//	go test -race -bench . ./inventory
+
+const skuCount = 256
+
+func sku(i int) inventory.SKU {
+	return inventory.SKU(fmt.Sprintf("SKU-%03d", i%skuCount))
+}
+
+// newInventory returns an inventory with skuCount items, each holding
+// perLocation units in warehouses A and B.
+func newInventory(tb testing.TB, perLocation int) *inventory.Inventory {
+	tb.Helper()
+	inv := inventory.New()
+	for i := 0; i < skuCount; i++ {
+		if err := inv.AddItem(inventory.Item{SKU: sku(i), Name: string(sku(i))}); err != nil {
+			tb.Fatal(err)
+		}
+		if perLocation == 0 {
+			continue
+		}
+		for _, loc := range []inventory.Location{"A", "B"} {
+			if _, err := inv.StockIn(sku(i), loc, perLocation, "seed"); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return inv
+}
+
+// run starts workers goroutines running fn(worker) and waits for them.
+func run(workers int, fn func(w int)) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(w)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentTransfersConserveStock(t *testing.T) {
+	const workers, rounds, perLocation = 16, 2000, 50
+	inv := newInventory(t, perLocation)
+
+	run(workers, func(w int) {
+		for r := 0; r < rounds; r++ {
+			from, to := inventory.Location("A"), inventory.Location("B")
+			if (w+r)%2 == 0 {
+				from, to = to, from
+			}
+			_, err := inv.Transfer(sku(w*rounds+r), from, to, 1+r%7, "stress")
+			if err != nil && !errors.Is(err, inventory.ErrInsufficientStock) {
+				t.Errorf("Transfer: %v", err)
+				return
+			}
+		}
+	})
+
+	for i := 0; i < skuCount; i++ {
+		a, b := inv.Level(sku(i), "A"), inv.Level(sku(i), "B")
+		if a.OnHand < 0 || b.OnHand < 0 {
+			t.Errorf("%s went negative: A=%d B=%d", sku(i), a.OnHand, b.OnHand)
+		}
+		if a.OnHand+b.OnHand != 2*perLocation {
+			t.Errorf("%s has %d units, want %d", sku(i), a.OnHand+b.OnHand, 2*perLocation)
+		}
+	}
+}
+
+func TestConcurrentApplyIsAllOrNothing(t *testing.T) {
+	const workers, rounds = 8, 500
+	inv := newInventory(t, 0)
+	var applied atomic.Int64
+
+	// Every batch moves one unit from a shared pool SKU into two others.
+	// Only skuCount batches can succeed; the rest must change nothing.
+	pool := sku(0)
+	if _, err := inv.StockIn(pool, "A", 2*skuCount, "seed"); err != nil {
+		t.Fatal(err)
+	}
+	run(workers, func(w int) {
+		for r := 0; r < rounds; r++ {
+			n := w*rounds + r
+			_, err := inv.Apply(
+				inventory.Op{Kind: inventory.StockOut, SKU: pool, Location: "A", Quantity: 2},
+				inventory.Op{Kind: inventory.StockIn, SKU: sku(1 + n%(skuCount-1)), Location: "B", Quantity: 1},
+				inventory.Op{Kind: inventory.StockIn, SKU: sku(1 + (n+1)%(skuCount-1)), Location: "B", Quantity: 1},
+			)
+			switch {
+			case err == nil:
+				applied.Add(1)
+			case !errors.Is(err, inventory.ErrInsufficientStock):
+				t.Errorf("Apply: %v", err)
+				return
+			}
+		}
+	})
+
+	if got := applied.Load(); got != skuCount {
+		t.Errorf("%d batches applied, want %d", got, skuCount)
+	}
+	if got := inv.Level(pool, "A").OnHand; got != 0 {
+		t.Errorf("pool has %d units left, want 0", got)
+	}
+	received := 0
+	for i := 1; i < skuCount; i++ {
+		received += inv.Level(sku(i), "B").OnHand
+	}
+	if received != 2*skuCount {
+		t.Errorf("%d units received, want %d", received, 2*skuCount)
+	}
+	// One seed movement plus three per applied batch; failed batches
+	// must not leave anything in the ledger.
+	if got, want := len(inv.Ledger()), 1+3*skuCount; got != want {
+		t.Errorf("ledger has %d movements, want %d", got, want)
+	}
+}
+
+func TestConcurrentReservationsNeverOversell(t *testing.T) {
+	const workers, units = 16, 100
+	inv := inventory.New()
+	if err := inv.AddItem(inventory.Item{SKU: "HOT", Name: "Hot item"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inv.StockIn("HOT", "A", units, "seed"); err != nil {
+		t.Fatal(err)
+	}
+
+	var reserved sync.Map
+	run(workers, func(w int) {
+		for {
+			res, err := inv.Reserve("HOT", "A", 1, fmt.Sprint("order-", w))
+			if errors.Is(err, inventory.ErrInsufficientStock) {
+				return
+			}
+			if err != nil {
+				t.Errorf("Reserve: %v", err)
+				return
+			}
+			reserved.Store(res.ID, true)
+		}
+	})
+	if got := len(inv.Reservations()); got != units {
+		t.Fatalf("%d reservations, want %d", got, units)
+	}
+
+	ids := make(chan int, units)
+	reserved.Range(func(id, _ any) bool {
+		ids <- id.(int)
+		return true
+	})
+	close(ids)
+	run(workers, func(int) {
+		for id := range ids {
+			if id%2 == 0 {
+				if _, err := inv.Fulfil(id); err != nil {
+					t.Errorf("Fulfil(%d): %v", id, err)
+				}
+			} else if err := inv.Cancel(id); err != nil {
+				t.Errorf("Cancel(%d): %v", id, err)
+			}
+		}
+	})
+
+	lvl := inv.Level("HOT", "A")
+	if lvl.Reserved != 0 || lvl.OnHand != units/2 {
+		t.Errorf("level = %+v, want %d on hand and nothing reserved", lvl, units/2)
+	}
+}
+
+func BenchmarkStockIn(b *testing.B) {
+	for _, skus := range []int{1, skuCount} {
+		b.Run(benchName(skus), func(b *testing.B) {
+			inv := newInventory(b, 0)
+			var next atomic.Int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					inv.StockIn(sku(int(next.Add(1))%skus), "A", 1, "bench")
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkTransfer(b *testing.B) {
+	for _, skus := range []int{1, skuCount} {
+		b.Run(benchName(skus), func(b *testing.B) {
+			inv := newInventory(b, 1<<30)
+			var next atomic.Int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					inv.Transfer(sku(int(next.Add(1))%skus), "A", "B", 1, "bench")
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkApply3 moves three different SKUs in one atomic batch.
+func BenchmarkApply3(b *testing.B) {
+	inv := newInventory(b, 1<<30)
+	var next atomic.Int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := int(next.Add(1))
+			inv.Apply(
+				inventory.Op{Kind: inventory.StockOut, SKU: sku(n), Location: "A", Quantity: 1},
+				inventory.Op{Kind: inventory.StockOut, SKU: sku(n + 1), Location: "A", Quantity: 1},
+				inventory.Op{Kind: inventory.StockIn, SKU: sku(n + 2), Location: "B", Quantity: 2},
+			)
+		}
+	})
+}
+
+func benchName(skus int) string {
+	if skus == 1 {
+		return "OneSKU"
+	}
+	return "ManySKUs"
+}
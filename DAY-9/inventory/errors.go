@@ -0,0 +1,81 @@
+// inventory/errors.go
+package inventory
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors. Check for them with errors.Is; the typed errors below
+// wrap them so both styles work.
+var (
+	ErrUnknownItem        = errors.New("inventory: unknown item")
+	ErrDuplicateItem      = errors.New("inventory: item already exists")
+	ErrInvalidQuantity    = errors.New("inventory: quantity must be positive")
+	ErrInsufficientStock  = errors.New("inventory: insufficient stock")
+	ErrUnknownReservation = errors.New("inventory: unknown reservation")
+	ErrUnsupportedKind    = errors.New("inventory: unsupported movement kind")
+)
+
+// ItemError reports an operation on a SKU that is not registered, or one
+// that is registered twice. It wraps ErrUnknownItem or ErrDuplicateItem.
+type ItemError struct {
+	SKU SKU
+	Err error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.SKU)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// QuantityError reports a movement with a zero or negative quantity.
+// It wraps ErrInvalidQuantity.
+type QuantityError struct {
+	SKU      SKU
+	Quantity int
+}
+
+func (e *QuantityError) Error() string {
+	return fmt.Sprintf("%v: %d units of %s", ErrInvalidQuantity, e.Quantity, e.SKU)
+}
+
+func (e *QuantityError) Unwrap() error {
+	return ErrInvalidQuantity
+}
+
+// StockError reports a movement that would make stock at a location go
+// negative. Available is what could still be taken out, i.e. the units on
+// hand minus the units already reserved. It wraps ErrInsufficientStock.
+type StockError struct {
+	SKU       SKU
+	Location  Location
+	Requested int
+	Available int
+}
+
+func (e *StockError) Error() string {
+	return fmt.Sprintf("%v: requested %d units of %s at %s, only %d available",
+		ErrInsufficientStock, e.Requested, e.SKU, e.Location, e.Available)
+}
+
+func (e *StockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+// KindError reports an Apply op whose kind is neither StockIn nor StockOut.
+// It wraps ErrUnsupportedKind.
+type KindError struct {
+	Kind MovementKind
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrUnsupportedKind, e.Kind)
+}
+
+func (e *KindError) Unwrap() error {
+	return ErrUnsupportedKind
+}
@@ -0,0 +1,284 @@
+// inventory/inventory.go
+package inventory // SKU-keyed stock across warehouse locations, with a ledger
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SKU (stock keeping unit) uniquely identifies an item, e.g. "MOUSE-USB-01".
+type SKU string
+
+// Location names a warehouse or any other place stock can sit.
+type Location string
+
+// Item describes something we keep in stock. LowStock is the threshold at or
+// below which the item shows up in LowStockReport; zero disables the alert.
+type Item struct {
+	SKU       SKU
+	Name      string
+	UnitPrice float64
+	LowStock  int
+}
+
+// Level is the stock of one SKU at one location. OnHand counts the units
+// physically there; Reserved counts those already promised to someone.
+type Level struct {
+	OnHand   int
+	Reserved int
+}
+
+// Available is what can still be reserved or shipped.
+func (l Level) Available() int {
+	return l.OnHand - l.Reserved
+}
+
+// Reservation holds units at a location until they are shipped with Fulfil
+// or handed back with Cancel.
+type Reservation struct {
+	ID        int
+	SKU       SKU
+	Location  Location
+	Quantity  int
+	Reference string
+}
+
+// LowStockAlert is one line of LowStockReport.
+type LowStockAlert struct {
+	Item      Item
+	Available int // Across all locations.
+}
+
+// Inventory keeps items, their stock per location, open reservations and a
+// ledger of every movement. Stock never goes negative: a movement that would
+// take out more than is available fails with a *StockError and changes
+// nothing.
+//
+// An Inventory is safe for concurrent use. Items are spread over shards, each
+// with its own lock, so goroutines working on different SKUs rarely wait for
+// each other; see Apply for updates that touch several SKUs at once.
+//
+// The zero value is not usable; create one with New.
+type Inventory struct {
+	shards [shardCount]shard
+
+	resMu        sync.Mutex
+	reservations map[int]Reservation
+	nextResID    int
+
+	ledger ledger
+
+	// Now returns the time stamped on ledger entries. It defaults to
+	// time.Now and can be replaced to get reproducible output. Set it before
+	// the Inventory is shared between goroutines.
+	Now func() time.Time
+}
+
+// New returns an empty Inventory.
+func New() *Inventory {
+	inv := &Inventory{
+		reservations: make(map[int]Reservation),
+		Now:          time.Now,
+	}
+	for i := range inv.shards {
+		inv.shards[i].items = make(map[SKU]Item)
+		inv.shards[i].levels = make(map[SKU]map[Location]Level)
+	}
+	return inv
+}
+
+// AddItem registers a new item. Stock can only be moved for registered items.
+func (inv *Inventory) AddItem(item Item) error {
+	sh := inv.shard(item.SKU)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if _, ok := sh.items[item.SKU]; ok {
+		return &ItemError{SKU: item.SKU, Err: ErrDuplicateItem}
+	}
+	sh.items[item.SKU] = item
+	sh.levels[item.SKU] = make(map[Location]Level)
+	return nil
+}
+
+// Item looks up an item by SKU using the comma ok idiom.
+func (inv *Inventory) Item(sku SKU) (Item, bool) {
+	sh := inv.shard(sku)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	item, ok := sh.items[sku]
+	return item, ok
+}
+
+// SetLowStock changes the low-stock threshold of an item.
+func (inv *Inventory) SetLowStock(sku SKU, threshold int) error {
+	sh := inv.shard(sku)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	item, ok := sh.items[sku]
+	if !ok {
+		return &ItemError{SKU: sku, Err: ErrUnknownItem}
+	}
+	item.LowStock = threshold
+	sh.items[sku] = item
+	return nil
+}
+
+// Level returns the stock of sku at loc. Unknown combinations report zero.
+func (inv *Inventory) Level(sku SKU, loc Location) Level {
+	sh := inv.shard(sku)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.levels[sku][loc]
+}
+
+// Available returns the units of sku that can still be reserved or shipped
+// across all locations.
+func (inv *Inventory) Available(sku SKU) int {
+	sh := inv.shard(sku)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.available(sku)
+}
+
+// StockIn records units arriving at a location.
+func (inv *Inventory) StockIn(sku SKU, loc Location, qty int, ref string) (Movement, error) {
+	moves, err := inv.Apply(Op{Kind: StockIn, SKU: sku, Location: loc, Quantity: qty, Reference: ref})
+	if err != nil {
+		return Movement{}, err
+	}
+	return moves[0], nil
+}
+
+// StockOut records units leaving a location. Reserved units can't be taken
+// this way; ship them with Fulfil instead.
+func (inv *Inventory) StockOut(sku SKU, loc Location, qty int, ref string) (Movement, error) {
+	moves, err := inv.Apply(Op{Kind: StockOut, SKU: sku, Location: loc, Quantity: qty, Reference: ref})
+	if err != nil {
+		return Movement{}, err
+	}
+	return moves[0], nil
+}
+
+// Reserve sets units aside at a location. They stay on hand but are no
+// longer available to anyone else.
+func (inv *Inventory) Reserve(sku SKU, loc Location, qty int, ref string) (Reservation, error) {
+	sh := inv.shard(sku)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	lvl, err := sh.level(sku, qty)
+	if err != nil {
+		return Reservation{}, err
+	}
+	cur := lvl[loc]
+	if cur.Available() < qty {
+		return Reservation{}, &StockError{SKU: sku, Location: loc, Requested: qty, Available: cur.Available()}
+	}
+	cur.Reserved += qty
+	lvl[loc] = cur
+
+	inv.resMu.Lock()
+	inv.nextResID++
+	res := Reservation{ID: inv.nextResID, SKU: sku, Location: loc, Quantity: qty, Reference: ref}
+	inv.reservations[res.ID] = res
+	inv.resMu.Unlock()
+
+	inv.ledger.record(inv.Now(), Op{Kind: Reserve, SKU: sku, Location: loc, Quantity: qty, Reference: ref})
+	return res, nil
+}
+
+// Cancel releases a reservation, making its units available again.
+func (inv *Inventory) Cancel(id int) error {
+	res, ok := inv.takeReservation(id)
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownReservation, id)
+	}
+
+	sh := inv.shard(res.SKU)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	lvl := sh.levels[res.SKU]
+	cur := lvl[res.Location]
+	cur.Reserved -= res.Quantity
+	lvl[res.Location] = cur
+	inv.ledger.record(inv.Now(), Op{Kind: Release, SKU: res.SKU, Location: res.Location, Quantity: res.Quantity, Reference: res.Reference})
+	return nil
+}
+
+// Fulfil ships the units of a reservation, recording a stock-out.
+func (inv *Inventory) Fulfil(id int) (Movement, error) {
+	res, ok := inv.takeReservation(id)
+	if !ok {
+		return Movement{}, fmt.Errorf("%w: %d", ErrUnknownReservation, id)
+	}
+
+	sh := inv.shard(res.SKU)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	lvl := sh.levels[res.SKU]
+	cur := lvl[res.Location]
+	cur.Reserved -= res.Quantity
+	cur.OnHand -= res.Quantity
+	lvl[res.Location] = cur
+	moves := inv.ledger.record(inv.Now(), Op{Kind: StockOut, SKU: res.SKU, Location: res.Location, Quantity: res.Quantity, Reference: res.Reference})
+	return moves[0], nil
+}
+
+// Reservations returns the open reservations, oldest first.
+func (inv *Inventory) Reservations() []Reservation {
+	inv.resMu.Lock()
+	defer inv.resMu.Unlock()
+
+	out := make([]Reservation, 0, len(inv.reservations))
+	for _, res := range inv.reservations {
+		out = append(out, res)
+	}
+	slices.SortFunc(out, func(a, b Reservation) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+// Ledger returns a copy of every movement recorded so far, oldest first.
+func (inv *Inventory) Ledger() []Movement {
+	return inv.ledger.all()
+}
+
+// LowStockReport lists the items whose available stock, summed over all
+// locations, is at or below their LowStock threshold, sorted by SKU.
+func (inv *Inventory) LowStockReport() []LowStockAlert {
+	var alerts []LowStockAlert
+	for i := range inv.shards {
+		sh := &inv.shards[i]
+		sh.mu.Lock()
+		for sku, item := range sh.items {
+			if item.LowStock <= 0 {
+				continue
+			}
+			if avail := sh.available(sku); avail <= item.LowStock {
+				alerts = append(alerts, LowStockAlert{Item: item, Available: avail})
+			}
+		}
+		sh.mu.Unlock()
+	}
+	slices.SortFunc(alerts, func(a, b LowStockAlert) int { return cmp.Compare(a.Item.SKU, b.Item.SKU) })
+	return alerts
+}
+
+// takeReservation removes and returns an open reservation.
+func (inv *Inventory) takeReservation(id int) (Reservation, bool) {
+	inv.resMu.Lock()
+	defer inv.resMu.Unlock()
+
+	res, ok := inv.reservations[id]
+	delete(inv.reservations, id)
+	return res, ok
+}
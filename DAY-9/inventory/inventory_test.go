@@ -0,0 +1,260 @@
+// inventory/inventory_test.go
+package inventory_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"inventory/inventory"
+)
+
+var stamp = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// stocked returns an inventory with a mouse (10 units in A, LowStock 5) and
+// a keyboard (no stock), stamping the ledger with a fixed time.
+func stocked(t *testing.T) *inventory.Inventory {
+	t.Helper()
+	inv := inventory.New()
+	inv.Now = func() time.Time { return stamp }
+	for _, item := range []inventory.Item{
+		{SKU: "MOUSE", Name: "Mouse", UnitPrice: 19.99, LowStock: 5},
+		{SKU: "KEYB", Name: "Keyboard", UnitPrice: 49.5},
+	} {
+		if err := inv.AddItem(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := inv.StockIn("MOUSE", "A", 10, "delivery 1"); err != nil {
+		t.Fatal(err)
+	}
+	return inv
+}
+
+func TestAddItemDuplicate(t *testing.T) {
+	inv := stocked(t)
+	err := inv.AddItem(inventory.Item{SKU: "MOUSE"})
+	var ie *inventory.ItemError
+	if !errors.As(err, &ie) || ie.SKU != "MOUSE" || !errors.Is(err, inventory.ErrDuplicateItem) {
+		t.Fatalf("AddItem duplicate = %v, want *ItemError wrapping ErrDuplicateItem", err)
+	}
+	if item, ok := inv.Item("MOUSE"); !ok || item.Name != "Mouse" {
+		t.Errorf("Item(MOUSE) = %+v, %v; the duplicate must not replace it", item, ok)
+	}
+}
+
+func TestMovementErrors(t *testing.T) {
+	inv := stocked(t)
+	tests := []struct {
+		name   string
+		do     func() error
+		target error
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name:   "unknown item",
+			do:     func() error { _, err := inv.StockIn("NOPE", "A", 1, ""); return err },
+			target: inventory.ErrUnknownItem,
+			check: func(t *testing.T, err error) {
+				var e *inventory.ItemError
+				if !errors.As(err, &e) || e.SKU != "NOPE" {
+					t.Errorf("err = %#v, want *ItemError for NOPE", err)
+				}
+			},
+		},
+		{
+			name:   "zero quantity",
+			do:     func() error { _, err := inv.StockOut("MOUSE", "A", 0, ""); return err },
+			target: inventory.ErrInvalidQuantity,
+			check: func(t *testing.T, err error) {
+				var e *inventory.QuantityError
+				if !errors.As(err, &e) || e.Quantity != 0 {
+					t.Errorf("err = %#v, want *QuantityError with Quantity 0", err)
+				}
+			},
+		},
+		{
+			name:   "insufficient stock",
+			do:     func() error { _, err := inv.StockOut("MOUSE", "A", 11, ""); return err },
+			target: inventory.ErrInsufficientStock,
+			check: func(t *testing.T, err error) {
+				var e *inventory.StockError
+				want := inventory.StockError{SKU: "MOUSE", Location: "A", Requested: 11, Available: 10}
+				if !errors.As(err, &e) || *e != want {
+					t.Errorf("err = %#v, want %#v", err, &want)
+				}
+			},
+		},
+		{
+			name:   "unsupported kind",
+			do:     func() error { _, err := inv.Apply(inventory.Op{Kind: inventory.Reserve, SKU: "MOUSE"}); return err },
+			target: inventory.ErrUnsupportedKind,
+			check: func(t *testing.T, err error) {
+				var e *inventory.KindError
+				if !errors.As(err, &e) || e.Kind != inventory.Reserve {
+					t.Errorf("err = %#v, want *KindError for reserve", err)
+				}
+			},
+		},
+		{
+			name:   "unknown reservation",
+			do:     func() error { return inv.Cancel(42) },
+			target: inventory.ErrUnknownReservation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.do()
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("err = %v, want errors.Is %v", err, tt.target)
+			}
+			if tt.check != nil {
+				tt.check(t, err)
+			}
+		})
+	}
+	if got := inv.Level("MOUSE", "A"); got != (inventory.Level{OnHand: 10}) {
+		t.Errorf("failed movements changed the stock: %+v", got)
+	}
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	inv := stocked(t)
+	_, err := inv.Apply(
+		inventory.Op{Kind: inventory.StockIn, SKU: "KEYB", Location: "A", Quantity: 3},
+		inventory.Op{Kind: inventory.StockOut, SKU: "MOUSE", Location: "A", Quantity: 99},
+	)
+	if !errors.Is(err, inventory.ErrInsufficientStock) {
+		t.Fatalf("Apply = %v, want ErrInsufficientStock", err)
+	}
+	if got := inv.Available("KEYB"); got != 0 {
+		t.Errorf("Available(KEYB) = %d after a failed batch, want 0", got)
+	}
+	if got := len(inv.Ledger()); got != 1 {
+		t.Errorf("ledger has %d entries after a failed batch, want 1", got)
+	}
+}
+
+func TestReservations(t *testing.T) {
+	inv := stocked(t)
+	r1, err := inv.Reserve("MOUSE", "A", 4, "order 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := inv.Reserve("MOUSE", "A", 3, "order 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := inv.Level("MOUSE", "A"), (inventory.Level{OnHand: 10, Reserved: 7}); got != want {
+		t.Fatalf("Level = %+v, want %+v", got, want)
+	}
+
+	// Reserved units are neither reservable nor shippable.
+	if _, err := inv.Reserve("MOUSE", "A", 4, "order 3"); !errors.Is(err, inventory.ErrInsufficientStock) {
+		t.Errorf("Reserve over available = %v, want ErrInsufficientStock", err)
+	}
+	if _, err := inv.StockOut("MOUSE", "A", 4, "walk-in"); !errors.Is(err, inventory.ErrInsufficientStock) {
+		t.Errorf("StockOut of reserved units = %v, want ErrInsufficientStock", err)
+	}
+
+	if got := inv.Reservations(); !slices.Equal(got, []inventory.Reservation{r1, r2}) {
+		t.Errorf("Reservations = %+v, want %+v", got, []inventory.Reservation{r1, r2})
+	}
+
+	if err := inv.Cancel(r1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Cancel(r1.ID); !errors.Is(err, inventory.ErrUnknownReservation) {
+		t.Errorf("second Cancel = %v, want ErrUnknownReservation", err)
+	}
+	m, err := inv.Fulfil(r2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Kind != inventory.StockOut || m.Quantity != 3 || m.Reference != "order 2" {
+		t.Errorf("Fulfil movement = %+v", m)
+	}
+	if got, want := inv.Level("MOUSE", "A"), (inventory.Level{OnHand: 7}); got != want {
+		t.Errorf("Level after cancel and fulfil = %+v, want %+v", got, want)
+	}
+	if got := inv.Reservations(); len(got) != 0 {
+		t.Errorf("Reservations = %+v, want none", got)
+	}
+}
+
+func TestLedger(t *testing.T) {
+	inv := stocked(t)
+	res, err := inv.Reserve("MOUSE", "A", 2, "order 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Cancel(res.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inv.Transfer("MOUSE", "A", "B", 6, "rebalance"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []inventory.Movement{
+		{ID: 1, Kind: inventory.StockIn, SKU: "MOUSE", Location: "A", Quantity: 10, Reference: "delivery 1", At: stamp},
+		{ID: 2, Kind: inventory.Reserve, SKU: "MOUSE", Location: "A", Quantity: 2, Reference: "order 1", At: stamp},
+		{ID: 3, Kind: inventory.Release, SKU: "MOUSE", Location: "A", Quantity: 2, Reference: "order 1", At: stamp},
+		{ID: 4, Kind: inventory.StockOut, SKU: "MOUSE", Location: "A", Quantity: 6, Reference: "rebalance", At: stamp},
+		{ID: 5, Kind: inventory.StockIn, SKU: "MOUSE", Location: "B", Quantity: 6, Reference: "rebalance", At: stamp},
+	}
+	got := inv.Ledger()
+	if !slices.Equal(got, want) {
+		t.Fatalf("Ledger =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// The ledger is a copy; changing it doesn't rewrite history.
+	got[0].Quantity = 1000
+	if inv.Ledger()[0].Quantity != 10 {
+		t.Error("changing the returned ledger changed the inventory's")
+	}
+}
+
+func TestLowStockReport(t *testing.T) {
+	inv := stocked(t)
+	if err := inv.SetLowStock("KEYB", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.SetLowStock("NOPE", 2); !errors.Is(err, inventory.ErrUnknownItem) {
+		t.Errorf("SetLowStock(NOPE) = %v, want ErrUnknownItem", err)
+	}
+
+	// MOUSE has 10 available against a threshold of 5; KEYB has none.
+	skus := func() []inventory.SKU {
+		var out []inventory.SKU
+		for _, a := range inv.LowStockReport() {
+			out = append(out, a.Item.SKU)
+		}
+		return out
+	}
+	if got := skus(); !slices.Equal(got, []inventory.SKU{"KEYB"}) {
+		t.Fatalf("LowStockReport = %v, want [KEYB]", got)
+	}
+
+	// Reserved units don't count as available, and stock at every location
+	// is summed.
+	if _, err := inv.Reserve("MOUSE", "A", 6, "order"); err != nil {
+		t.Fatal(err)
+	}
+	if got := skus(); !slices.Equal(got, []inventory.SKU{"KEYB", "MOUSE"}) {
+		t.Fatalf("LowStockReport = %v, want [KEYB MOUSE]", got)
+	}
+	if _, err := inv.StockIn("MOUSE", "B", 1, "delivery 2"); err != nil {
+		t.Fatal(err)
+	}
+	report := inv.LowStockReport()
+	if len(report) != 2 || report[1].Available != 5 {
+		t.Fatalf("LowStockReport = %+v, want MOUSE with 5 available", report)
+	}
+	if _, err := inv.StockIn("MOUSE", "B", 1, "delivery 3"); err != nil {
+		t.Fatal(err)
+	}
+	if got := skus(); !slices.Equal(got, []inventory.SKU{"KEYB"}) {
+		t.Errorf("LowStockReport = %v, want [KEYB]", got)
+	}
+}
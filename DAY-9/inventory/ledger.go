@@ -0,0 +1,81 @@
+// inventory/ledger.go
+package inventory
+
+import (
+	"sync"
+	"time"
+)
+
+// MovementKind says what a ledger entry did to the stock.
+type MovementKind int
+
+const (
+	StockIn  MovementKind = iota + 1 // Units arrived at a location.
+	StockOut                         // Units left a location.
+	Reserve                          // Units were set aside for an order.
+	Release                          // A reservation was cancelled.
+)
+
+func (k MovementKind) String() string {
+	switch k {
+	case StockIn:
+		return "stock-in"
+	case StockOut:
+		return "stock-out"
+	case Reserve:
+		return "reserve"
+	case Release:
+		return "release"
+	}
+	return "unknown"
+}
+
+// Movement is one entry in the ledger. Entries are never changed or removed
+// once recorded, so the ledger is the full history of every SKU.
+type Movement struct {
+	ID        int
+	Kind      MovementKind
+	SKU       SKU
+	Location  Location
+	Quantity  int
+	Reference string // Free-form: an order number, a delivery note, ...
+	At        time.Time
+}
+
+// ledger is an append-only list of movements, safe for concurrent use.
+type ledger struct {
+	mu      sync.Mutex
+	entries []Movement
+}
+
+// record appends one movement per op, all stamped with the same time and
+// numbered consecutively.
+func (l *ledger) record(at time.Time, ops ...Op) []Movement {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]Movement, len(ops))
+	for i, op := range ops {
+		out[i] = Movement{
+			ID:        len(l.entries) + 1,
+			Kind:      op.Kind,
+			SKU:       op.SKU,
+			Location:  op.Location,
+			Quantity:  op.Quantity,
+			Reference: op.Reference,
+			At:        at,
+		}
+		l.entries = append(l.entries, out[i])
+	}
+	return out
+}
+
+// all returns a copy so callers can't rewrite history.
+func (l *ledger) all() []Movement {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]Movement, len(l.entries))
+	copy(out, l.entries)
+	return out
+}
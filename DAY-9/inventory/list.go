@@ -0,0 +1,105 @@
+// inventory/list.go
+package inventory
+
+import (
+	"cmp"
+	"slices"
+)
+
+// StockLine is the stock of one item summed over all locations.
+type StockLine struct {
+	Item      Item
+	OnHand    int
+	Reserved  int
+	Available int
+	Value     float64 // OnHand * Item.UnitPrice.
+}
+
+// SortKey selects the order of List results.
+type SortKey int
+
+const (
+	BySKU      SortKey = iota // The default.
+	ByName                    // Item name, then SKU.
+	ByQuantity                // Units on hand, then SKU.
+	ByValue                   // Stock value, then SKU.
+)
+
+// Query controls List. The zero Query returns every item sorted by SKU.
+type Query struct {
+	SortBy     SortKey
+	Descending bool
+	Offset     int // Number of lines to skip.
+	Limit      int // Maximum number of lines; zero means no limit.
+}
+
+// Page is one page of List results. Total counts all lines, not just the
+// ones on this page, and Offset is the index of the first line on it: the
+// query's offset, clamped to [0, Total].
+type Page struct {
+	Lines  []StockLine
+	Total  int
+	Offset int
+	Limit  int
+}
+
+// HasMore reports whether there are lines after this page.
+func (p Page) HasMore() bool {
+	return p.Offset+len(p.Lines) < p.Total
+}
+
+// List returns the stock of every item in a fixed order. Ranging over a map
+// visits keys in a different order on every run; List sorts, and ties are
+// always broken by SKU, so the same inventory always lists the same way.
+func (inv *Inventory) List(q Query) Page {
+	lines := inv.stockLines()
+
+	var key func(a, b StockLine) int
+	switch q.SortBy {
+	case ByName:
+		key = func(a, b StockLine) int { return cmp.Compare(a.Item.Name, b.Item.Name) }
+	case ByQuantity:
+		key = func(a, b StockLine) int { return cmp.Compare(a.OnHand, b.OnHand) }
+	case ByValue:
+		key = func(a, b StockLine) int { return cmp.Compare(a.Value, b.Value) }
+	default:
+		key = func(a, b StockLine) int { return 0 }
+	}
+	slices.SortFunc(lines, func(a, b StockLine) int {
+		c := cmp.Or(key(a, b), cmp.Compare(a.Item.SKU, b.Item.SKU))
+		if q.Descending {
+			return -c
+		}
+		return c
+	})
+
+	start := min(max(q.Offset, 0), len(lines))
+	page := Page{Total: len(lines), Offset: start, Limit: q.Limit}
+	end := len(lines)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, len(lines))
+	}
+	page.Lines = lines[start:end]
+	return page
+}
+
+// stockLines collects one line per item, in no particular order.
+func (inv *Inventory) stockLines() []StockLine {
+	var lines []StockLine
+	for i := range inv.shards {
+		sh := &inv.shards[i]
+		sh.mu.Lock()
+		for sku, item := range sh.items {
+			line := StockLine{Item: item}
+			for _, lvl := range sh.levels[sku] {
+				line.OnHand += lvl.OnHand
+				line.Reserved += lvl.Reserved
+			}
+			line.Available = line.OnHand - line.Reserved
+			line.Value = float64(line.OnHand) * item.UnitPrice
+			lines = append(lines, line)
+		}
+		sh.mu.Unlock()
+	}
+	return lines
+}
@@ -0,0 +1,185 @@
+// inventory/list_test.go
+package inventory_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"inventory/inventory"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// catalog returns an inventory whose items tie on name, quantity and value
+// in places, so the tests see how ties are broken.
+func catalog(t *testing.T) *inventory.Inventory {
+	t.Helper()
+	inv := inventory.New()
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	inv.Now = func() time.Time { return day }
+	stock := []struct {
+		item inventory.Item
+		qty  int
+	}{
+		{inventory.Item{SKU: "CABLE-HDMI", Name: "Cable", UnitPrice: 5}, 20},
+		{inventory.Item{SKU: "CABLE-USB", Name: "Cable", UnitPrice: 2.5}, 40},
+		{inventory.Item{SKU: "KEYB-01", Name: "Keyboard", UnitPrice: 49.99}, 2},
+		{inventory.Item{SKU: "MOUSE-01", Name: "Mouse", UnitPrice: 19.99}, 5},
+		{inventory.Item{SKU: "PAD-01", Name: "Mouse pad", UnitPrice: 4}, 0},
+	}
+	for _, s := range stock {
+		if err := inv.AddItem(s.item); err != nil {
+			t.Fatal(err)
+		}
+		if s.qty > 0 {
+			if _, err := inv.StockIn(s.item.SKU, "A", s.qty, "opening"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	day = day.Add(24 * time.Hour)
+	if _, err := inv.Reserve("MOUSE-01", "A", 1, "order 7"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inv.Transfer("CABLE-USB", "A", "B", 15, "rebalance"); err != nil {
+		t.Fatal(err)
+	}
+	return inv
+}
+
+func skus(lines []inventory.StockLine) []inventory.SKU {
+	var out []inventory.SKU
+	for _, l := range lines {
+		out = append(out, l.Item.SKU)
+	}
+	return out
+}
+
+func TestListOrder(t *testing.T) {
+	inv := catalog(t)
+	tests := []struct {
+		name string
+		q    inventory.Query
+		want []inventory.SKU
+	}{
+		{"sku", inventory.Query{},
+			[]inventory.SKU{"CABLE-HDMI", "CABLE-USB", "KEYB-01", "MOUSE-01", "PAD-01"}},
+		{"name", inventory.Query{SortBy: inventory.ByName},
+			[]inventory.SKU{"CABLE-HDMI", "CABLE-USB", "KEYB-01", "MOUSE-01", "PAD-01"}},
+		{"quantity", inventory.Query{SortBy: inventory.ByQuantity},
+			[]inventory.SKU{"PAD-01", "KEYB-01", "MOUSE-01", "CABLE-HDMI", "CABLE-USB"}},
+		// CABLE-HDMI and CABLE-USB are both worth 100.
+		{"value", inventory.Query{SortBy: inventory.ByValue},
+			[]inventory.SKU{"PAD-01", "MOUSE-01", "KEYB-01", "CABLE-HDMI", "CABLE-USB"}},
+		{"value descending", inventory.Query{SortBy: inventory.ByValue, Descending: true},
+			[]inventory.SKU{"CABLE-USB", "CABLE-HDMI", "KEYB-01", "MOUSE-01", "PAD-01"}},
+		{"name descending", inventory.Query{SortBy: inventory.ByName, Descending: true},
+			[]inventory.SKU{"PAD-01", "MOUSE-01", "KEYB-01", "CABLE-USB", "CABLE-HDMI"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map order changes from run to run; the list must not.
+			for range 5 {
+				if got := skus(inv.List(tt.q).Lines); !slices.Equal(got, tt.want) {
+					t.Fatalf("List = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListPages(t *testing.T) {
+	inv := catalog(t)
+	tests := []struct {
+		name    string
+		q       inventory.Query
+		want    []inventory.SKU
+		offset  int
+		hasMore bool
+	}{
+		{"first", inventory.Query{Limit: 2}, []inventory.SKU{"CABLE-HDMI", "CABLE-USB"}, 0, true},
+		{"middle", inventory.Query{Offset: 2, Limit: 2}, []inventory.SKU{"KEYB-01", "MOUSE-01"}, 2, true},
+		{"last", inventory.Query{Offset: 4, Limit: 2}, []inventory.SKU{"PAD-01"}, 4, false},
+		{"past the end", inventory.Query{Offset: 9, Limit: 2}, nil, 5, false},
+		{"negative offset", inventory.Query{Offset: -3}, []inventory.SKU{"CABLE-HDMI", "CABLE-USB", "KEYB-01", "MOUSE-01", "PAD-01"}, 0, false},
+		{"negative offset with limit", inventory.Query{Offset: -3, Limit: 5}, []inventory.SKU{"CABLE-HDMI", "CABLE-USB", "KEYB-01", "MOUSE-01", "PAD-01"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := inv.List(tt.q)
+			if got := skus(page.Lines); !slices.Equal(got, tt.want) {
+				t.Errorf("Lines = %v, want %v", got, tt.want)
+			}
+			if page.Total != 5 || page.Offset != tt.offset {
+				t.Errorf("Total, Offset = %d, %d, want 5, %d", page.Total, page.Offset, tt.offset)
+			}
+			if page.HasMore() != tt.hasMore {
+				t.Errorf("HasMore = %v, want %v", page.HasMore(), tt.hasMore)
+			}
+		})
+	}
+}
+
+func TestReportGolden(t *testing.T) {
+	inv := catalog(t)
+	reports := []struct {
+		name   string
+		report inventory.Report
+	}{
+		{"valuation", inv.Valuation()},
+		{"movements", inv.MovementSummary(time.Time{}, time.Time{})},
+		{"movements-day2", inv.MovementSummary(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), time.Time{})},
+	}
+	formats := []struct {
+		ext    string
+		format inventory.Format
+	}{
+		{"txt", inventory.Text},
+		{"csv", inventory.CSV},
+		{"json", inventory.JSON},
+	}
+	for _, r := range reports {
+		for _, f := range formats {
+			name := r.name + "." + f.ext
+			t.Run(name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := inventory.WriteReport(&buf, r.report, f.format); err != nil {
+					t.Fatal(err)
+				}
+				golden(t, name, buf.Bytes())
+			})
+		}
+	}
+}
+
+func TestWriteReportUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := inventory.WriteReport(&buf, inventory.Valuation{}, inventory.Format(99)); err == nil {
+		t.Error("WriteReport with an unknown format succeeded")
+	}
+}
+
+// golden compares got with testdata/name, or rewrites the file when the
+// test runs with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file:\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
@@ -0,0 +1,166 @@
+// inventory/report.go
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Format selects how WriteReport renders a report.
+type Format int
+
+const (
+	Text Format = iota // Aligned columns for people.
+	CSV                // Comma-separated values with a header row.
+	JSON               // The report struct, indented.
+)
+
+// Report is implemented by every report in this package.
+type Report interface {
+	// table returns the column headers and rows used by the Text and CSV
+	// formats. JSON marshals the report value itself.
+	table() (headers []string, rows [][]string)
+}
+
+// WriteReport renders r to w in the given format.
+func WriteReport(w io.Writer, r Report, f Format) error {
+	switch f {
+	case Text:
+		headers, rows := r.table()
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, row := range append([][]string{headers}, rows...) {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case CSV:
+		headers, rows := r.table()
+		cw := csv.NewWriter(w)
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return fmt.Errorf("inventory: unknown report format %d", f)
+}
+
+// ValuationLine is the value of the stock on hand of one item.
+type ValuationLine struct {
+	SKU       SKU     `json:"sku"`
+	Name      string  `json:"name"`
+	OnHand    int     `json:"on_hand"`
+	UnitPrice float64 `json:"unit_price"`
+	Value     float64 `json:"value"`
+}
+
+// Valuation is what the stock on hand is worth, item by item, sorted by SKU.
+type Valuation struct {
+	Lines []ValuationLine `json:"lines"`
+	Total float64         `json:"total"`
+}
+
+// Valuation builds a stock valuation report.
+func (inv *Inventory) Valuation() Valuation {
+	var v Valuation
+	for _, line := range inv.List(Query{}).Lines {
+		v.Lines = append(v.Lines, ValuationLine{
+			SKU:       line.Item.SKU,
+			Name:      line.Item.Name,
+			OnHand:    line.OnHand,
+			UnitPrice: line.Item.UnitPrice,
+			Value:     line.Value,
+		})
+		v.Total += line.Value
+	}
+	return v
+}
+
+func (v Valuation) table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(v.Lines)+1)
+	for _, l := range v.Lines {
+		rows = append(rows, []string{string(l.SKU), l.Name, strconv.Itoa(l.OnHand), money(l.UnitPrice), money(l.Value)})
+	}
+	rows = append(rows, []string{"TOTAL", "", "", "", money(v.Total)})
+	return []string{"SKU", "Name", "On hand", "Unit price", "Value"}, rows
+}
+
+// MovementTotals sums the ledger entries of one SKU by kind. Net is the
+// change in units on hand: stock-ins minus stock-outs.
+type MovementTotals struct {
+	SKU      SKU `json:"sku"`
+	In       int `json:"in"`
+	Out      int `json:"out"`
+	Reserved int `json:"reserved"`
+	Released int `json:"released"`
+	Net      int `json:"net"`
+}
+
+// MovementSummary totals the ledger per SKU over a period, sorted by SKU.
+type MovementSummary struct {
+	From  time.Time        `json:"from,omitzero"`
+	To    time.Time        `json:"to,omitzero"`
+	Lines []MovementTotals `json:"lines"`
+}
+
+// MovementSummary builds a movement summary for entries stamped in
+// [from, to). A zero from or to leaves that end of the period open.
+func (inv *Inventory) MovementSummary(from, to time.Time) MovementSummary {
+	totals := make(map[SKU]*MovementTotals)
+	var skus []SKU
+	for _, m := range inv.Ledger() {
+		if (!from.IsZero() && m.At.Before(from)) || (!to.IsZero() && !m.At.Before(to)) {
+			continue
+		}
+		t, ok := totals[m.SKU]
+		if !ok {
+			t = &MovementTotals{SKU: m.SKU}
+			totals[m.SKU] = t
+			skus = append(skus, m.SKU)
+		}
+		switch m.Kind {
+		case StockIn:
+			t.In += m.Quantity
+			t.Net += m.Quantity
+		case StockOut:
+			t.Out += m.Quantity
+			t.Net -= m.Quantity
+		case Reserve:
+			t.Reserved += m.Quantity
+		case Release:
+			t.Released += m.Quantity
+		}
+	}
+
+	slices.Sort(skus)
+	s := MovementSummary{From: from, To: to}
+	for _, sku := range skus {
+		s.Lines = append(s.Lines, *totals[sku])
+	}
+	return s
+}
+
+func (s MovementSummary) table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(s.Lines))
+	for _, l := range s.Lines {
+		rows = append(rows, []string{string(l.SKU), strconv.Itoa(l.In), strconv.Itoa(l.Out),
+			strconv.Itoa(l.Reserved), strconv.Itoa(l.Released), strconv.Itoa(l.Net)})
+	}
+	return []string{"SKU", "In", "Out", "Reserved", "Released", "Net"}, rows
+}
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
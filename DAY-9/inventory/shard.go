@@ -0,0 +1,135 @@
+// inventory/shard.go
+package inventory
+
+import (
+	"hash/fnv"
+	"slices"
+	"sync"
+)
+
+// shardCount is how many independently locked parts the inventory is split
+// into. More shards mean less waiting between goroutines that touch different
+// SKUs, at the price of a little memory.
+const shardCount = 32
+
+// shard owns the items and stock levels of every SKU that hashes to it.
+type shard struct {
+	mu     sync.Mutex
+	items  map[SKU]Item
+	levels map[SKU]map[Location]Level
+}
+
+func shardIndex(sku SKU) int {
+	h := fnv.New32a()
+	h.Write([]byte(sku))
+	return int(h.Sum32() % shardCount)
+}
+
+func (inv *Inventory) shard(sku SKU) *shard {
+	return &inv.shards[shardIndex(sku)]
+}
+
+// level validates a movement request and returns the per-location stock map
+// of sku. The caller must hold sh.mu.
+func (sh *shard) level(sku SKU, qty int) (map[Location]Level, error) {
+	lvl, ok := sh.levels[sku]
+	if !ok {
+		return nil, &ItemError{SKU: sku, Err: ErrUnknownItem}
+	}
+	if qty <= 0 {
+		return nil, &QuantityError{SKU: sku, Quantity: qty}
+	}
+	return lvl, nil
+}
+
+// available sums the available stock of sku over all locations. The caller
+// must hold sh.mu.
+func (sh *shard) available(sku SKU) int {
+	total := 0
+	for _, lvl := range sh.levels[sku] {
+		total += lvl.Available()
+	}
+	return total
+}
+
+// Op is one stock-in or stock-out inside an Apply batch.
+type Op struct {
+	Kind      MovementKind // StockIn or StockOut.
+	SKU       SKU
+	Location  Location
+	Quantity  int
+	Reference string
+}
+
+// stockKey identifies one stock level.
+type stockKey struct {
+	sku SKU
+	loc Location
+}
+
+// Apply performs several stock movements as one atomic update: either every
+// op succeeds, or none of them changes anything and the first failure is
+// returned. Ops run in order, so a batch may take out units that an earlier
+// op in the same batch put in. The recorded movements are returned in the
+// same order and appear next to each other in the ledger.
+//
+// Apply locks the shards of every SKU involved, always in ascending shard
+// order, so concurrent batches can never deadlock on each other.
+func (inv *Inventory) Apply(ops ...Op) ([]Movement, error) {
+	var idx []int
+	for _, op := range ops {
+		if op.Kind != StockIn && op.Kind != StockOut {
+			return nil, &KindError{Kind: op.Kind}
+		}
+		idx = append(idx, shardIndex(op.SKU))
+	}
+	slices.Sort(idx)
+	idx = slices.Compact(idx)
+	for _, i := range idx {
+		inv.shards[i].mu.Lock()
+	}
+	defer func() {
+		for _, i := range idx {
+			inv.shards[i].mu.Unlock()
+		}
+	}()
+
+	// Work out the new levels on the side first so a failing op leaves
+	// the real ones untouched.
+	pending := make(map[stockKey]Level)
+	for _, op := range ops {
+		sh := inv.shard(op.SKU)
+		lvl, err := sh.level(op.SKU, op.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		key := stockKey{op.SKU, op.Location}
+		cur, ok := pending[key]
+		if !ok {
+			cur = lvl[op.Location]
+		}
+		switch op.Kind {
+		case StockIn:
+			cur.OnHand += op.Quantity
+		case StockOut:
+			if cur.Available() < op.Quantity {
+				return nil, &StockError{SKU: op.SKU, Location: op.Location, Requested: op.Quantity, Available: cur.Available()}
+			}
+			cur.OnHand -= op.Quantity
+		}
+		pending[key] = cur
+	}
+
+	for key, lvl := range pending {
+		inv.shard(key.sku).levels[key.sku][key.loc] = lvl
+	}
+	return inv.ledger.record(inv.Now(), ops...), nil
+}
+
+// Transfer moves units of sku from one location to another atomically.
+func (inv *Inventory) Transfer(sku SKU, from, to Location, qty int, ref string) ([]Movement, error) {
+	return inv.Apply(
+		Op{Kind: StockOut, SKU: sku, Location: from, Quantity: qty, Reference: ref},
+		Op{Kind: StockIn, SKU: sku, Location: to, Quantity: qty, Reference: ref},
+	)
+}
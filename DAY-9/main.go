@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+    "fmt"
+    "maps"
+    "slices"
+)
 
 func main() {
     fmt.Println("--- Go Maps Demonstration: Simple Inventory ---")
@@ -57,8 +61,10 @@ func main() {
     // 7. Iterate over the map using for-range
     fmt.Println("\n--- Iterating Over Inventory ---")
     fmt.Println("Current Stock:")
-    for item, qty := range inventory {
-        fmt.Printf("  %s: %d units\n", item, qty)
+    // Ranging over a map visits keys in a random order that changes from run to run.
+    // Sorting the keys first gives the same listing every time.
+    for _, item := range slices.Sorted(maps.Keys(inventory)) {
+        fmt.Printf("  %s: %d units\n", item, inventory[item])
     }
 
     // 8. Map as a Reference Type
@@ -0,0 +1,131 @@
+// cmd/quiz/main.go
+package main
+
+// quiz asks questions about each day's concepts and schedules them for
+// review with spaced repetition. Run it from the tools directory:
+//
+//	go run ./cmd/quiz                # review what's due, if anything
+//	go run ./cmd/quiz 5              # take day 5's quiz
+//	go run ./cmd/quiz review         # review what's due
+//	go run ./cmd/quiz stats          # what you've seen and what's coming up
+//
+// Review schedules are kept in a JSON file, by default in the user config
+// directory; -file picks another.
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"roadmaptools/quiz"
+)
+
+func main() {
+	file := flag.String("file", "", "the review file (default: roadmap/quiz.json in the user config directory)")
+	limit := flag.Int("n", 0, "ask at most this many questions (default: all)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: quiz [flags] [day | review | stats]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*file, *limit, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(file string, limit int, args []string) error {
+	if file == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return err
+		}
+		file = filepath.Join(dir, "roadmap", "quiz.json")
+	}
+	deck, err := quiz.LoadDeck(file)
+	if err != nil {
+		return err
+	}
+	banks, err := quiz.Banks()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	cmd := "review"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	var questions []quiz.Question
+	switch cmd {
+	case "stats":
+		printStats(os.Stdout, banks, deck, now)
+		return nil
+	case "review":
+		var all []quiz.Question
+		for _, b := range banks {
+			all = append(all, b.Questions...)
+		}
+		questions = deck.Due(all, now)
+		if len(questions) == 0 {
+			fmt.Println("Nothing to review right now. Take a day's quiz with: quiz <day>")
+			return nil
+		}
+	default:
+		day, err := strconv.Atoi(cmd)
+		if err != nil {
+			flag.Usage()
+			return fmt.Errorf("unknown command %q", cmd)
+		}
+		b, err := quiz.BankFor(day)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Day %d: %s\n", b.Day, b.Title)
+		questions = b.Questions
+	}
+	if limit > 0 && len(questions) > limit {
+		questions = questions[:limit]
+	}
+
+	fmt.Println(`Answer each question, or type "q" to stop.`)
+	score, err := quiz.NewSession(os.Stdin, os.Stdout, deck).Run(questions)
+	// Keep the reviews that were made even if the session failed.
+	if saveErr := deck.Save(file); err == nil {
+		err = saveErr
+	}
+	if score.Total > 0 {
+		fmt.Printf("\nScore: %d/%d (%.0f%%)\n", score.Right, score.Total, score.Percent())
+	}
+	return err
+}
+
+func printStats(w io.Writer, banks []quiz.Bank, deck *quiz.Deck, now time.Time) {
+	fmt.Fprintf(w, "%-4s %-45s %5s %5s %4s  %s\n", "Day", "Title", "Seen", "Total", "Due", "Next review")
+	for _, b := range banks {
+		seen, due := 0, 0
+		var next time.Time
+		for _, q := range b.Questions {
+			c, ok := deck.Cards[q.Key()]
+			if !ok {
+				continue
+			}
+			seen++
+			if c.IsDue(now) {
+				due++
+			} else if next.IsZero() || c.Due.Before(next) {
+				next = c.Due
+			}
+		}
+		when := "-"
+		if !next.IsZero() {
+			when = next.Format("Mon 2 Jan")
+		}
+		fmt.Fprintf(w, "%-4d %-45s %5d %5d %4d  %s\n", b.Day, b.Title, seen, len(b.Questions), due, when)
+	}
+}
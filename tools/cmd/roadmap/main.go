@@ -0,0 +1,252 @@
+// cmd/roadmap/main.go
+package main
+
+// roadmap tracks progress through the 100-day plan in the root README.
+// Run it from the tools directory:
+//
+//	go run ./cmd/roadmap                    # overall progress, streaks and what's next
+//	go run ./cmd/roadmap list [phase]       # every topic, with done marks
+//	go run ./cmd/roadmap show 5             # a day's concept, project and notes
+//	go run ./cmd/roadmap done 5 6           # mark days done
+//	go run ./cmd/roadmap undo 6             # clear a done mark
+//	go run ./cmd/roadmap log 5 1h30m        # record time spent
+//	go run ./cmd/roadmap note 5 defer runs LIFO
+//
+// Progress is kept in a JSON file, by default in the user config
+// directory; -file picks another.
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"roadmaptools/internal/repo"
+	"roadmaptools/roadmap"
+)
+
+func main() {
+	readme := flag.String("readme", "", "the roadmap README (default: README.MD at the repository root)")
+	file := flag.String("file", "", "the progress file (default: roadmap/progress.json in the user config directory)")
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintln(out, "usage: roadmap [flags] [status | list [phase] | show day | done day... | undo day... | log day duration | note day text...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*readme, *file, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(readme, file string, args []string) error {
+	if readme == "" {
+		root, err := repo.Root()
+		if err != nil {
+			return fmt.Errorf("%w; use -readme", err)
+		}
+		readme = filepath.Join(root, "README.MD")
+	}
+	if file == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return err
+		}
+		file = filepath.Join(dir, "roadmap", "progress.json")
+	}
+	rm, err := roadmap.ParseFile(readme)
+	if err != nil {
+		return err
+	}
+	p, err := roadmap.Load(file)
+	if err != nil {
+		return err
+	}
+
+	cmd, args := "status", args
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+	now := time.Now()
+	w := os.Stdout
+	switch cmd {
+	case "status":
+		printStatus(w, rm, p, now)
+		return nil
+	case "list":
+		phase := 0
+		if len(args) > 0 {
+			if phase, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("%q is not a phase number", args[0])
+			}
+		}
+		printList(w, rm, p, phase)
+		return nil
+	case "show":
+		days, err := parseDays(rm, args, 1)
+		if err != nil {
+			return err
+		}
+		return printDay(w, rm, p, days[0])
+	case "done", "undo":
+		days, err := parseDays(rm, args, -1)
+		if err != nil {
+			return err
+		}
+		for _, day := range days {
+			if cmd == "done" {
+				p.MarkDone(day, now)
+			} else {
+				p.MarkUndone(day)
+			}
+		}
+	case "log":
+		if len(args) != 2 {
+			return errors.New("usage: roadmap log day duration (like 45m or 1h30m)")
+		}
+		days, err := parseDays(rm, args[:1], 1)
+		if err != nil {
+			return err
+		}
+		spent, err := time.ParseDuration(args[1])
+		if err != nil {
+			return err
+		}
+		if err := p.LogTime(days[0], spent, now); err != nil {
+			return err
+		}
+	case "note":
+		if len(args) < 2 {
+			return errors.New("usage: roadmap note day text...")
+		}
+		days, err := parseDays(rm, args[:1], 1)
+		if err != nil {
+			return err
+		}
+		if err := p.AddNote(days[0], strings.Join(args[1:], " "), now); err != nil {
+			return err
+		}
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+	if err := p.Save(file); err != nil {
+		return err
+	}
+	printStatus(w, rm, p, now)
+	return nil
+}
+
+// parseDays reads day numbers and checks they are on the roadmap. want is
+// how many are needed, or -1 for at least one.
+func parseDays(rm *roadmap.Roadmap, args []string, want int) ([]int, error) {
+	if len(args) == 0 || (want >= 0 && len(args) != want) {
+		return nil, errors.New("missing day number")
+	}
+	var days []int
+	for _, arg := range args {
+		day, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a day number", arg)
+		}
+		if _, _, err := rm.Topic(day); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func printStatus(w io.Writer, rm *roadmap.Roadmap, p *roadmap.Progress, now time.Time) {
+	s := roadmap.Summarize(rm, p, now)
+	fmt.Fprintf(w, "Overall   %s %3.0f%%  %d/%d days  %s\n", bar(s.Percent), s.Percent, s.DaysDone, s.TotalDays, hours(s.Minutes))
+	for _, ph := range s.Phases {
+		fmt.Fprintf(w, "Phase %d   %s %3.0f%%  %d/%d days  %s  %s\n",
+			ph.Phase.Number, bar(ph.Percent), ph.Percent, ph.DaysDone, ph.Days, hours(ph.Minutes), ph.Phase.Title)
+	}
+	fmt.Fprintf(w, "Streak    %s now, %s at best\n", plural(s.CurrentStreak, "day"), plural(s.LongestStreak, "day"))
+	if s.Next == 0 {
+		fmt.Fprintln(w, "Next      nothing left, well done!")
+		return
+	}
+	t, _, _ := rm.Topic(s.Next)
+	fmt.Fprintf(w, "Next      Day %d: %s\n", s.Next, t.Title)
+}
+
+func printList(w io.Writer, rm *roadmap.Roadmap, p *roadmap.Progress, phase int) {
+	for _, ph := range rm.Phases {
+		if phase != 0 && ph.Number != phase {
+			continue
+		}
+		fmt.Fprintf(w, "Phase %d: %s (Days %d-%d)\n", ph.Number, ph.Title, ph.FirstDay, ph.LastDay)
+		for _, t := range ph.Topics {
+			done := 0
+			for day := t.FirstDay; day <= t.LastDay; day++ {
+				if p.Done(day) {
+					done++
+				}
+			}
+			mark := " "
+			switch {
+			case done == t.Days():
+				mark = "x"
+			case done > 0:
+				mark = "~"
+			}
+			fmt.Fprintf(w, "  [%s] %-11s %s\n", mark, t.Label(), t.Title)
+		}
+	}
+}
+
+func printDay(w io.Writer, rm *roadmap.Roadmap, p *roadmap.Progress, day int) error {
+	t, ph, err := rm.Topic(day)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: %s (Phase %d: %s)\n", t.Label(), t.Title, ph.Number, ph.Title)
+	for _, f := range []struct{ name, text string }{
+		{"Concept", t.Concept}, {"Project", t.Project}, {"Resources", t.Resources},
+	} {
+		if f.text != "" {
+			fmt.Fprintf(w, "  %-10s %s\n", f.name+":", f.text)
+		}
+	}
+	d, ok := p.Days[day]
+	if !ok {
+		fmt.Fprintf(w, "Day %d: not started\n", day)
+		return nil
+	}
+	status := "in progress"
+	if d.Done {
+		status = "done " + d.CompletedAt.Local().Format("2006-01-02")
+	}
+	fmt.Fprintf(w, "Day %d: %s, %s logged\n", day, status, hours(d.Minutes()))
+	for _, n := range d.Notes {
+		fmt.Fprintf(w, "  %s  %s\n", n.At.Local().Format("2006-01-02"), n.Text)
+	}
+	return nil
+}
+
+func bar(percent float64) string {
+	const width = 20
+	filled := int(percent / 100 * width)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+func hours(minutes int) string {
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,107 @@
+// cmd/snippet/main.go
+package main
+
+// snippet compiles and runs a piece of Go code, so you can check your
+// prediction of what it prints. The code can be a whole program, some
+// declarations with a func main, or just statements. Run it from the
+// tools directory:
+//
+//	go run ./cmd/snippet demo.go
+//	echo 'fmt.Println(len("héllo"))' | go run ./cmd/snippet
+//	go run ./cmd/snippet -json -wall 2s demo.go
+//
+// It exits with status 1 if the snippet didn't compile or didn't finish
+// cleanly.
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"roadmaptools/sandbox"
+)
+
+func main() {
+	lim := sandbox.DefaultLimits
+	flag.DurationVar(&lim.Wall, "wall", lim.Wall, "real time the snippet may run")
+	flag.DurationVar(&lim.CPU, "cpu", lim.CPU, "CPU time the snippet may use")
+	mem := flag.Int64("mem", lim.Memory>>20, "memory the snippet may use, in MiB")
+	asJSON := flag.Bool("json", false, "print the result as JSON")
+	showSource := flag.Bool("source", false, "print the program the snippet was wrapped in")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: snippet [flags] [file]  (reads stdin without a file)")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	lim.Memory = *mem << 20
+
+	code, err := readSnippet(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *showSource {
+		fmt.Print(sandbox.Wrap(code))
+		return
+	}
+	res, err := sandbox.Run(context.Background(), code, lim)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(res)
+	} else {
+		printResult(res)
+	}
+	if res.Status != sandbox.OK {
+		os.Exit(1)
+	}
+}
+
+func readSnippet(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "" || path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return string(data), err
+}
+
+func printResult(res *sandbox.Result) {
+	if res.Status == sandbox.CompileFailed {
+		fmt.Fprintln(os.Stderr, "The snippet doesn't compile:")
+		for _, e := range res.CompileErrors {
+			fmt.Fprintln(os.Stderr, "  "+e.String())
+		}
+		return
+	}
+	fmt.Print(res.Stdout)
+	fmt.Fprint(os.Stderr, res.Stderr)
+	if res.Truncated {
+		fmt.Fprintln(os.Stderr, "[output truncated]")
+	}
+	switch res.Status {
+	case sandbox.RuntimeError:
+		if res.Panic != nil && res.Panic.Line > 0 {
+			fmt.Fprintf(os.Stderr, "[panicked at line %d: %s]\n", res.Panic.Line, res.Panic.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "[exit status %d]\n", res.ExitCode)
+		}
+	case sandbox.Timeout:
+		fmt.Fprintf(os.Stderr, "[stopped: still running after %v]\n", res.Duration.Round(time.Millisecond))
+	case sandbox.CPULimit:
+		fmt.Fprintln(os.Stderr, "[stopped: used up its CPU time]")
+	case sandbox.MemoryLimit:
+		fmt.Fprintln(os.Stderr, "[stopped: ran out of memory]")
+	}
+}
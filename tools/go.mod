@@ -0,0 +1,5 @@
+module roadmaptools
+
+go 1.24.3
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,232 @@
+// grader/build.go
+package grader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// maxOutput caps how much of stdout and stderr is kept, so a program stuck
+// printing in a loop can't eat all memory before the timeout.
+const maxOutput = 1 << 20
+
+var ErrTimeout = errors.New("grader: program timed out")
+
+// BuildError is a program that didn't compile. Output is what the go
+// command printed.
+type BuildError struct {
+	Output string
+}
+
+func (e *BuildError) Error() string {
+	return "grader: build failed:\n" + e.Output
+}
+
+// Program is a day's program built in a temporary directory.
+type Program struct {
+	dir  string // The copy of the sources.
+	Path string // The executable.
+}
+
+// RunResult is what a Program did when it ran.
+type RunResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// Build copies src into a temporary directory and builds it there, so
+// nothing is written next to the learner's code. A directory without a
+// go.mod, like the early days, gets one, and relative replace directives
+// such as DAY-5's "calculator => ../DAY-6" are pointed back at src's
+// neighbours. Call Remove when done with the Program.
+func Build(ctx context.Context, src string) (*Program, error) {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "grader-")
+	if err != nil {
+		return nil, err
+	}
+	p := &Program{dir: dir, Path: filepath.Join(dir, "program")}
+	if runtime.GOOS == "windows" {
+		p.Path += ".exe"
+	}
+	if err := p.build(ctx, src); err != nil {
+		p.Remove()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Program) build(ctx context.Context, src string) error {
+	work := filepath.Join(p.dir, "src")
+	if err := copyTree(src, work); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(work, "go.mod")); errors.Is(err, fs.ErrNotExist) {
+		if _, err := goCmd(ctx, work, "mod", "init", "program"); err != nil {
+			return err
+		}
+	} else if err := fixReplaces(ctx, work, src); err != nil {
+		return err
+	}
+	_, err := goCmd(ctx, work, "build", "-o", p.Path, ".")
+	return err
+}
+
+// fixReplaces rewrites relative replace paths in the copied go.mod to
+// point at the original tree.
+func fixReplaces(ctx context.Context, work, src string) error {
+	out, err := goCmd(ctx, work, "mod", "edit", "-json")
+	if err != nil {
+		return err
+	}
+	var mod struct {
+		Replace []struct {
+			Old, New struct{ Path, Version string }
+		}
+	}
+	if err := json.Unmarshal(out, &mod); err != nil {
+		return fmt.Errorf("grader: reading go.mod: %w", err)
+	}
+	for _, r := range mod.Replace {
+		if !strings.HasPrefix(r.New.Path, "./") && !strings.HasPrefix(r.New.Path, "../") {
+			continue // A module path, not a directory.
+		}
+		old := r.Old.Path
+		if r.Old.Version != "" {
+			old += "@" + r.Old.Version
+		}
+		abs := filepath.Join(src, r.New.Path)
+		if _, err := goCmd(ctx, work, "mod", "edit", "-replace", old+"="+abs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goCmd runs the go command in dir and returns its stdout. A failure
+// becomes a *BuildError holding everything it printed.
+func goCmd(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	// Build the module on its own, with the toolchain that is installed.
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOTOOLCHAIN=local")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, &BuildError{Output: msg}
+	}
+	return stdout.Bytes(), nil
+}
+
+// Run runs the program with stdin as its input and kills it after
+// timeout. It returns ErrTimeout, along with the output so far, if the
+// program was killed. A non-zero exit status is not an error; it is in
+// the RunResult.
+func (p *Program) Run(ctx context.Context, stdin string, timeout time.Duration) (*RunResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Dir = filepath.Join(p.dir, "src") // Files the program writes stay in the copy.
+	cmd.Stdin = strings.NewReader(stdin)
+	stdout, stderr := &cappedBuffer{}, &cappedBuffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = time.Second // Don't hang on pipes a child process kept open.
+
+	start := time.Now()
+	err := cmd.Run()
+	res := &RunResult{Stdout: stdout.String(), Stderr: stderr.String(), Duration: time.Since(start)}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return res, ErrTimeout
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		res.ExitCode = exitErr.ExitCode()
+		return res, nil
+	}
+	return res, err
+}
+
+// Remove deletes the temporary directory.
+func (p *Program) Remove() error {
+	return os.RemoveAll(p.dir)
+}
+
+// cappedBuffer keeps the first maxOutput bytes written to it and quietly
+// drops the rest. It doesn't embed bytes.Buffer: io.Copy would then use
+// Buffer.ReadFrom and skip the cap.
+type cappedBuffer struct {
+	buf bytes.Buffer
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
+
+// copyTree copies the regular files under src to dst, skipping hidden
+// directories such as .git.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir() && rel != "." && strings.HasPrefix(d.Name(), "."):
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,103 @@
+// grader/diff.go
+package grader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is how many matching lines are shown around each change.
+const diffContext = 2
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-' // In want only.
+	opInsert opKind = '+' // In got only.
+)
+
+type diffOp struct {
+	kind opKind
+	i, j int // Indexes into want and got.
+}
+
+// diffLines finds the longest common subsequence of want and got, where
+// eq(i, j) says whether want[i] matches got[j], and returns the edit
+// script. Program output is short, so the quadratic table is fine.
+func diffLines(n, m int, eq func(i, j int) bool) []diffOp {
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if eq(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && eq(i, j):
+			ops = append(ops, diffOp{opEqual, i, j})
+			i, j = i+1, j+1
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{opDelete, i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{opInsert, i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+func allEqual(ops []diffOp) bool {
+	for _, op := range ops {
+		if op.kind != opEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// formatDiff prints the changes with a little context, "-" for expected
+// lines that are missing and "+" for lines the program printed instead.
+// Each hunk starts with the line numbers, within the checked section,
+// where it begins.
+func formatDiff(ops []diffOp, want, got []string) string {
+	show := make([]bool, len(ops))
+	for k, op := range ops {
+		if op.kind == opEqual {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(ops)-1, k+diffContext); c++ {
+			show[c] = true
+		}
+	}
+
+	var b strings.Builder
+	for k, op := range ops {
+		if !show[k] {
+			continue
+		}
+		if k == 0 || !show[k-1] {
+			fmt.Fprintf(&b, "@@ expected line %d, output line %d @@\n", op.i+1, op.j+1)
+		}
+		switch op.kind {
+		case opEqual:
+			fmt.Fprintf(&b, "  %s\n", got[op.j])
+		case opDelete:
+			fmt.Fprintf(&b, "- %s\n", want[op.i])
+		case opInsert:
+			fmt.Fprintf(&b, "+ %s\n", got[op.j])
+		}
+	}
+	return b.String()
+}
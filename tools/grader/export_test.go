@@ -0,0 +1,5 @@
+// grader/export_test.go
+package grader
+
+// Section lets the tests in grader_test reach section.
+var Section = section
@@ -0,0 +1,93 @@
+// grader/grader.go
+package grader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTimeout is how long a program may run when its spec doesn't say.
+const DefaultTimeout = 10 * time.Second
+
+// Result is the outcome of grading one day.
+type Result struct {
+	Spec Spec
+	Dir  string // What was built.
+
+	// Err is why the program couldn't be checked, or failed regardless of
+	// its output: a *BuildError, ErrTimeout or the wrong exit status.
+	Err    error
+	Run    *RunResult // Nil if the program never ran.
+	Checks []CheckResult
+}
+
+// CheckResult is the outcome of one Check.
+type CheckResult struct {
+	Check  Check
+	Passed bool
+	Diff   string // Expected against actual, when the check failed.
+	Err    error  // Why the check couldn't be made, such as a missing section.
+}
+
+// Passed reports whether the program built, ran and passed every check.
+func (r *Result) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Grade builds the program in dir, runs it and checks its output against
+// spec. Problems with the program are reported in the Result; the error
+// is only for problems with grading itself, like a cancelled ctx.
+func Grade(ctx context.Context, spec Spec, dir string) (*Result, error) {
+	res := &Result{Spec: spec, Dir: dir}
+	prog, err := Build(ctx, dir)
+	var buildErr *BuildError
+	if errors.As(err, &buildErr) {
+		res.Err = err
+		return res, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer prog.Remove()
+
+	timeout := spec.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	res.Run, err = prog.Run(ctx, spec.Stdin, timeout)
+	switch {
+	case errors.Is(err, ErrTimeout):
+		res.Err = fmt.Errorf("%w after %v", ErrTimeout, timeout)
+	case err != nil:
+		return nil, err
+	case res.Run.ExitCode != spec.ExitCode:
+		res.Err = fmt.Errorf("grader: program exited with status %d, want %d", res.Run.ExitCode, spec.ExitCode)
+	}
+
+	// Check the output even after a timeout or a bad exit: it shows how
+	// far the program got.
+	out := lines(res.Run.Stdout)
+	for _, c := range spec.Checks {
+		res.Checks = append(res.Checks, check(c, out))
+	}
+	return res, ctx.Err()
+}
+
+func check(c Check, out []string) CheckResult {
+	got, err := section(out, c.From, c.To)
+	if err != nil {
+		return CheckResult{Check: c, Err: err}
+	}
+	ok, diff, err := c.Match.Match(got, lines(c.Expect))
+	return CheckResult{Check: c, Passed: ok, Diff: diff, Err: err}
+}
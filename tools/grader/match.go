@@ -0,0 +1,156 @@
+// grader/match.go
+package grader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mode is how a check compares output with what it expects.
+type Mode string
+
+const (
+	// Exact wants the same lines. Only "\r\n" line endings and a missing
+	// final newline are forgiven.
+	Exact Mode = "exact"
+
+	// Whitespace ignores blank lines and how much space separates words,
+	// so "a  b" matches "a b" and indentation doesn't matter.
+	Whitespace Mode = "whitespace"
+
+	// Regex treats each expected line as a regular expression that must
+	// match the whole of the corresponding output line.
+	Regex Mode = "regex"
+
+	// Unordered wants the same lines in any order, for output that comes
+	// from ranging over a map.
+	Unordered Mode = "unordered"
+)
+
+// Modes lists every Mode.
+var Modes = []Mode{Exact, Whitespace, Regex, Unordered}
+
+// Match compares got with want. When they differ, it also returns a diff
+// of want against got.
+func (m Mode) Match(got, want []string) (ok bool, diff string, err error) {
+	switch m {
+	case Exact:
+		ok, diff = compare(want, got, func(i, j int) bool { return want[i] == got[j] })
+	case Whitespace:
+		want, got = squeeze(want), squeeze(got)
+		ok, diff = compare(want, got, func(i, j int) bool { return want[i] == got[j] })
+	case Regex:
+		res := make([]*regexp.Regexp, len(want))
+		for i, w := range want {
+			if res[i], err = regexp.Compile("^(?:" + w + ")$"); err != nil {
+				return false, "", fmt.Errorf("grader: expected line %d: %w", i+1, err)
+			}
+		}
+		ok, diff = compare(want, got, func(i, j int) bool { return res[i].MatchString(got[j]) })
+	case Unordered:
+		ok, diff = unordered(want, got)
+	default:
+		return false, "", fmt.Errorf("grader: unknown match %q", m)
+	}
+	return ok, diff, nil
+}
+
+// compare diffs want against got, where eq(i, j) says whether want[i]
+// matches got[j].
+func compare(want, got []string, eq func(i, j int) bool) (bool, string) {
+	ops := diffLines(len(want), len(got), eq)
+	if allEqual(ops) {
+		return true, ""
+	}
+	return false, formatDiff(ops, want, got)
+}
+
+// squeeze drops blank lines and collapses runs of spaces and tabs.
+func squeeze(lines []string) []string {
+	var out []string
+	for _, l := range lines {
+		if f := strings.Fields(l); len(f) > 0 {
+			out = append(out, strings.Join(f, " "))
+		}
+	}
+	return out
+}
+
+func unordered(want, got []string) (bool, string) {
+	counts := make(map[string]int)
+	for _, g := range got {
+		counts[g]++
+	}
+	var missing []string
+	for _, w := range want {
+		if counts[w] > 0 {
+			counts[w]--
+		} else {
+			missing = append(missing, w)
+		}
+	}
+	var extra []string
+	for _, g := range got {
+		if counts[g] > 0 {
+			counts[g]--
+			extra = append(extra, g)
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 {
+		return true, ""
+	}
+	var b strings.Builder
+	for _, l := range missing {
+		fmt.Fprintf(&b, "- %s\n", l)
+	}
+	for _, l := range extra {
+		fmt.Fprintf(&b, "+ %s\n", l)
+	}
+	return false, b.String()
+}
+
+// lines splits output into lines, forgiving "\r\n" and a missing final
+// newline.
+func lines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// section cuts out the lines from the first match of from up to, not
+// including, the next match of to. Blank lines at the end are dropped, so
+// a section doesn't have to expect the gap before the next heading.
+func section(out []string, from, to string) ([]string, error) {
+	start := 0
+	if from != "" {
+		re := regexp.MustCompile(from) // Checked by ParseSpec.
+		start = -1
+		for i, l := range out {
+			if re.MatchString(l) {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("no line matches from %q", from)
+		}
+	}
+	end := len(out)
+	if to != "" {
+		re := regexp.MustCompile(to)
+		for i := start + 1; i < len(out); i++ {
+			if re.MatchString(out[i]) {
+				end = i
+				break
+			}
+		}
+	}
+	for end > start && strings.TrimSpace(out[end-1]) == "" {
+		end--
+	}
+	return out[start:end], nil
+}
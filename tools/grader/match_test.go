@@ -0,0 +1,146 @@
+// grader/match_test.go
+package grader_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"roadmaptools/grader"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      grader.Mode
+		got, want string
+		ok        bool
+	}{
+		{"exact", grader.Exact, "a\nb", "a\nb", true},
+		{"exact differs", grader.Exact, "a\nc", "a\nb", false},
+		{"exact keeps spaces", grader.Exact, "a ", "a", false},
+		{"exact extra line", grader.Exact, "a\nb", "a", false},
+
+		{"whitespace", grader.Whitespace, "  a   b\n\n\tc\n", "a b\nc", true},
+		{"whitespace keeps words", grader.Whitespace, "a b", "ab", false},
+		{"whitespace keeps order", grader.Whitespace, "b\na", "a\nb", false},
+
+		{"regex", grader.Regex, "3 items\n[p q s]", `\d+ items` + "\n" + `\[p q [rs]\]`, true},
+		{"regex matches whole lines", grader.Regex, "12", `\d`, false},
+		{"regex alternation is anchored", grader.Regex, "ab", "a|b", false},
+		{"regex per line", grader.Regex, "x\n1", `\d` + "\nx", false},
+
+		{"unordered", grader.Unordered, "b\nc\na", "a\nb\nc", true},
+		{"unordered counts repeats", grader.Unordered, "a\nb\nb", "a\na\nb", false},
+		{"unordered missing line", grader.Unordered, "a", "a\nb", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, diff, err := tt.mode.Match(strings.Split(tt.got, "\n"), strings.Split(tt.want, "\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.ok {
+				t.Errorf("Match = %v, want %v; diff:\n%s", ok, tt.ok, diff)
+			}
+			if ok != (diff == "") {
+				t.Errorf("Match = %v with diff %q: a diff is only for a mismatch", ok, diff)
+			}
+		})
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	if _, _, err := grader.Regex.Match([]string{"a"}, []string{"a", "(unclosed"}); err == nil || !strings.Contains(err.Error(), "expected line 2") {
+		t.Errorf("Regex with a bad expression = %v, want an error naming line 2", err)
+	}
+	if _, _, err := grader.Mode("fuzzy").Match(nil, nil); err == nil {
+		t.Error("unknown mode matched")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      grader.Mode
+		got, want []string
+		diff      string
+	}{
+		{"two hunks", grader.Exact,
+			[]string{"a", "B", "c", "d", "e", "f", "g"},
+			[]string{"a", "b", "c", "d", "e", "f", "g", "h"},
+			"@@ expected line 1, output line 1 @@\n  a\n- b\n+ B\n  c\n  d\n" +
+				"@@ expected line 6, output line 6 @@\n  f\n  g\n- h\n"},
+		{"nearby changes share a hunk", grader.Exact,
+			[]string{"x", "b", "c", "y"},
+			[]string{"a", "b", "c", "d"},
+			"@@ expected line 1, output line 1 @@\n- a\n+ x\n  b\n  c\n- d\n+ y\n"},
+		{"inserted line", grader.Exact,
+			[]string{"a", "new", "b"},
+			[]string{"a", "b"},
+			"@@ expected line 1, output line 1 @@\n  a\n+ new\n  b\n"},
+		{"nothing printed", grader.Exact,
+			nil,
+			[]string{"a", "b"},
+			"@@ expected line 1, output line 1 @@\n- a\n- b\n"},
+		{"regex shows the output line", grader.Regex,
+			[]string{"10 items", "oops"},
+			[]string{`\d+ items`, "done"},
+			"@@ expected line 1, output line 1 @@\n  10 items\n- done\n+ oops\n"},
+		{"unordered lists missing then extra", grader.Unordered,
+			[]string{"c", "x", "a"},
+			[]string{"a", "b", "c"},
+			"- b\n+ x\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diff, err := tt.mode.Match(tt.got, tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff != tt.diff {
+				t.Errorf("diff =\n%s\nwant\n%s", diff, tt.diff)
+			}
+		})
+	}
+}
+
+func TestSection(t *testing.T) {
+	out := []string{
+		"intro",
+		"--- One ---",
+		"1",
+		"",
+		"--- Two ---",
+		"2",
+		"",
+		"",
+	}
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{"whole output", "", "", []string{"intro", "--- One ---", "1", "", "--- Two ---", "2"}},
+		{"up to a heading", "", "^--- ", []string{"intro"}},
+		{"between headings", "^--- One", "^--- ", []string{"--- One ---", "1"}},
+		{"to the end", "^--- Two", "^--- ", []string{"--- Two ---", "2"}},
+		{"first match of from", "^---", "^--- Two", []string{"--- One ---", "1"}},
+		{"to never matches", "^1$", "^nope", []string{"1", "", "--- Two ---", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := grader.Section(out, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Section(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := grader.Section(out, "^--- Three", ""); err == nil {
+		t.Error("Section with a from that matches nothing succeeded")
+	}
+}
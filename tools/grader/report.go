@@ -0,0 +1,65 @@
+// grader/report.go
+package grader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteReport prints one line per day and, for each failure, why it
+// failed: the build output, the diff of each failed check and anything
+// the program wrote to stderr. With verbose, passing days show their
+// checks too.
+func WriteReport(w io.Writer, results []*Result, verbose bool) error {
+	var b strings.Builder
+	passed := 0
+	for _, r := range results {
+		status := "ok"
+		if r.Passed() {
+			passed++
+		} else {
+			status = "FAIL"
+		}
+		n := 0
+		for _, c := range r.Checks {
+			if c.Passed {
+				n++
+			}
+		}
+		fmt.Fprintf(&b, "%-7s %-4s  %d/%d checks", r.Spec.Dir, status, n, len(r.Spec.Checks))
+		if r.Run != nil {
+			fmt.Fprintf(&b, "  %v", r.Run.Duration.Round(time.Millisecond))
+		}
+		b.WriteString("\n")
+
+		if r.Err != nil {
+			indent(&b, r.Err.Error())
+		}
+		for _, c := range r.Checks {
+			switch {
+			case c.Err != nil:
+				fmt.Fprintf(&b, "    %s (%s): %v\n", c.Check.Name, c.Check.Match, c.Err)
+			case !c.Passed:
+				fmt.Fprintf(&b, "    %s (%s): output differs\n", c.Check.Name, c.Check.Match)
+				indent(&b, c.Diff)
+			case verbose:
+				fmt.Fprintf(&b, "    %s (%s): ok\n", c.Check.Name, c.Check.Match)
+			}
+		}
+		if !r.Passed() && r.Run != nil && r.Run.Stderr != "" {
+			b.WriteString("    stderr:\n")
+			indent(&b, r.Run.Stderr)
+		}
+	}
+	fmt.Fprintf(&b, "%d/%d days passed\n", passed, len(results))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func indent(b *strings.Builder, text string) {
+	for _, l := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString("        " + l + "\n")
+	}
+}
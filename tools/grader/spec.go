@@ -0,0 +1,121 @@
+// grader/spec.go
+package grader // Build a day's program, run it and check its output against a spec
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed specs/*.yaml
+var specFiles embed.FS
+
+var ErrNoSpec = errors.New("grader: no spec for that day")
+
+// Spec says what a day's program should print. Specs live in specs/ as
+// YAML, one file per day:
+//
+//	day: 9
+//	dir: DAY-9
+//	checks:
+//	  - name: 'Iterating Over Inventory'
+//	    match: unordered
+//	    from: '^--- Iterating Over Inventory ---$'
+//	    to: '^--- '
+//	    expect: |
+//	      --- Iterating Over Inventory ---
+//	      Current Stock:
+//	        Laptop: 5 units
+//	        Mouse: 15 units
+type Spec struct {
+	Day      int           `yaml:"day"`
+	Dir      string        `yaml:"dir"`       // The day's directory, relative to the repository root.
+	Stdin    string        `yaml:"stdin"`     // Fed to the program.
+	ExitCode int           `yaml:"exit_code"` // What the program must exit with.
+	Timeout  time.Duration `yaml:"timeout"`   // How long it may run. Defaults to DefaultTimeout.
+	Checks   []Check       `yaml:"checks"`
+}
+
+// Check compares one section of the output with Expect.
+type Check struct {
+	Name  string `yaml:"name"`
+	Match Mode   `yaml:"match"` // Defaults to Exact.
+
+	// From and To are regular expressions that cut the section to check
+	// out of the output: it starts at the first line matching From and
+	// stops before the next line matching To. Without From the section
+	// starts at the top; without To it runs to the end. Blank lines at
+	// the end of a section are ignored.
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+
+	Expect string `yaml:"expect"`
+}
+
+// Specs returns the built-in specs, sorted by day.
+func Specs() ([]Spec, error) {
+	names, err := fs.Glob(specFiles, "specs/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var specs []Spec
+	for _, name := range names {
+		data, err := specFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		s, err := ParseSpec(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		specs = append(specs, s)
+	}
+	slices.SortFunc(specs, func(a, b Spec) int { return a.Day - b.Day })
+	return specs, nil
+}
+
+// SpecFor returns the built-in spec for a day.
+func SpecFor(day int) (Spec, error) {
+	specs, err := Specs()
+	if err != nil {
+		return Spec{}, err
+	}
+	for _, s := range specs {
+		if s.Day == day {
+			return s, nil
+		}
+	}
+	return Spec{}, fmt.Errorf("%w: %d", ErrNoSpec, day)
+}
+
+// ParseSpec decodes and checks a YAML spec.
+func ParseSpec(data []byte) (Spec, error) {
+	var s Spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return Spec{}, fmt.Errorf("grader: %w", err)
+	}
+	if s.Dir == "" {
+		return Spec{}, fmt.Errorf("grader: day %d: missing dir", s.Day)
+	}
+	for i := range s.Checks {
+		c := &s.Checks[i]
+		if c.Match == "" {
+			c.Match = Exact
+		}
+		if !slices.Contains(Modes, c.Match) {
+			return Spec{}, fmt.Errorf("grader: day %d: check %q: unknown match %q", s.Day, c.Name, c.Match)
+		}
+		for _, re := range []string{c.From, c.To} {
+			if _, err := regexp.Compile(re); err != nil {
+				return Spec{}, fmt.Errorf("grader: day %d: check %q: %w", s.Day, c.Name, err)
+			}
+		}
+	}
+	return s, nil
+}
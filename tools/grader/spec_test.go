@@ -0,0 +1,82 @@
+// grader/spec_test.go
+package grader_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"roadmaptools/grader"
+)
+
+func TestParseSpec(t *testing.T) {
+	s, err := grader.ParseSpec([]byte(`
+day: 8
+dir: DAY-8
+timeout: 5s
+checks:
+  - name: default match
+    expect: hello
+  - name: regex
+    match: regex
+    from: '^--- '
+    expect: '\d+'
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Day != 8 || s.Dir != "DAY-8" || s.Timeout.Seconds() != 5 || len(s.Checks) != 2 {
+		t.Fatalf("ParseSpec = %+v", s)
+	}
+	if s.Checks[0].Match != grader.Exact || s.Checks[1].Match != grader.Regex {
+		t.Errorf("matches = %q, %q, want exact (the default) and regex", s.Checks[0].Match, s.Checks[1].Match)
+	}
+}
+
+func TestParseSpecRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string // In the error.
+	}{
+		{"unknown mode", "day: 1\ndir: DAY-1\nchecks:\n  - name: c\n    match: fuzzy\n", `unknown match "fuzzy"`},
+		{"bad from", "day: 1\ndir: DAY-1\nchecks:\n  - name: c\n    from: '(unclosed'\n", `check "c"`},
+		{"bad to", "day: 1\ndir: DAY-1\nchecks:\n  - name: c\n    to: '[z-a]'\n", `check "c"`},
+		{"no dir", "day: 3\n", "day 3: missing dir"},
+		{"not YAML", "day: [1\n", "grader:"},
+		{"wrong type", "day: one\ndir: DAY-1\n", "grader:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := grader.ParseSpec([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseSpec = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecs(t *testing.T) {
+	specs, err := grader.Specs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) == 0 {
+		t.Fatal("no built-in specs")
+	}
+	for i, s := range specs {
+		if i > 0 && s.Day <= specs[i-1].Day {
+			t.Errorf("specs not sorted by day: %d after %d", s.Day, specs[i-1].Day)
+		}
+		if len(s.Checks) == 0 {
+			t.Errorf("day %d has no checks", s.Day)
+		}
+	}
+
+	if s, err := grader.SpecFor(specs[0].Day); err != nil || s.Dir != specs[0].Dir {
+		t.Errorf("SpecFor(%d) = %+v, %v", specs[0].Day, s, err)
+	}
+	if _, err := grader.SpecFor(1000); !errors.Is(err, grader.ErrNoSpec) {
+		t.Errorf("SpecFor(1000) = %v, want ErrNoSpec", err)
+	}
+}
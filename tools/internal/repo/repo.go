@@ -0,0 +1,34 @@
+// internal/repo/repo.go
+package repo // Find the root of the roadmap repository from anywhere inside it
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var ErrNoRoot = errors.New("can't find the repository root (a directory with DAY-1 in it)")
+
+// Root walks up from the current directory to the one holding DAY-1.
+func Root() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return RootFrom(dir)
+}
+
+// RootFrom walks up from dir to the one holding DAY-1, or returns
+// ErrNoRoot.
+func RootFrom(dir string) (string, error) {
+	for {
+		if info, err := os.Stat(filepath.Join(dir, "DAY-1")); err == nil && info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoRoot
+		}
+		dir = parent
+	}
+}
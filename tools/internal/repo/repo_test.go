@@ -0,0 +1,42 @@
+// internal/repo/repo_test.go
+package repo_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"roadmaptools/internal/repo"
+)
+
+func TestRootFrom(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "DAY-5", "scope")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.RootFrom(deep); !errors.Is(err, repo.ErrNoRoot) {
+		t.Errorf("RootFrom without DAY-1 = %v, want ErrNoRoot", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(root, "DAY-1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{root, deep} {
+		if got, err := repo.RootFrom(dir); err != nil || got != root {
+			t.Errorf("RootFrom(%s) = %q, %v, want %q", dir, got, err, root)
+		}
+	}
+}
+
+func TestRoot(t *testing.T) {
+	root, err := repo.Root()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The tests run in internal/repo, inside the tools module.
+	if _, err := os.Stat(filepath.Join(root, "tools", "internal", "repo")); err != nil {
+		t.Errorf("Root = %q, which doesn't hold this package", root)
+	}
+}
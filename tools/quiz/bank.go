@@ -0,0 +1,197 @@
+// quiz/bank.go
+package quiz // Question banks for each day, and spaced-repetition review of them
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed banks/*.yaml
+var bankFiles embed.FS
+
+var ErrNoBank = errors.New("quiz: no questions for that day")
+
+// Kind is the type of a question.
+type Kind string
+
+const (
+	// Choice is multiple choice: answer with the letter of a choice.
+	Choice Kind = "choice"
+
+	// Output shows a program and asks what it prints.
+	Output Kind = "output"
+
+	// Code shows a program with a ___ blank and asks what goes there.
+	Code Kind = "code"
+)
+
+// Blank marks the gap in a Code question.
+const Blank = "___"
+
+// Bank is one day's questions. Banks live in banks/ as YAML:
+//
+//	day: 5
+//	title: Functions
+//	questions:
+//	  - id: defer-order
+//	    kind: output
+//	    prompt: What does this print?
+//	    code: |
+//	      ...
+//	    answer: |
+//	      ...
+//	    explain: Deferred calls run last in, first out.
+type Bank struct {
+	Day       int        `yaml:"day"`
+	Title     string     `yaml:"title"`
+	Questions []Question `yaml:"questions"`
+}
+
+// Question is one question of a bank.
+type Question struct {
+	ID      string   `yaml:"id"` // Unique within the day.
+	Day     int      `yaml:"-"`
+	Kind    Kind     `yaml:"kind"`
+	Prompt  string   `yaml:"prompt"`
+	Code    string   `yaml:"code"`    // The program for Output and Code questions.
+	Choices []string `yaml:"choices"` // Only for Choice.
+	Answer  string   `yaml:"answer"`  // For Choice, the text of the right choice.
+	Accept  []string `yaml:"accept"`  // Other answers that also count, for Code.
+	Explain string   `yaml:"explain"` // Shown after answering.
+}
+
+// Key identifies a question across all banks, like "5/defer-order".
+func (q Question) Key() string {
+	return fmt.Sprintf("%d/%s", q.Day, q.ID)
+}
+
+// Banks returns the built-in banks, sorted by day.
+func Banks() ([]Bank, error) {
+	names, err := fs.Glob(bankFiles, "banks/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var banks []Bank
+	for _, name := range names {
+		data, err := bankFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		b, err := ParseBank(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		banks = append(banks, b)
+	}
+	slices.SortFunc(banks, func(a, b Bank) int { return a.Day - b.Day })
+	return banks, nil
+}
+
+// BankFor returns the built-in bank for a day.
+func BankFor(day int) (Bank, error) {
+	banks, err := Banks()
+	if err != nil {
+		return Bank{}, err
+	}
+	for _, b := range banks {
+		if b.Day == day {
+			return b, nil
+		}
+	}
+	return Bank{}, fmt.Errorf("%w: %d", ErrNoBank, day)
+}
+
+// ParseBank decodes and checks a YAML bank.
+func ParseBank(data []byte) (Bank, error) {
+	var b Bank
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return Bank{}, fmt.Errorf("quiz: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i := range b.Questions {
+		q := &b.Questions[i]
+		q.Day = b.Day
+		if err := q.check(); err != nil {
+			return Bank{}, fmt.Errorf("quiz: day %d, question %d (%s): %w", b.Day, i+1, q.ID, err)
+		}
+		if seen[q.ID] {
+			return Bank{}, fmt.Errorf("quiz: day %d: duplicate question id %q", b.Day, q.ID)
+		}
+		seen[q.ID] = true
+	}
+	return b, nil
+}
+
+func (q Question) check() error {
+	switch {
+	case q.ID == "":
+		return errors.New("missing id")
+	case q.Prompt == "":
+		return errors.New("missing prompt")
+	case strings.TrimSpace(q.Answer) == "":
+		return errors.New("missing answer")
+	}
+	switch q.Kind {
+	case Choice:
+		if len(q.Choices) < 2 || len(q.Choices) > 26 {
+			return errors.New("a choice question needs 2 to 26 choices")
+		}
+		if !slices.Contains(q.Choices, q.Answer) {
+			return errors.New("the answer is not one of the choices")
+		}
+	case Output:
+		if q.Code == "" {
+			return errors.New("an output question needs code")
+		}
+	case Code:
+		if strings.Count(q.Code, Blank) != 1 {
+			return fmt.Errorf("a code question needs exactly one %s in its code", Blank)
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", q.Kind)
+	}
+	return nil
+}
+
+// Check reports whether answer is right. Choice questions take the
+// letter of a choice or its text. Output answers are compared line by
+// line, ignoring blank lines and spaces at the ends of lines. Code answers
+// ignore how much space separates words.
+func (q Question) Check(answer string) bool {
+	switch q.Kind {
+	case Choice:
+		a := strings.TrimSpace(answer)
+		if len(a) == 1 {
+			if i := int(strings.ToLower(a)[0] - 'a'); i >= 0 && i < len(q.Choices) {
+				return q.Choices[i] == q.Answer
+			}
+		}
+		return strings.EqualFold(a, q.Answer)
+	case Output:
+		return slices.Equal(outputLines(answer), outputLines(q.Answer))
+	case Code:
+		a := strings.Join(strings.Fields(answer), " ")
+		for _, want := range append([]string{q.Answer}, q.Accept...) {
+			if a == strings.Join(strings.Fields(want), " ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func outputLines(s string) []string {
+	var out []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+// quiz/bank_test.go
+package quiz_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"roadmaptools/quiz"
+	"roadmaptools/sandbox"
+)
+
+func TestCheck(t *testing.T) {
+	choice := quiz.Question{Kind: quiz.Choice, Choices: []string{"nil", "0", "an empty map"}, Answer: "nil"}
+	output := quiz.Question{Kind: quiz.Output, Answer: "three\ntwo\none\n"}
+	code := quiz.Question{Kind: quiz.Code, Answer: "defer f.Close()", Accept: []string{"defer func() { f.Close() }()"}}
+	tests := []struct {
+		name   string
+		q      quiz.Question
+		answer string
+		want   bool
+	}{
+		{"choice letter", choice, "a", true},
+		{"choice capital letter", choice, " A\n", true},
+		{"choice wrong letter", choice, "b", false},
+		{"choice letter past the end", choice, "d", false},
+		{"choice text", choice, "NIL", true},
+		{"choice wrong text", choice, "an empty map", false},
+		{"output", output, "three\ntwo\none", true},
+		{"output with blank lines and spaces", output, "\n  three \n\ntwo\none  \n\n", true},
+		{"output in the wrong order", output, "three\none\ntwo\n", false},
+		{"output with a line missing", output, "three\ntwo\n", false},
+		{"output split differently", output, "three two\none\n", false},
+		{"code", code, "defer f.Close()", true},
+		{"code with other spacing", code, "  defer   f.Close()\n", true},
+		{"code accepted alternative", code, "defer func() {\n\tf.Close()\n}()", true},
+		{"code wrong", code, "f.Close()", false},
+		{"code spacing inside a word", code, "defer f. Close()", false},
+		{"unknown kind", quiz.Question{Kind: "essay", Answer: "x"}, "x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Check(tt.answer); got != tt.want {
+				t.Errorf("Check(%q) = %v, want %v", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBank(t *testing.T) {
+	b, err := quiz.ParseBank([]byte(`
+day: 5
+title: Functions
+questions:
+  - id: zero-map
+    kind: choice
+    prompt: What is the zero value of a map?
+    choices: [nil, an empty map]
+    answer: nil
+  - id: close
+    kind: code
+    prompt: Close the file when the function returns.
+    code: "___"
+    answer: defer f.Close()
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Day != 5 || b.Title != "Functions" || len(b.Questions) != 2 {
+		t.Fatalf("ParseBank = %+v", b)
+	}
+	if q := b.Questions[1]; q.Day != 5 || q.Key() != "5/close" {
+		t.Errorf("question = %+v with key %q, want day 5 and key 5/close", q, q.Key())
+	}
+}
+
+func TestParseBankErrors(t *testing.T) {
+	const head = "day: 3\nquestions:\n"
+	tests := []struct {
+		name string
+		yaml string
+		want string // In the error.
+	}{
+		{"bad yaml", "day: [", "quiz: yaml"},
+		{"missing id", head + "  - {kind: output, prompt: p, code: c, answer: a}\n", "question 1 (): missing id"},
+		{"missing prompt", head + "  - {id: x, kind: output, code: c, answer: a}\n", "(x): missing prompt"},
+		{"missing answer", head + "  - {id: x, kind: output, prompt: p, code: c, answer: \"  \"}\n", "missing answer"},
+		{"one choice", head + "  - {id: x, kind: choice, prompt: p, choices: [a], answer: a}\n", "2 to 26 choices"},
+		{"answer not a choice", head + "  - {id: x, kind: choice, prompt: p, choices: [a, b], answer: c}\n", "not one of the choices"},
+		{"output without code", head + "  - {id: x, kind: output, prompt: p, answer: a}\n", "needs code"},
+		{"code without a blank", head + "  - {id: x, kind: code, prompt: p, code: f(), answer: a}\n", "exactly one ___"},
+		{"code with two blanks", head + "  - {id: x, kind: code, prompt: p, code: ___(___), answer: a}\n", "exactly one ___"},
+		{"unknown kind", head + "  - {id: x, kind: essay, prompt: p, answer: a}\n", `unknown kind "essay"`},
+		{"duplicate id", head +
+			"  - {id: x, kind: output, prompt: p, code: c, answer: a}\n" +
+			"  - {id: x, kind: output, prompt: p, code: c, answer: a}\n", `day 3: duplicate question id "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := quiz.ParseBank([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseBank = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBanks(t *testing.T) {
+	banks, err := quiz.Banks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(banks) != 10 {
+		t.Fatalf("%d banks, want one for each of days 1-10", len(banks))
+	}
+	for i, b := range banks {
+		if b.Day != i+1 || len(b.Questions) == 0 {
+			t.Errorf("bank %d is day %d with %d questions", i+1, b.Day, len(b.Questions))
+		}
+	}
+	if b, err := quiz.BankFor(5); err != nil || b.Day != 5 {
+		t.Errorf("BankFor(5) = day %d, %v", b.Day, err)
+	}
+	if _, err := quiz.BankFor(11); !errors.Is(err, quiz.ErrNoBank) {
+		t.Errorf("BankFor(11) = %v, want ErrNoBank", err)
+	}
+}
+
+// TestOutputAnswers runs every output question's program, so a bank edit
+// can't ship an answer the program doesn't print.
+func TestOutputAnswers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles every output question")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("needs the go command:", err)
+	}
+	banks, err := quiz.Banks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range banks {
+		for _, q := range b.Questions {
+			if q.Kind != quiz.Output {
+				continue
+			}
+			t.Run(q.Key(), func(t *testing.T) {
+				t.Parallel()
+				res, err := sandbox.Run(context.Background(), q.Code, sandbox.Limits{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Status != sandbox.OK {
+					t.Fatalf("status %s:\n%s", res.Status, res.Stderr)
+				}
+				if !q.Check(res.Stdout) {
+					t.Errorf("the program prints\n%s\nbut the answer is\n%s", res.Stdout, q.Answer)
+				}
+			})
+		}
+	}
+}
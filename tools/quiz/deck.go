@@ -0,0 +1,86 @@
+// quiz/deck.go
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Deck holds the review schedule of every question answered so far, kept
+// in a JSON file keyed by Question.Key.
+type Deck struct {
+	Cards map[string]Card `json:"cards"`
+}
+
+// LoadDeck reads a deck from path. A missing file is an empty deck.
+func LoadDeck(path string) (*Deck, error) {
+	d := &Deck{Cards: make(map[string]Card)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("quiz: reading %s: %w", path, err)
+	}
+	if d.Cards == nil {
+		d.Cards = make(map[string]Card)
+	}
+	return d, nil
+}
+
+// Save writes the deck to path through a temporary file, creating the
+// directory if needed.
+func (d *Deck) Save(path string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Card returns the schedule of q, or a new one if q was never reviewed.
+func (d *Deck) Card(q Question) Card {
+	if c, ok := d.Cards[q.Key()]; ok {
+		return c
+	}
+	return NewCard()
+}
+
+// Review records an answer to q.
+func (d *Deck) Review(q Question, grade int, now time.Time) Card {
+	c := d.Card(q).Review(grade, now)
+	d.Cards[q.Key()] = c
+	return c
+}
+
+// Due returns the questions, among those already seen, whose review is
+// due at now, most overdue first. New questions aren't included; take
+// them a day at a time.
+func (d *Deck) Due(questions []Question, now time.Time) []Question {
+	var due []Question
+	for _, q := range questions {
+		if c, ok := d.Cards[q.Key()]; ok && c.IsDue(now) {
+			due = append(due, q)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b Question) int {
+		return d.Cards[a.Key()].Due.Compare(d.Cards[b.Key()].Due)
+	})
+	return due
+}
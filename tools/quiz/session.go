@@ -0,0 +1,152 @@
+// quiz/session.go
+package quiz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Score is the result of a quiz.
+type Score struct {
+	Right int
+	Total int
+}
+
+func (s Score) Percent() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return 100 * float64(s.Right) / float64(s.Total)
+}
+
+// Session asks questions on a terminal and records the answers in a Deck.
+type Session struct {
+	In   *bufio.Reader
+	Out  io.Writer
+	Deck *Deck
+	Now  func() time.Time // Defaults to time.Now.
+}
+
+// NewSession returns a Session reading answers from in.
+func NewSession(in io.Reader, out io.Writer, deck *Deck) *Session {
+	return &Session{In: bufio.NewReader(in), Out: out, Deck: deck, Now: time.Now}
+}
+
+// Run asks each question in turn. A right answer is followed by a
+// question about how easy it was, which sets when it comes back; a wrong
+// one shows the answer. Run stops early, keeping what was answered, when
+// the input ends or the learner types "q".
+func (s *Session) Run(questions []Question) (Score, error) {
+	var score Score
+	for i, q := range questions {
+		fmt.Fprintf(s.Out, "\n[%d/%d] Day %d: %s\n", i+1, len(questions), q.Day, q.Prompt)
+		if q.Code != "" {
+			fmt.Fprintln(s.Out)
+			fmt.Fprintln(s.Out, indent(q.Code))
+			fmt.Fprintln(s.Out)
+		}
+		for j, c := range q.Choices {
+			fmt.Fprintf(s.Out, "  %c) %s\n", 'a'+j, c)
+		}
+
+		answer, err := s.ask(q)
+		if err == io.EOF || strings.TrimSpace(answer) == "q" {
+			break
+		}
+		if err != nil {
+			return score, err
+		}
+		score.Total++
+		grade := Forgot
+		if q.Check(answer) {
+			score.Right++
+			fmt.Fprintln(s.Out, "Right!")
+			if grade, err = s.askGrade(); err == io.EOF {
+				grade = Good
+			} else if err != nil {
+				return score, err
+			}
+		} else {
+			fmt.Fprintf(s.Out, "Not quite. The answer is:\n%s\n", indent(q.Answer))
+		}
+		if q.Explain != "" {
+			fmt.Fprintln(s.Out, strings.TrimSpace(q.Explain))
+		}
+		c := s.Deck.Review(q, grade, s.Now())
+		fmt.Fprintf(s.Out, "Next review: %s\n", c.Due.Format("Mon 2 Jan"))
+	}
+	return score, nil
+}
+
+// ask reads an answer. Output questions take several lines, ended by an
+// empty one; the others take one line.
+func (s *Session) ask(q Question) (string, error) {
+	switch q.Kind {
+	case Output:
+		fmt.Fprintln(s.Out, "Type the output, then an empty line:")
+		var lines []string
+		for {
+			line, err := s.readLine()
+			if err != nil && (err != io.EOF || len(lines) == 0) {
+				return "", err
+			}
+			if line == "" || err == io.EOF {
+				return strings.Join(lines, "\n"), nil
+			}
+			if line == "q" && len(lines) == 0 {
+				return line, nil
+			}
+			lines = append(lines, line)
+		}
+	case Choice:
+		fmt.Fprint(s.Out, "Your answer (letter): ")
+	default:
+		fmt.Fprintf(s.Out, "What goes in place of %s? ", Blank)
+	}
+	return s.readLine()
+}
+
+func (s *Session) askGrade() (int, error) {
+	for {
+		fmt.Fprintf(s.Out, "How easy was it? %d) hard  %d) good  %d) easy  [%d]: ", Hard, Good, Easy, Good)
+		line, err := s.readLine()
+		if err != nil {
+			return 0, err
+		}
+		switch strings.TrimSpace(line) {
+		case "":
+			return Good, nil
+		case "3":
+			return Hard, nil
+		case "4":
+			return Good, nil
+		case "5":
+			return Easy, nil
+		}
+	}
+}
+
+// readLine returns the next line without its line ending. At the end of
+// the input it returns what is left along with io.EOF.
+func (s *Session) readLine() (string, error) {
+	line, err := s.In.ReadString('\n')
+	line = strings.TrimRight(line, "\r\n")
+	if err == io.EOF && line != "" {
+		return line, nil
+	}
+	return line, err
+}
+
+// indent indents each non-empty line of text by four spaces.
+func indent(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = "    " + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
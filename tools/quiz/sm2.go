@@ -0,0 +1,71 @@
+// quiz/sm2.go
+package quiz
+
+import (
+	"math"
+	"time"
+)
+
+// Grades for Card.Review, from the SM-2 algorithm's 0-5 scale.
+const (
+	Forgot = 1 // Wrong answer.
+	Hard   = 3 // Right, with serious effort.
+	Good   = 4 // Right, after some thought.
+	Easy   = 5 // Right, straight away.
+)
+
+// minEase keeps hard cards from being shown every single day forever.
+const minEase = 1.3
+
+// Card is the review schedule of one question, kept with SuperMemo's SM-2
+// algorithm: every right answer pushes the next review further out, by a
+// factor that grows for easy questions and shrinks for hard ones, and a
+// wrong answer starts the card over.
+type Card struct {
+	Repetitions int       `json:"repetitions"` // Right answers in a row.
+	Interval    int       `json:"interval"`    // Days until the next review.
+	Ease        float64   `json:"ease"`
+	Due         time.Time `json:"due"`
+	Reviewed    time.Time `json:"reviewed"`
+	Reviews     int       `json:"reviews"`
+	Lapses      int       `json:"lapses"` // Wrong answers.
+}
+
+// NewCard returns the schedule of a question never reviewed.
+func NewCard() Card {
+	return Card{Ease: 2.5}
+}
+
+// Review returns the card rescheduled after an answer graded 0-5 at now.
+// Below 3 counts as wrong.
+func (c Card) Review(grade int, now time.Time) Card {
+	grade = min(max(grade, 0), 5)
+	if grade < Hard {
+		c.Repetitions, c.Interval = 0, 1
+		c.Lapses++
+	} else {
+		c.Repetitions++
+		switch c.Repetitions {
+		case 1:
+			c.Interval = 1
+		case 2:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+	}
+	miss := float64(5 - grade)
+	c.Ease = max(minEase, c.Ease+0.1-miss*(0.08+miss*0.02))
+
+	y, m, d := now.Date()
+	c.Due = time.Date(y, m, d+c.Interval, 0, 0, 0, 0, now.Location())
+	c.Reviewed = now
+	c.Reviews++
+	return c
+}
+
+// IsDue reports whether the card should be reviewed at now. A card never
+// reviewed is always due.
+func (c Card) IsDue(now time.Time) bool {
+	return !now.Before(c.Due)
+}
@@ -0,0 +1,105 @@
+// quiz/sm2_test.go
+package quiz_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"roadmaptools/quiz"
+)
+
+// now is mid-afternoon, so Due also checks that reviews land on midnight.
+var now = time.Date(2026, time.October, 19, 15, 30, 0, 0, time.UTC)
+
+func TestReviewIntervals(t *testing.T) {
+	c := quiz.NewCard()
+	// 1 day, 6 days, then the last interval times the ease, which Good
+	// leaves at 2.5: 15, then 37.5 rounded to 38.
+	for i, want := range []int{1, 6, 15, 38} {
+		c = c.Review(quiz.Good, now)
+		if c.Interval != want || c.Repetitions != i+1 || c.Ease != 2.5 {
+			t.Fatalf("review %d: Interval %d, Repetitions %d, Ease %g; want %d, %d, 2.5", i+1, c.Interval, c.Repetitions, c.Ease, want, i+1)
+		}
+		if due := time.Date(2026, time.October, 19+want, 0, 0, 0, 0, time.UTC); !c.Due.Equal(due) {
+			t.Errorf("review %d: Due = %v, want %v", i+1, c.Due, due)
+		}
+	}
+	if c.Reviews != 4 || c.Lapses != 0 || !c.Reviewed.Equal(now) {
+		t.Errorf("card = %+v", c)
+	}
+}
+
+func TestReviewEase(t *testing.T) {
+	tests := []struct {
+		grade int
+		ease  float64
+	}{
+		{quiz.Easy, 2.6},
+		{quiz.Good, 2.5},
+		{quiz.Hard, 2.36},
+		{2, 2.18},
+		{quiz.Forgot, 1.96},
+		{0, 1.7},
+		{-3, 1.7}, // Clamped to 0.
+		{9, 2.6},  // Clamped to 5.
+	}
+	for _, tt := range tests {
+		if got := quiz.NewCard().Review(tt.grade, now).Ease; math.Abs(got-tt.ease) > 1e-9 {
+			t.Errorf("grade %d: Ease = %g, want %g", tt.grade, got, tt.ease)
+		}
+	}
+
+	// Hard answers wear the ease down, but never below 1.3.
+	c := quiz.NewCard()
+	for range 10 {
+		c = c.Review(quiz.Hard, now)
+	}
+	if c.Ease != 1.3 {
+		t.Errorf("Ease after ten Hard reviews = %g, want the 1.3 floor", c.Ease)
+	}
+	c = quiz.NewCard()
+	for range 10 {
+		c = c.Review(0, now)
+	}
+	if c.Ease != 1.3 || c.Interval != 1 {
+		t.Errorf("after ten blackouts, Ease %g and Interval %d, want 1.3 and 1", c.Ease, c.Interval)
+	}
+}
+
+func TestReviewLapse(t *testing.T) {
+	c := quiz.NewCard()
+	for range 3 {
+		c = c.Review(quiz.Easy, now)
+	}
+	if c.Interval <= 6 {
+		t.Fatalf("Interval after three Easy reviews = %d, want more than 6", c.Interval)
+	}
+	c = c.Review(quiz.Forgot, now)
+	if c.Repetitions != 0 || c.Interval != 1 || c.Lapses != 1 || c.Reviews != 4 {
+		t.Errorf("after a lapse, card = %+v, want it started over", c)
+	}
+	if !c.IsDue(now.AddDate(0, 0, 1)) || c.IsDue(now) {
+		t.Errorf("Due = %v, want tomorrow", c.Due)
+	}
+	// The ease it earned is kept, less the lapse, and the card climbs
+	// back through 1 and 6 days.
+	if math.Abs(c.Ease-2.26) > 1e-9 {
+		t.Errorf("Ease after a lapse = %g, want 2.8 - 0.54 = 2.26", c.Ease)
+	}
+	for _, want := range []int{1, 6} {
+		if c = c.Review(quiz.Good, now); c.Interval != want {
+			t.Errorf("Interval = %d, want %d", c.Interval, want)
+		}
+	}
+}
+
+func TestIsDue(t *testing.T) {
+	if !quiz.NewCard().IsDue(now) {
+		t.Error("a new card is not due")
+	}
+	c := quiz.NewCard().Review(quiz.Good, now)
+	if c.IsDue(now) || !c.IsDue(c.Due) {
+		t.Errorf("Due = %v: want it due from then, and not before", c.Due)
+	}
+}
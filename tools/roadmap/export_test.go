@@ -0,0 +1,5 @@
+// roadmap/export_test.go
+package roadmap
+
+// Streaks lets the tests in roadmap_test reach streaks.
+var Streaks = streaks
@@ -0,0 +1,150 @@
+// roadmap/parse.go
+package roadmap // The 100-day plan from the root README, and a learner's progress through it
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoPhases   = errors.New("roadmap: no phases found")
+	ErrUnknownDay = errors.New("roadmap: no such day")
+)
+
+// Roadmap is the plan: phases made of topics, each topic covering one or
+// more days.
+type Roadmap struct {
+	Phases []Phase
+}
+
+// Phase is one "### Phase N" section of the README.
+type Phase struct {
+	Number   int
+	Title    string
+	FirstDay int
+	LastDay  int
+	Goal     string
+	Topics   []Topic
+}
+
+// Topic is one "**Day N**" or "**Day N-M**" entry.
+type Topic struct {
+	FirstDay  int
+	LastDay   int // Equal to FirstDay for a single day.
+	Title     string
+	Concept   string
+	Project   string
+	Resources string
+}
+
+// Days returns how many days the topic spans.
+func (t Topic) Days() int {
+	return t.LastDay - t.FirstDay + 1
+}
+
+// Label returns "Day 5" or "Day 6-7".
+func (t Topic) Label() string {
+	if t.FirstDay == t.LastDay {
+		return fmt.Sprintf("Day %d", t.FirstDay)
+	}
+	return fmt.Sprintf("Day %d-%d", t.FirstDay, t.LastDay)
+}
+
+var (
+	phaseRe = regexp.MustCompile(`^### Phase (\d+): (.+?) \(Days (\d+)-(\d+)\)\s*$`)
+	goalRe  = regexp.MustCompile(`^\*\*Goal:\*\*\s*(.+)$`)
+	topicRe = regexp.MustCompile(`^- \*\*Day (\d+)(?:-(\d+))?: (.+?)\*\*\s*$`)
+	fieldRe = regexp.MustCompile(`^\s+- \*\*(\w+):\*\*\s*(.+)$`)
+)
+
+// ParseFile parses the README at path.
+func ParseFile(path string) (*Roadmap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads the roadmap out of the README's markdown. It only looks at
+// the "### Phase" sections and the day entries under them; everything
+// else in the file is ignored.
+func Parse(r io.Reader) (*Roadmap, error) {
+	rm := &Roadmap{}
+	var phase *Phase
+	var topic *Topic
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		switch {
+		case strings.HasPrefix(text, "## "):
+			phase, topic = nil, nil // A new top-level section ends the roadmap.
+		case phaseRe.MatchString(text):
+			m := phaseRe.FindStringSubmatch(text)
+			rm.Phases = append(rm.Phases, Phase{Number: atoi(m[1]), Title: m[2], FirstDay: atoi(m[3]), LastDay: atoi(m[4])})
+			phase, topic = &rm.Phases[len(rm.Phases)-1], nil
+		case phase == nil:
+		case goalRe.MatchString(text):
+			phase.Goal = goalRe.FindStringSubmatch(text)[1]
+		case topicRe.MatchString(text):
+			m := topicRe.FindStringSubmatch(text)
+			t := Topic{FirstDay: atoi(m[1]), LastDay: atoi(m[1]), Title: m[3]}
+			if m[2] != "" {
+				t.LastDay = atoi(m[2])
+			}
+			if t.LastDay < t.FirstDay || t.FirstDay < phase.FirstDay || t.LastDay > phase.LastDay {
+				return nil, fmt.Errorf("roadmap: line %d: %s is outside phase %d (days %d-%d)",
+					line, t.Label(), phase.Number, phase.FirstDay, phase.LastDay)
+			}
+			phase.Topics = append(phase.Topics, t)
+			topic = &phase.Topics[len(phase.Topics)-1]
+		case topic != nil && fieldRe.MatchString(text):
+			m := fieldRe.FindStringSubmatch(text)
+			switch m[1] {
+			case "Concept":
+				topic.Concept = m[2]
+			case "Project":
+				topic.Project = m[2]
+			case "Resources":
+				topic.Resources = m[2]
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(rm.Phases) == 0 {
+		return nil, ErrNoPhases
+	}
+	return rm, nil
+}
+
+// atoi is only called on strings a regexp matched as digits.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// TotalDays returns the last day of the last phase.
+func (rm *Roadmap) TotalDays() int {
+	return rm.Phases[len(rm.Phases)-1].LastDay
+}
+
+// Topic returns the topic and phase that cover day.
+func (rm *Roadmap) Topic(day int) (Topic, Phase, error) {
+	for _, p := range rm.Phases {
+		for _, t := range p.Topics {
+			if day >= t.FirstDay && day <= t.LastDay {
+				return t, p, nil
+			}
+		}
+	}
+	return Topic{}, Phase{}, fmt.Errorf("%w: %d", ErrUnknownDay, day)
+}
@@ -0,0 +1,131 @@
+// roadmap/parse_test.go
+package roadmap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"roadmaptools/roadmap"
+)
+
+// The real plan, at the repository root.
+const readme = "../../README.MD"
+
+func TestParseReadme(t *testing.T) {
+	rm, err := roadmap.ParseFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ first, last int }{{1, 20}, {21, 50}, {51, 100}}
+	if len(rm.Phases) != len(want) {
+		t.Fatalf("%d phases, want %d", len(rm.Phases), len(want))
+	}
+	for i, p := range rm.Phases {
+		if p.Number != i+1 || p.FirstDay != want[i].first || p.LastDay != want[i].last || p.Goal == "" {
+			t.Errorf("phase %d = %d %q, days %d-%d, goal %q", i+1, p.Number, p.Title, p.FirstDay, p.LastDay, p.Goal)
+		}
+	}
+	if rm.TotalDays() != 100 {
+		t.Errorf("TotalDays = %d, want 100", rm.TotalDays())
+	}
+
+	// The topics cover every day exactly once, in order, and each has a
+	// concept and a project.
+	next := 1
+	for _, p := range rm.Phases {
+		for _, topic := range p.Topics {
+			if topic.FirstDay != next {
+				t.Errorf("%s follows day %d", topic.Label(), next-1)
+			}
+			if topic.Concept == "" || topic.Project == "" {
+				t.Errorf("%s: concept %q, project %q", topic.Label(), topic.Concept, topic.Project)
+			}
+			next = topic.LastDay + 1
+		}
+	}
+	if next != 101 {
+		t.Errorf("topics end at day %d, want 100", next-1)
+	}
+
+	day1, phase, err := rm.Topic(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if day1.Title != "Introduction to Go & Setup" || phase.Number != 1 ||
+		!strings.HasPrefix(day1.Concept, "What is Go") || !strings.HasPrefix(day1.Project, "Install Go.") ||
+		!strings.Contains(day1.Resources, "Tour of Go") {
+		t.Errorf("Topic(1) = %+v", day1)
+	}
+	day7, _, err := rm.Topic(7)
+	if err != nil || day7.Label() != "Day 6-7" || day7.Days() != 2 || day7.Title != "Packages and Modules" {
+		t.Errorf("Topic(7) = %+v, %v, want the Day 6-7 topic", day7, err)
+	}
+	if _, _, err := rm.Topic(101); !errors.Is(err, roadmap.ErrUnknownDay) {
+		t.Errorf("Topic(101) = %v, want ErrUnknownDay", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	const md = `# Title
+
+- **Day 0: Not in a phase**
+
+### Phase 1: Basics (Days 1-3)
+
+**Goal:** Learn things.
+
+- **Day 1: First**
+  - **Concept:** One.
+  - **Project:** Build one.
+  - **Unknown:** Ignored.
+- **Day 2-3: Second and third**
+  - **Concept:** Two.
+
+## Afterwards
+
+- **Day 4: After the roadmap**
+`
+	rm, err := roadmap.Parse(strings.NewReader(md))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rm.Phases) != 1 {
+		t.Fatalf("Phases = %+v, want one", rm.Phases)
+	}
+	p := rm.Phases[0]
+	if p.Title != "Basics" || p.Goal != "Learn things." || len(p.Topics) != 2 {
+		t.Fatalf("phase = %+v", p)
+	}
+	first, second := p.Topics[0], p.Topics[1]
+	if first != (roadmap.Topic{FirstDay: 1, LastDay: 1, Title: "First", Concept: "One.", Project: "Build one."}) {
+		t.Errorf("first topic = %+v", first)
+	}
+	if second.FirstDay != 2 || second.LastDay != 3 || second.Concept != "Two." || second.Project != "" {
+		t.Errorf("second topic = %+v", second)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string // In the error.
+	}{
+		{"past the phase", "### Phase 1: A (Days 1-3)\n- **Day 3-4: Too long**\n", "line 2: Day 3-4 is outside phase 1 (days 1-3)"},
+		{"before the phase", "### Phase 2: B (Days 5-9)\n\n- **Day 4: Early**\n", "line 3: Day 4 is outside phase 2 (days 5-9)"},
+		{"backwards", "### Phase 1: A (Days 1-9)\n- **Day 5-4: Backwards**\n", "Day 5-4 is outside phase 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := roadmap.Parse(strings.NewReader(tt.md))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := roadmap.Parse(strings.NewReader("# Nothing here\n")); !errors.Is(err, roadmap.ErrNoPhases) {
+		t.Errorf("Parse without phases = %v, want ErrNoPhases", err)
+	}
+}
@@ -0,0 +1,174 @@
+// roadmap/progress.go
+package roadmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Progress is what a learner has done, kept in a JSON file:
+//
+//	{"days": {"5": {"done": true, "completed_at": "...",
+//	                "sessions": [{"at": "...", "minutes": 45}],
+//	                "notes": [{"at": "...", "text": "defer is LIFO"}]}}}
+type Progress struct {
+	Days map[int]*DayLog `json:"days"`
+}
+
+// DayLog is the record of one day.
+type DayLog struct {
+	Done        bool      `json:"done"`
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	Sessions    []Session `json:"sessions,omitempty"`
+	Notes       []Note    `json:"notes,omitempty"`
+}
+
+// Session is time spent studying a day.
+type Session struct {
+	At      time.Time `json:"at"`
+	Minutes int       `json:"minutes"`
+}
+
+// Note is something the learner wrote down about a day.
+type Note struct {
+	At   time.Time `json:"at"`
+	Text string    `json:"text"`
+}
+
+// Minutes returns the total time logged for the day.
+func (d *DayLog) Minutes() int {
+	total := 0
+	for _, s := range d.Sessions {
+		total += s.Minutes
+	}
+	return total
+}
+
+// Load reads progress from path. A missing file is an empty Progress, so
+// the first run needs no setup.
+func Load(path string) (*Progress, error) {
+	p := &Progress{Days: make(map[int]*DayLog)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("roadmap: reading %s: %w", path, err)
+	}
+	if p.Days == nil {
+		p.Days = make(map[int]*DayLog)
+	}
+	return p, nil
+}
+
+// Save writes progress to path, creating its directory if needed. It
+// writes a temporary file and renames it over the old one, so a crash
+// can't leave half a file behind.
+func (p *Progress) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Day returns the log of a day, creating it if needed.
+func (p *Progress) Day(day int) *DayLog {
+	d, ok := p.Days[day]
+	if !ok {
+		d = &DayLog{}
+		p.Days[day] = d
+	}
+	return d
+}
+
+// Done reports whether day is marked done.
+func (p *Progress) Done(day int) bool {
+	d, ok := p.Days[day]
+	return ok && d.Done
+}
+
+// MarkDone marks day done at the given time. Marking it again keeps the
+// first completion time.
+func (p *Progress) MarkDone(day int, at time.Time) {
+	d := p.Day(day)
+	if !d.Done {
+		d.Done, d.CompletedAt = true, at
+	}
+}
+
+// MarkUndone clears a day's done mark but keeps its sessions and notes.
+func (p *Progress) MarkUndone(day int) {
+	if d, ok := p.Days[day]; ok {
+		d.Done, d.CompletedAt = false, time.Time{}
+	}
+}
+
+// LogTime records time spent on day, rounded to the minute.
+func (p *Progress) LogTime(day int, spent time.Duration, at time.Time) error {
+	minutes := int(spent.Round(time.Minute) / time.Minute)
+	if minutes <= 0 {
+		return fmt.Errorf("roadmap: log at least a minute, not %v", spent)
+	}
+	d := p.Day(day)
+	d.Sessions = append(d.Sessions, Session{At: at, Minutes: minutes})
+	return nil
+}
+
+// AddNote attaches a note to day.
+func (p *Progress) AddNote(day int, text string, at time.Time) error {
+	if text == "" {
+		return errors.New("roadmap: empty note")
+	}
+	d := p.Day(day)
+	d.Notes = append(d.Notes, Note{At: at, Text: text})
+	return nil
+}
+
+// activeDates returns the calendar dates, in loc, on which anything was
+// done, oldest first and without repeats.
+func (p *Progress) activeDates(loc *time.Location) []time.Time {
+	seen := make(map[time.Time]bool)
+	add := func(t time.Time) {
+		if !t.IsZero() {
+			seen[date(t, loc)] = true
+		}
+	}
+	for _, d := range p.Days {
+		add(d.CompletedAt)
+		for _, s := range d.Sessions {
+			add(s.At)
+		}
+		for _, n := range d.Notes {
+			add(n.At)
+		}
+	}
+	dates := make([]time.Time, 0, len(seen))
+	for t := range seen {
+		dates = append(dates, t)
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	return dates
+}
+
+// date truncates t to midnight in loc.
+func date(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,84 @@
+// roadmap/stats.go
+package roadmap
+
+import "time"
+
+// Stats summarizes Progress against a Roadmap.
+type Stats struct {
+	DaysDone  int
+	TotalDays int
+	Percent   float64
+	Minutes   int // All time logged.
+
+	Phases []PhaseStats
+
+	// CurrentStreak is how many days in a row, up to today, something was
+	// done. A streak that ended yesterday still counts until today is
+	// over, so it doesn't drop to zero first thing in the morning.
+	CurrentStreak int
+	LongestStreak int
+
+	// Next is the first day not yet done, or 0 if every day is.
+	Next int
+}
+
+// PhaseStats is the progress through one phase.
+type PhaseStats struct {
+	Phase    Phase
+	DaysDone int
+	Days     int
+	Percent  float64
+	Minutes  int
+}
+
+// Summarize computes Stats. Streaks are counted in now's time zone.
+func Summarize(rm *Roadmap, p *Progress, now time.Time) Stats {
+	s := Stats{TotalDays: rm.TotalDays()}
+	for _, ph := range rm.Phases {
+		ps := PhaseStats{Phase: ph, Days: ph.LastDay - ph.FirstDay + 1}
+		for day := ph.FirstDay; day <= ph.LastDay; day++ {
+			if p.Done(day) {
+				ps.DaysDone++
+			} else if s.Next == 0 {
+				s.Next = day
+			}
+			if d, ok := p.Days[day]; ok {
+				ps.Minutes += d.Minutes()
+			}
+		}
+		ps.Percent = percent(ps.DaysDone, ps.Days)
+		s.DaysDone += ps.DaysDone
+		s.Minutes += ps.Minutes
+		s.Phases = append(s.Phases, ps)
+	}
+	s.Percent = percent(s.DaysDone, s.TotalDays)
+	s.CurrentStreak, s.LongestStreak = streaks(p.activeDates(now.Location()), date(now, now.Location()))
+	return s
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// streaks finds runs of consecutive dates. dates must be sorted midnights.
+func streaks(dates []time.Time, today time.Time) (current, longest int) {
+	run := 0
+	for i, d := range dates {
+		if i > 0 && dates[i-1].AddDate(0, 0, 1).Equal(d) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	if n := len(dates); n > 0 {
+		last := dates[n-1]
+		if last.Equal(today) || last.AddDate(0, 0, 1).Equal(today) {
+			current = run
+		}
+	}
+	return current, longest
+}
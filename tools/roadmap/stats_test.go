@@ -0,0 +1,143 @@
+// roadmap/stats_test.go
+package roadmap_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"roadmaptools/roadmap"
+)
+
+// day returns midnight UTC on the given day of October 2026.
+func day(d int) time.Time {
+	return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStreaks(t *testing.T) {
+	tests := []struct {
+		name             string
+		dates            []time.Time
+		today            time.Time
+		current, longest int
+	}{
+		{"nothing done", nil, day(10), 0, 0},
+		{"today only", []time.Time{day(10)}, day(10), 1, 1},
+		{"run up to today", []time.Time{day(8), day(9), day(10)}, day(10), 3, 3},
+		{"run up to yesterday", []time.Time{day(8), day(9)}, day(10), 2, 2},
+		{"run ended the day before yesterday", []time.Time{day(7), day(8)}, day(10), 0, 2},
+		{"gap breaks the run", []time.Time{day(1), day(2), day(3), day(5), day(6)}, day(6), 2, 3},
+		{"longest is not the last", []time.Time{day(1), day(2), day(3), day(4), day(9)}, day(10), 1, 4},
+		{"across a month", []time.Time{day(0), day(1)}, day(1), 2, 2}, // day(0) is 30 September.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := roadmap.Streaks(tt.dates, tt.today)
+			if current != tt.current || longest != tt.longest {
+				t.Errorf("streaks = %d, %d, want %d, %d", current, longest, tt.current, tt.longest)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	rm, err := roadmap.ParseFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := day(10).Add(20 * time.Hour)
+	p, err := roadmap.Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	empty := roadmap.Summarize(rm, p, now)
+	if empty.DaysDone != 0 || empty.TotalDays != 100 || empty.Next != 1 || empty.CurrentStreak != 0 || len(empty.Phases) != 3 {
+		t.Errorf("Summarize with no progress = %+v", empty)
+	}
+
+	// Days 1 and 2 done on the 8th and 9th, day 21 on the 5th, a session
+	// on day 3 this morning, and a note on day 30 on the 4th.
+	p.MarkDone(1, day(8).Add(9*time.Hour))
+	p.MarkDone(2, day(9).Add(23*time.Hour))
+	p.MarkDone(21, day(5).Add(time.Hour))
+	if err := p.LogTime(1, 45*time.Minute, day(8)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.LogTime(3, 30*time.Minute, day(10).Add(8*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.LogTime(21, 90*time.Minute, day(5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddNote(30, "select blocks until a case is ready", day(4).Add(12*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := roadmap.Summarize(rm, p, now)
+	if s.DaysDone != 3 || s.Percent != 3 || s.Minutes != 165 || s.Next != 3 {
+		t.Errorf("Summarize = %d done (%g%%), %d minutes, next %d; want 3 (3%%), 165, 3", s.DaysDone, s.Percent, s.Minutes, s.Next)
+	}
+	want := []struct{ done, days, minutes int }{{2, 20, 75}, {1, 30, 90}, {0, 50, 0}}
+	for i, ps := range s.Phases {
+		if ps.DaysDone != want[i].done || ps.Days != want[i].days || ps.Minutes != want[i].minutes || ps.Phase.Number != i+1 {
+			t.Errorf("phase %d = %d/%d days, %d minutes; want %d/%d, %d", i+1, ps.DaysDone, ps.Days, ps.Minutes, want[i].done, want[i].days, want[i].minutes)
+		}
+	}
+	if got := s.Phases[0].Percent; got != 10 {
+		t.Errorf("phase 1 Percent = %g, want 10", got)
+	}
+	// Active on the 4th, 5th, 8th, 9th and 10th.
+	if s.CurrentStreak != 3 || s.LongestStreak != 3 {
+		t.Errorf("streaks = %d, %d, want 3, 3", s.CurrentStreak, s.LongestStreak)
+	}
+
+	// 23:00 UTC on the 9th is already the 10th four hours east, so that
+	// completion no longer fills the 9th and the streak breaks.
+	east := time.FixedZone("UTC+4", 4*60*60)
+	if s := roadmap.Summarize(rm, p, now.In(east)); s.CurrentStreak != 1 || s.LongestStreak != 2 {
+		t.Errorf("streaks at UTC+4 = %d, %d, want 1, 2", s.CurrentStreak, s.LongestStreak)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress", "days.json")
+	p, err := roadmap.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, later := day(1), day(2)
+	p.MarkDone(4, first)
+	p.MarkDone(4, later)
+	if got := p.Days[4].CompletedAt; !got.Equal(first) {
+		t.Errorf("CompletedAt = %v, want the first completion %v", got, first)
+	}
+	if err := p.LogTime(4, 29*time.Second, first); err == nil {
+		t.Error("LogTime accepted less than a minute")
+	}
+	if err := p.AddNote(4, "", first); err == nil {
+		t.Error("AddNote accepted an empty note")
+	}
+	if err := p.LogTime(4, 89*time.Second, first); err != nil || p.Days[4].Minutes() != 1 {
+		t.Errorf("LogTime(89s) = %v, %d minutes, want 1", err, p.Days[4].Minutes())
+	}
+	if err := p.AddNote(4, "maps are references", first); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = roadmap.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := p.Days[4]
+	if !p.Done(4) || !d.CompletedAt.Equal(first) || d.Minutes() != 1 || len(d.Notes) != 1 || d.Notes[0].Text != "maps are references" {
+		t.Errorf("reloaded day 4 = %+v", d)
+	}
+	p.MarkUndone(4)
+	if p.Done(4) || len(p.Days[4].Sessions) != 1 {
+		t.Errorf("after MarkUndone, day 4 = %+v, want it not done but its sessions kept", p.Days[4])
+	}
+}
@@ -0,0 +1,9 @@
+// sandbox/export_test.go
+package sandbox
+
+// Internals for the tests in sandbox_test.
+var (
+	ParseCompileErrors = parseCompileErrors
+	ParsePanic         = parsePanic
+	OutOfMemory        = outOfMemoryRe.MatchString
+)
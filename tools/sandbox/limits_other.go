@@ -0,0 +1,21 @@
+// sandbox/limits_other.go
+
+//go:build !unix
+
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// limitedCommand runs bin directly: without rlimits, only the wall-clock
+// limit and the GOMEMLIMIT soft limit apply.
+func limitedCommand(ctx context.Context, bin string, lim Limits) *exec.Cmd {
+	return exec.CommandContext(ctx, bin)
+}
+
+func hitCPULimit(ps *os.ProcessState, lim Limits) bool {
+	return false
+}
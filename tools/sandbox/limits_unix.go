@@ -0,0 +1,43 @@
+// sandbox/limits_unix.go
+
+//go:build unix
+
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// limitedCommand runs bin through sh, which sets the CPU and data-size
+// rlimits and then execs bin, so the limits apply to bin alone. RLIMIT_AS
+// would be the obvious memory limit, but the Go runtime reserves far more
+// address space than it uses and wouldn't start; RLIMIT_DATA only counts
+// memory actually mapped for writing.
+func limitedCommand(ctx context.Context, bin string, lim Limits) *exec.Cmd {
+	dataKB := lim.Memory >> 10
+	return exec.CommandContext(ctx, "/bin/sh", "-c", `ulimit -t "$1" && ulimit -d "$2" && exec "$0"`,
+		bin, strconv.FormatInt(cpuSeconds(lim), 10), strconv.FormatInt(dataKB, 10))
+}
+
+// cpuSeconds is the CPU limit rounded up to the whole seconds rlimits use.
+func cpuSeconds(lim Limits) int64 {
+	return int64((lim.CPU + time.Second - 1) / time.Second)
+}
+
+// hitCPULimit reports whether the kernel killed the process for using up
+// its CPU time: SIGXCPU at the soft limit, SIGKILL at the hard one. The
+// kernel checks the limit on its own clock, so the usage the process
+// reports can come out a little under it.
+func hitCPULimit(ps *os.ProcessState, lim Limits) bool {
+	ws, ok := ps.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return false
+	}
+	sig := ws.Signal()
+	return sig == syscall.SIGXCPU || (sig == syscall.SIGKILL && ps.UserTime()+ps.SystemTime() >= time.Duration(cpuSeconds(lim))*time.Second*9/10)
+}
@@ -0,0 +1,29 @@
+// sandbox/limits_unix_test.go
+
+//go:build unix
+
+package sandbox_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"roadmaptools/sandbox"
+)
+
+func TestRunCPULimit(t *testing.T) {
+	needsGo(t)
+	res := run(t, "for {\n}\n", sandbox.Limits{CPU: time.Second, Wall: 10 * time.Second})
+	if res.Status != sandbox.CPULimit || res.Duration > 5*time.Second {
+		t.Errorf("Run = %s after %v, want the CPU limit after about 1s", res.Status, res.Duration)
+	}
+}
+
+func TestRunMemoryLimit(t *testing.T) {
+	needsGo(t)
+	res := run(t, "var s [][]byte\nfor {\n\ts = append(s, make([]byte, 1<<20))\n}\n", sandbox.Limits{Memory: 64 << 20})
+	if res.Status != sandbox.MemoryLimit {
+		t.Errorf("Run = %s, exit %d, want the memory limit\n%s", res.Status, res.ExitCode, strings.SplitN(res.Stderr, "\n", 2)[0])
+	}
+}
@@ -0,0 +1,280 @@
+// sandbox/sandbox.go
+package sandbox // Compile and run Go snippets with time, CPU and memory limits
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits bound what a snippet may use. Zero fields take the defaults.
+//
+// The limits protect against mistakes, like an infinite loop or a slice
+// that grows forever, not against hostile code: the snippet runs as the
+// current user, with their files and network.
+type Limits struct {
+	Wall    time.Duration // Real time for the run. Defaults to 5s.
+	CPU     time.Duration // CPU time, rounded up to whole seconds. Defaults to 2s.
+	Memory  int64         // Bytes of heap. Defaults to 256 MiB.
+	Output  int           // Bytes of stdout, and of stderr, that are kept. Defaults to 64 KiB.
+	Compile time.Duration // Time to compile. Defaults to 60s.
+}
+
+// DefaultLimits are the limits used for zero fields.
+var DefaultLimits = Limits{
+	Wall:    5 * time.Second,
+	CPU:     2 * time.Second,
+	Memory:  256 << 20,
+	Output:  64 << 10,
+	Compile: 60 * time.Second,
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.Wall <= 0 {
+		l.Wall = DefaultLimits.Wall
+	}
+	if l.CPU <= 0 {
+		l.CPU = DefaultLimits.CPU
+	}
+	if l.Memory <= 0 {
+		l.Memory = DefaultLimits.Memory
+	}
+	if l.Output <= 0 {
+		l.Output = DefaultLimits.Output
+	}
+	if l.Compile <= 0 {
+		l.Compile = DefaultLimits.Compile
+	}
+	return l
+}
+
+// Status is how a run ended.
+type Status string
+
+const (
+	OK            Status = "ok"
+	CompileFailed Status = "compile_error"
+	RuntimeError  Status = "runtime_error" // Non-zero exit, such as a panic.
+	Timeout       Status = "timeout"
+	CPULimit      Status = "cpu_limit"
+	MemoryLimit   Status = "memory_limit"
+)
+
+// Result is everything a run produced.
+type Result struct {
+	Status        Status         `json:"status"`
+	Stdout        string         `json:"stdout"`
+	Stderr        string         `json:"stderr"`
+	Truncated     bool           `json:"truncated,omitempty"` // Output went over Limits.Output.
+	ExitCode      int            `json:"exit_code"`
+	Duration      time.Duration  `json:"duration_ns"`
+	CompileErrors []CompileError `json:"compile_errors,omitempty"`
+	Panic         *Panic         `json:"panic,omitempty"`
+	Source        string         `json:"source"` // The program that was compiled.
+}
+
+// CompileError is one error from the compiler. Line and Column are in the
+// snippet as given; Line is 0 for an error in code Wrap added, such as an
+// unused automatic import.
+type CompileError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e CompileError) String() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Panic is the panic that ended a run. Line is where in the snippet it
+// happened, or 0 if the trace doesn't go through the snippet.
+type Panic struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+}
+
+var (
+	compileErrRe = regexp.MustCompile(`^(\S+?):(\d+)(?::(\d+))?: (.*)$`)
+	panicRe      = regexp.MustCompile(`(?m)^panic: (.*)$`)
+	traceLineRe  = regexp.MustCompile(`(?m)^\s+\S*` + regexp.QuoteMeta(SnippetFile) + `:(\d+)`)
+
+	// The runtime reports running out of memory as "runtime: out of
+	// memory: ..." or as a fatal error, such as "fatal error: out of
+	// memory allocating heap arena metadata", depending on where it ran
+	// out.
+	outOfMemoryRe = regexp.MustCompile(`(?m)^(?:runtime: out of memory|fatal error: .*out of memory)`)
+)
+
+// Run wraps the snippet with Wrap, compiles it with the local go command
+// and runs it within lim. Problems with the snippet, from compile errors
+// to panics and exceeded limits, are reported in the Result; the error is
+// for problems running the toolchain itself.
+func Run(ctx context.Context, snippet string, lim Limits) (*Result, error) {
+	lim = lim.withDefaults()
+	dir, err := os.MkdirTemp("", "sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	res := &Result{Source: Wrap(snippet)}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(res.Source), 0o644); err != nil {
+		return nil, err
+	}
+	bin := filepath.Join(dir, "snippet")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	if ok, err := compile(ctx, dir, bin, lim, res); err != nil || !ok {
+		return res, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lim.Wall)
+	defer cancel()
+	cmd := limitedCommand(ctx, bin, lim)
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"HOME=" + dir,
+		"TMPDIR=" + dir,
+		// A soft limit a little under the hard one, so the garbage
+		// collector works harder before the program runs out.
+		"GOMEMLIMIT=" + strconv.FormatInt(lim.Memory/10*9, 10),
+	}
+	stdout, stderr := &cappedBuffer{max: lim.Output}, &cappedBuffer{max: lim.Output}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	runErr := cmd.Run()
+	res.Duration = time.Since(start)
+	res.Stdout, res.Stderr = stdout.String(), stderr.String()
+	res.Truncated = stdout.dropped || stderr.dropped
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		res.Status = Timeout
+	case runErr == nil:
+		res.Status = OK
+	case !errors.As(runErr, &exitErr):
+		return res, runErr
+	case hitCPULimit(cmd.ProcessState, lim):
+		res.Status = CPULimit
+	case outOfMemoryRe.MatchString(res.Stderr):
+		res.Status = MemoryLimit
+	default:
+		res.Status = RuntimeError
+		res.Panic = parsePanic(res.Stderr)
+	}
+	return res, nil
+}
+
+// compile builds the program. It returns false, with the errors in res,
+// if the snippet doesn't compile.
+func compile(ctx context.Context, dir, bin string, lim Limits, res *Result) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, lim.Compile)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", bin, "main.go")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOTOOLCHAIN=local", "GOFLAGS=")
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	if ctx.Err() != nil {
+		return false, fmt.Errorf("sandbox: compiling: %w", ctx.Err())
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false, fmt.Errorf("sandbox: running go: %w", err)
+	}
+	res.Status = CompileFailed
+	res.Stderr = out.String()
+	res.CompileErrors = parseCompileErrors(out.String())
+	return false, nil
+}
+
+// parseCompileErrors reads the go command's error lines. Positions in
+// SnippetFile are already snippet positions, thanks to the //line
+// directives Wrap adds.
+func parseCompileErrors(out string) []CompileError {
+	var errs []CompileError
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := compileErrRe.FindStringSubmatch(line)
+		if m == nil {
+			// A continuation of the previous message, or a note.
+			if n := len(errs); n > 0 {
+				errs[n-1].Message += "\n" + strings.TrimSpace(line)
+			} else {
+				errs = append(errs, CompileError{Message: strings.TrimSpace(line)})
+			}
+			continue
+		}
+		e := CompileError{Message: m[4]}
+		if filepath.Base(m[1]) == SnippetFile {
+			e.Line, _ = strconv.Atoi(m[2])
+			e.Column, _ = strconv.Atoi(m[3])
+		} else {
+			e.Message = "in generated code: " + e.Message
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+func parsePanic(stderr string) *Panic {
+	m := panicRe.FindStringSubmatch(stderr)
+	if m == nil {
+		return nil
+	}
+	p := &Panic{Message: m[1]}
+	// The first snippet frame after the panic message is where it
+	// happened; the runtime's own frames come from other files.
+	if t := traceLineRe.FindStringSubmatch(stderr[strings.Index(stderr, m[0]):]); t != nil {
+		p.Line, _ = strconv.Atoi(t[1])
+	}
+	return p
+}
+
+// cappedBuffer keeps the first max bytes written to it. It doesn't embed
+// bytes.Buffer: io.Copy would then use Buffer.ReadFrom and skip the cap.
+type cappedBuffer struct {
+	buf     bytes.Buffer
+	max     int
+	dropped bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.max - b.buf.Len()
+	if room < len(p) {
+		b.dropped = true
+	}
+	if room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
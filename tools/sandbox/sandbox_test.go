@@ -0,0 +1,188 @@
+// sandbox/sandbox_test.go
+package sandbox_test
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"testing"
+	"time"
+
+	"roadmaptools/sandbox"
+)
+
+func TestParseCompileErrors(t *testing.T) {
+	out := `# command-line-arguments
+./snippet.go:1:1: declared and not used: x
+./snippet.go:2:13: undefined: y
+/tmp/sandbox-1/main.go:5:2: "strings" imported and not used
+./snippet.go:7:2: cannot use s (variable of type string) as int value in assignment
+	have string
+./snippet.go:9: missing column
+`
+	want := []sandbox.CompileError{
+		{Line: 1, Column: 1, Message: "declared and not used: x"},
+		{Line: 2, Column: 13, Message: "undefined: y"},
+		{Message: `in generated code: "strings" imported and not used`},
+		{Line: 7, Column: 2, Message: "cannot use s (variable of type string) as int value in assignment\nhave string"},
+		{Line: 9, Message: "missing column"},
+	}
+	if got := sandbox.ParseCompileErrors(out); !slices.Equal(got, want) {
+		t.Errorf("parseCompileErrors =\n%q\nwant\n%q", got, want)
+	}
+
+	// A message that isn't about a position is kept whole.
+	got := sandbox.ParseCompileErrors("go: cannot find main module\n\tsee 'go help modules'\n")
+	if len(got) != 1 || got[0].Line != 0 || got[0].Message != "go: cannot find main module\nsee 'go help modules'" {
+		t.Errorf("parseCompileErrors = %q", got)
+	}
+	if got := sandbox.ParseCompileErrors(""); got != nil {
+		t.Errorf("parseCompileErrors of nothing = %q", got)
+	}
+}
+
+func TestParsePanic(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   *sandbox.Panic
+	}{
+		{"in the snippet", `panic: runtime error: index out of range [3] with length 1
+
+goroutine 1 [running]:
+main.main()
+	/tmp/sandbox-1/snippet.go:3 +0x9
+exit status 2
+`, &sandbox.Panic{Message: "runtime error: index out of range [3] with length 1", Line: 3}},
+		{"first snippet frame after runtime frames", `panic: boom
+
+goroutine 1 [running]:
+runtime.gopanic({0x4809ba?, 0x28e1?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.explode(...)
+	/tmp/sandbox-1/snippet.go:12
+main.main()
+	/tmp/sandbox-1/snippet.go:4 +0x1d
+`, &sandbox.Panic{Message: "boom", Line: 12}},
+		{"frame printed before the panic", `	/tmp/sandbox-1/snippet.go:99
+panic: late
+`, &sandbox.Panic{Message: "late"}},
+		{"no snippet frame", "panic: from the runtime\n\tgoroutine 1 [running]:\n\t/usr/local/go/src/runtime/proc.go:1 +0x1\n",
+			&sandbox.Panic{Message: "from the runtime"}},
+		{"no panic", "exit status 1\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sandbox.ParsePanic(tt.stderr)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("parsePanic = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutOfMemory(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"runtime: out of memory: cannot allocate 4194304-byte block (67108864 in use)\nfatal error: out of memory\n", true},
+		{"fatal error: runtime: out of memory\n\nruntime stack:\n", true},
+		{"fatal error: out of memory allocating heap arena metadata\n", true},
+		{"fatal error: all goroutines are asleep - deadlock!\n", false},
+		{"panic: out of memory\n", false}, // The snippet's own panic.
+		{"hello\nsomeone said fatal error: out of memory\n", false},
+	}
+	for _, tt := range tests {
+		if got := sandbox.OutOfMemory(tt.stderr); got != tt.want {
+			t.Errorf("out of memory in %q = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+// needsGo skips tests that compile and run snippets when they can't, or
+// shouldn't, in -short mode.
+func needsGo(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("compiles and runs snippets")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("needs the go command:", err)
+	}
+}
+
+func run(t *testing.T, snippet string, lim sandbox.Limits) *sandbox.Result {
+	t.Helper()
+	res, err := sandbox.Run(context.Background(), snippet, lim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestRun(t *testing.T) {
+	needsGo(t)
+	res := run(t, `fmt.Println(strings.Repeat("ab", 2))`, sandbox.Limits{})
+	if res.Status != sandbox.OK || res.Stdout != "abab\n" || res.ExitCode != 0 {
+		t.Errorf("Run = %s, exit %d, stdout %q, stderr %q", res.Status, res.ExitCode, res.Stdout, res.Stderr)
+	}
+
+	res = run(t, "os.Exit(3)", sandbox.Limits{})
+	if res.Status != sandbox.RuntimeError || res.ExitCode != 3 || res.Panic != nil {
+		t.Errorf("os.Exit(3): %s, exit %d, panic %+v", res.Status, res.ExitCode, res.Panic)
+	}
+
+	res = run(t, `for range 1000 { fmt.Println("a line of output") }`, sandbox.Limits{Output: 100})
+	if res.Status != sandbox.OK || len(res.Stdout) != 100 || !res.Truncated {
+		t.Errorf("long output: %s, %d bytes kept, truncated %v", res.Status, len(res.Stdout), res.Truncated)
+	}
+}
+
+// TestRunLines checks that compile errors and panics point at the
+// snippet's own lines, whatever Wrap added around it.
+func TestRunLines(t *testing.T) {
+	needsGo(t)
+	tests := []struct {
+		name      string
+		snippet   string
+		wantLines []int
+		panicLine int
+	}{
+		{"whole file", "package main\n\nfunc main() {\n\tx := 1\n}\n", []int{4}, 0},
+		{"statements", "a := 1\nb := 2\n_ = a", []int{2}, 0},
+		{"statements with imports", "import \"fmt\"\n\nx := 1\nfmt.Println(y)\n", []int{3, 4}, 0},
+		{"declarations", "type T int\n\nfunc main() {\n\tvar t T = \"x\"\n\t_ = t\n}\n", []int{4}, 0},
+		{"automatic imports", "x := 1\nfmt.Println()\nvar s []int\n_ = s[x]\n_ = strings.Builder{}", nil, 4},
+		{"panic in statements", "s := []int{1}\ni := 3\nfmt.Println(\ns[i])", nil, 4},
+		{"panic in a function", "import \"errors\"\n\nfunc fail() {\n\tpanic(errors.New(\"boom\"))\n}\n\nfunc main() {\n\tfail()\n}\n", nil, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res := run(t, tt.snippet, sandbox.Limits{})
+			if tt.panicLine != 0 {
+				if res.Status != sandbox.RuntimeError || res.Panic == nil || res.Panic.Line != tt.panicLine {
+					t.Errorf("Run = %s with panic %+v, want one on line %d\n%s", res.Status, res.Panic, tt.panicLine, res.Stderr)
+				}
+				return
+			}
+			var lines []int
+			for _, e := range res.CompileErrors {
+				lines = append(lines, e.Line)
+			}
+			if res.Status != sandbox.CompileFailed || !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("Run = %s with errors %q, want them on lines %v", res.Status, res.CompileErrors, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	needsGo(t)
+	// Asleep, so it uses no CPU: only the wall clock stops it.
+	res := run(t, "time.Sleep(time.Hour)\n", sandbox.Limits{Wall: 200 * time.Millisecond})
+	if res.Status != sandbox.Timeout || res.Duration > 2*time.Second {
+		t.Errorf("Run = %s after %v, want a timeout after 200ms\n%s", res.Status, res.Duration, res.Stderr)
+	}
+}
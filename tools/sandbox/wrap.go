@@ -0,0 +1,160 @@
+// sandbox/wrap.go
+package sandbox
+
+import (
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SnippetFile is the name compile errors and stack traces use for the
+// snippet, whatever was wrapped around it.
+const SnippetFile = "snippet.go"
+
+// stdImports are the packages a snippet may use without importing them.
+// Keys are the names code refers to them by.
+var stdImports = map[string]string{
+	"bufio":   "bufio",
+	"bytes":   "bytes",
+	"context": "context",
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"io":      "io",
+	"maps":    "maps",
+	"math":    "math",
+	"os":      "os",
+	"rand":    "math/rand/v2",
+	"slices":  "slices",
+	"sort":    "sort",
+	"strconv": "strconv",
+	"strings": "strings",
+	"sync":    "sync",
+	"time":    "time",
+	"unicode": "unicode",
+	"utf8":    "unicode/utf8",
+}
+
+var mainFuncRe = regexp.MustCompile(`(?m)^func\s+main\s*\(`)
+
+// Wrap turns a snippet into a complete program. It accepts three shapes:
+//
+//   - a whole file, with its package clause, used as is;
+//   - declarations without a package clause, such as types and a func main;
+//   - bare statements, which become the body of func main. Import lines
+//     at the top stay outside it.
+//
+// A snippet without imports of its own gets imports for the common
+// standard packages it refers to, such as fmt and strings. The snippet's
+// lines are marked with //line directives, so compile errors and stack
+// traces point at SnippetFile with the snippet's own line numbers.
+func Wrap(snippet string) string {
+	snippet = strings.ReplaceAll(snippet, "\r\n", "\n")
+	if hasPackageClause(snippet) {
+		return lineDirective(1) + snippet
+	}
+
+	var b strings.Builder
+	b.WriteString("package main\n\n")
+	imports, body, bodyLine := splitImports(snippet)
+	if imports == "" {
+		if names := usedPackages(body); len(names) > 0 {
+			b.WriteString("import (\n")
+			for _, name := range names {
+				b.WriteString("\t\"" + stdImports[name] + "\"\n")
+			}
+			b.WriteString(")\n\n")
+		}
+	} else {
+		b.WriteString(lineDirective(1) + imports)
+		if !strings.HasSuffix(imports, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	body = strings.TrimRight(body, "\n") + "\n"
+	if isDeclarations(body) {
+		b.WriteString(lineDirective(bodyLine) + body)
+		return b.String()
+	}
+	b.WriteString("func main() {\n")
+	b.WriteString(lineDirective(bodyLine) + body)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// lineDirective makes the compiler count the next line as line n, column
+// 1, of SnippetFile.
+func lineDirective(n int) string {
+	return "//line " + SnippetFile + ":" + strconv.Itoa(n) + ":1\n"
+}
+
+func hasPackageClause(src string) bool {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+	return err == nil && f.Name != nil
+}
+
+// splitImports cuts the import declarations off the top of a snippet. It
+// returns them, the rest, and the snippet line the rest starts on.
+func splitImports(snippet string) (imports, body string, bodyLine int) {
+	const header = "package main\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", header+snippet, parser.ImportsOnly)
+	if err != nil || len(f.Imports) == 0 {
+		return "", snippet, 1
+	}
+	// ImportsOnly stops parsing after the imports, so the last
+	// declaration is the last import.
+	end := fset.Position(f.Decls[len(f.Decls)-1].End()).Offset - len(header)
+	if nl := strings.IndexByte(snippet[end:], '\n'); nl >= 0 && strings.TrimSpace(snippet[end:end+nl]) == "" {
+		end += nl + 1 // Keep the rest of the last import's line with it.
+	}
+	imports, body = snippet[:end], snippet[end:]
+	return imports, body, strings.Count(imports, "\n") + 1
+}
+
+// isDeclarations reports whether src is top-level declarations rather
+// than statements. Code with a syntax error counts as declarations if it
+// has a func main, so the compiler's message is about the code as written.
+func isDeclarations(src string) bool {
+	_, err := parser.ParseFile(token.NewFileSet(), "", "package main\n"+src, 0)
+	return err == nil || mainFuncRe.MatchString(src)
+}
+
+// usedPackages returns, sorted, the names in stdImports that src uses as
+// qualifiers, like the fmt in fmt.Println. Comments and strings are
+// skipped.
+func usedPackages(src string) []string {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, []byte(src), nil, 0)
+
+	seen := make(map[string]bool)
+	var prev string
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.PERIOD && prev != "" {
+			if _, ok := stdImports[prev]; ok {
+				seen[prev] = true
+			}
+		}
+		prev = ""
+		if tok == token.IDENT {
+			prev = lit
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
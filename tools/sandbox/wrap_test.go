@@ -0,0 +1,70 @@
+// sandbox/wrap_test.go
+package sandbox_test
+
+import (
+	"testing"
+
+	"roadmaptools/sandbox"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{
+			"whole file",
+			"package main\n\nfunc main() {}\n",
+			"//line snippet.go:1:1\npackage main\n\nfunc main() {}\n",
+		},
+		{
+			"statements",
+			"x := 1\nprintln(x)",
+			"package main\n\nfunc main() {\n//line snippet.go:1:1\nx := 1\nprintln(x)\n}\n",
+		},
+		{
+			"statements with automatic imports",
+			`fmt.Println(strings.ToUpper("hi"))`,
+			"package main\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n\nfunc main() {\n//line snippet.go:1:1\nfmt.Println(strings.ToUpper(\"hi\"))\n}\n",
+		},
+		{
+			"no imports for comments and strings",
+			"// fmt.Println\ns := \"os.Exit\"\n_ = s",
+			"package main\n\nfunc main() {\n//line snippet.go:1:1\n// fmt.Println\ns := \"os.Exit\"\n_ = s\n}\n",
+		},
+		{
+			"statements with imports",
+			"import \"fmt\"\n\nfmt.Println(1)\n",
+			"package main\n\n//line snippet.go:1:1\nimport \"fmt\"\n\nfunc main() {\n//line snippet.go:2:1\n\nfmt.Println(1)\n}\n",
+		},
+		{
+			"declarations",
+			"type T struct{}\n\nfunc main() {\n\tfmt.Println(rand.N(3), utf8.RuneLen('x'))\n}\n",
+			"package main\n\nimport (\n\t\"fmt\"\n\t\"math/rand/v2\"\n\t\"unicode/utf8\"\n)\n\n" +
+				"//line snippet.go:1:1\ntype T struct{}\n\nfunc main() {\n\tfmt.Println(rand.N(3), utf8.RuneLen('x'))\n}\n",
+		},
+		{
+			"declarations with imports",
+			"import (\n\t\"fmt\"\n)\ntype T int\n\nfunc main() { fmt.Println(T(1)) }",
+			"package main\n\n//line snippet.go:1:1\nimport (\n\t\"fmt\"\n)\n\n//line snippet.go:4:1\ntype T int\n\nfunc main() { fmt.Println(T(1)) }\n",
+		},
+		{
+			"declarations with a syntax error",
+			"func main() {\n\tx :=\n}",
+			"package main\n\n//line snippet.go:1:1\nfunc main() {\n\tx :=\n}\n",
+		},
+		{
+			"CRLF line endings",
+			"x := 1\r\nprintln(x)\r\n",
+			"package main\n\nfunc main() {\n//line snippet.go:1:1\nx := 1\nprintln(x)\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sandbox.Wrap(tt.snippet); got != tt.want {
+				t.Errorf("Wrap(%q) =\n%s\nwant\n%s", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}